@@ -270,6 +270,8 @@ type UpdateOptions struct {
 	AutoApprove bool
 	// SkipPreview, when true, causes the preview step to be skipped.
 	SkipPreview bool
+	// PreviewOnly, when true, causes the operation to stop after the preview step.
+	PreviewOnly bool
 }
 
 // QueryOptions configures a query to operate against a backend and the engine.
@@ -306,18 +308,34 @@ type backendClient struct {
 
 // GetStackOutputs returns the outputs of the stack with the given name.
 func (c *backendClient) GetStackOutputs(ctx context.Context, name string) (resource.PropertyMap, error) {
-	ref, err := c.backend.ParseStackReference(name)
+	res, err := c.getRootStackResource(ctx, name)
 	if err != nil {
 		return nil, err
 	}
-	s, err := c.backend.GetStack(ctx, ref)
+	if res == nil {
+		return resource.PropertyMap{}, nil
+	}
+	return res.Outputs, nil
+}
+
+// GetStackOutputsSchema returns the declared types of the outputs of the stack with the given name, if any.
+func (c *backendClient) GetStackOutputsSchema(
+	ctx context.Context, name string,
+) (map[string]workspace.ProjectOutputType, error) {
+	snap, err := c.getSnapshot(ctx, name)
 	if err != nil {
 		return nil, err
 	}
-	if s == nil {
-		return nil, fmt.Errorf("unknown stack %q", name)
+	if snap == nil {
+		return nil, nil
 	}
-	snap, err := s.Snapshot(ctx, c.secretsProvider)
+	return snap.Manifest.OutputsSchema, nil
+}
+
+// getRootStackResource returns the root stack resource of the latest snapshot of the stack with the given name, or
+// nil if the stack has no root stack resource.
+func (c *backendClient) getRootStackResource(ctx context.Context, name string) (*resource.State, error) {
+	snap, err := c.getSnapshot(ctx, name)
 	if err != nil {
 		return nil, err
 	}
@@ -325,10 +343,23 @@ func (c *backendClient) GetStackOutputs(ctx context.Context, name string) (resou
 	if err != nil {
 		return nil, fmt.Errorf("getting root stack resources: %w", err)
 	}
-	if res == nil {
-		return resource.PropertyMap{}, nil
+	return res, nil
+}
+
+// getSnapshot returns the latest snapshot of the stack with the given name.
+func (c *backendClient) getSnapshot(ctx context.Context, name string) (*deploy.Snapshot, error) {
+	ref, err := c.backend.ParseStackReference(name)
+	if err != nil {
+		return nil, err
 	}
-	return res.Outputs, nil
+	s, err := c.backend.GetStack(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("unknown stack %q", name)
+	}
+	return s.Snapshot(ctx, c.secretsProvider)
 }
 
 func (c *backendClient) GetStackResourceOutputs(
@@ -0,0 +1,77 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// ShowBlastRadius renders the blast radius of a preview to stdout. If opts.JSONDisplay is set, the report is rendered
+// as JSON.
+func ShowBlastRadius(report engine.BlastRadiusReport, opts Options) {
+	stdout := opts.Stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+
+	if opts.JSONDisplay {
+		out, err := json.MarshalIndent(&report, "", "    ")
+		contract.Assertf(err == nil, "unexpected JSON error: %v", err)
+		fprintIgnoreError(stdout, string(out)+"\n")
+		return
+	}
+
+	renderBlastRadius(stdout, report, opts)
+}
+
+func renderBlastRadius(out io.Writer, report engine.BlastRadiusReport, opts Options) {
+	fprintIgnoreError(out, "\n")
+	if len(report.Changes) == 0 {
+		fprintIgnoreError(out, opts.Color.Colorize(
+			colors.SpecInfo+"No resources will be replaced or deleted"+colors.Reset+"\n"))
+		return
+	}
+
+	protected := func(p bool) string {
+		if !p {
+			return ""
+		}
+		return " " + colors.SpecWarning + "[protected]" + colors.Reset
+	}
+
+	fprintIgnoreError(out, opts.Color.Colorize(colors.SpecHeadline+"Blast radius:"+colors.Reset+"\n"))
+	for _, change := range report.Changes {
+		fprintIgnoreError(out, opts.Color.Colorize(fmt.Sprintf("    %s%s (%s) affects %d resources%s%s\n",
+			deploy.Prefix(change.Op, true), change.URN, change.Op, len(change.Affected), colors.Reset,
+			protected(change.Protected))))
+		for _, res := range change.Affected {
+			fprintIgnoreError(out, opts.Color.Colorize(fmt.Sprintf("        %s%s (%s)%s%s\n",
+				deploy.Prefix(res.Op, true), res.URN, res.Op, colors.Reset, protected(res.Protected))))
+		}
+		if len(change.ProtectedChain) != 0 {
+			fprintIgnoreError(out, opts.Color.Colorize(fmt.Sprintf(
+				"        %s%d protected resources are in the chain%s\n",
+				colors.SpecWarning, len(change.ProtectedChain), colors.Reset)))
+		}
+	}
+}
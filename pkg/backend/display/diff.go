@@ -220,6 +220,16 @@ func renderSummaryEvent(event engine.SummaryEventPayload, wroteDiagnosticHeader
 		fprintfIgnoreError(out, "\n")
 	}
 
+	// If the deployment continued on errors, list all of the resource operations that failed.
+	if len(event.Failures) > 0 {
+		fprintIgnoreError(out, opts.Color.Colorize(
+			fmt.Sprintf("\n%sFailures:%s\n", colors.SpecHeadline, colors.Reset)))
+		for _, f := range event.Failures {
+			fprintIgnoreError(out, opts.Color.Colorize(
+				fmt.Sprintf("    %s%s %s:%s %s\n", colors.SpecError, f.Op, f.URN, colors.Reset, f.Message)))
+		}
+	}
+
 	// Print policy packs loaded. Data is rendered as a table of {policy-pack-name, version}.
 	renderPolicyPacks(out, event.PolicyPacks, opts)
 
@@ -67,6 +67,12 @@ func ShowEvents(
 		events, done = startEventLogger(events, done, opts)
 	}
 
+	// Drift reports have their own JSON format, so they must be handled before the generic JSON display.
+	if opts.Type == DisplayDrift {
+		ShowDriftEvents(events, done, opts)
+		return
+	}
+
 	streamPreview := cmdutil.IsTruthy(os.Getenv("PULUMI_ENABLE_STREAMING_JSON_PREVIEW"))
 
 	if opts.JSONDisplay {
@@ -0,0 +1,107 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// ShowDriftEvents accumulates the results of a refresh preview into a drift report, which is rendered once the event
+// stream is closed, and stored in opts.DriftReport if it is set. If opts.JSONDisplay is set, the report is rendered as
+// JSON. Errors and warnings are written to stderr as they arrive.
+func ShowDriftEvents(events <-chan engine.Event, done chan<- bool, opts Options) {
+	// Ensure we close the done channel before exiting.
+	defer func() { close(done) }()
+
+	stdout := opts.Stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	stderr := opts.Stderr
+	if stderr == nil {
+		stderr = os.Stderr
+	}
+
+	var report engine.DriftReport
+	for e := range events {
+		// In the event of cancellation, break out of the loop immediately.
+		if e.Type == engine.CancelEvent {
+			break
+		}
+
+		switch e.Type {
+		case engine.ResourceOutputsEvent:
+			report.RecordRefresh(e.Payload().(engine.ResourceOutputsEventPayload))
+		case engine.DiagEvent:
+			p := e.Payload().(engine.DiagEventPayload)
+			if !p.Ephemeral && (p.Severity == diag.Error || p.Severity == diag.Warning) {
+				fprintIgnoreError(stderr, opts.Color.Colorize(p.Prefix+p.Message))
+			}
+		}
+	}
+	report.Sort()
+	if opts.DriftReport != nil {
+		*opts.DriftReport = report
+	}
+
+	if opts.JSONDisplay {
+		out, err := json.MarshalIndent(&report, "", "    ")
+		contract.Assertf(err == nil, "unexpected JSON error: %v", err)
+		fprintIgnoreError(stdout, string(out)+"\n")
+		return
+	}
+
+	renderDriftReport(stdout, report, opts)
+}
+
+func renderDriftReport(out io.Writer, report engine.DriftReport, opts Options) {
+	if !report.HasDrift() {
+		fprintIgnoreError(out, opts.Color.Colorize(colors.SpecInfo+"No drift detected"+colors.Reset+"\n"))
+		return
+	}
+
+	fprintIgnoreError(out, opts.Color.Colorize(
+		fmt.Sprintf("%sDrift detected in %d resources:%s\n", colors.SpecHeadline, len(report.Resources), colors.Reset)))
+	for _, res := range report.Resources {
+		if res.Deleted {
+			fprintIgnoreError(out, opts.Color.Colorize(
+				fmt.Sprintf("    %s- %s (deleted)%s\n", colors.SpecDelete, res.URN, colors.Reset)))
+			continue
+		}
+
+		fprintIgnoreError(out, opts.Color.Colorize(
+			fmt.Sprintf("    %s~ %s%s\n", colors.SpecUpdate, res.URN, colors.Reset)))
+		for _, path := range res.Added {
+			fprintIgnoreError(out, opts.Color.Colorize(
+				fmt.Sprintf("        %s+ %s%s\n", colors.SpecCreate, path, colors.Reset)))
+		}
+		for _, path := range res.Removed {
+			fprintIgnoreError(out, opts.Color.Colorize(
+				fmt.Sprintf("        %s- %s%s\n", colors.SpecDelete, path, colors.Reset)))
+		}
+		for _, path := range res.Changed {
+			fprintIgnoreError(out, opts.Color.Colorize(
+				fmt.Sprintf("        %s~ %s%s\n", colors.SpecUpdate, path, colors.Reset)))
+		}
+	}
+}
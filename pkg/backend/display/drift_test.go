@@ -0,0 +1,55 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+)
+
+func TestShowDriftEventsReport(t *testing.T) {
+	t.Parallel()
+
+	showDrift := func(events ...engine.Event) (string, engine.DriftReport) {
+		var stdout bytes.Buffer
+		var report engine.DriftReport
+		eventsChannel, done := make(chan engine.Event, len(events)), make(chan bool)
+		for _, e := range events {
+			eventsChannel <- e
+		}
+		close(eventsChannel)
+		ShowDriftEvents(eventsChannel, done, Options{Color: colors.Never, Stdout: &stdout, DriftReport: &report})
+		<-done
+		return stdout.String(), report
+	}
+
+	// Refreshes without drift, e.g. of resources whose outputs didn't change, aren't reported.
+	out, report := showDrift(engine.NewEvent(engine.ResourceOutputsEvent, engine.ResourceOutputsEventPayload{}))
+	assert.Equal(t, "No drift detected\n", out)
+	assert.False(t, report.HasDrift())
+
+	// The report that is rendered is the one that is returned.
+	drift := engine.ResourceDrift{URN: "urn:pulumi:dev::proj::pkgA:m:typA::resA", Changed: []string{"size"}}
+	out, report = showDrift(engine.NewEvent(engine.ResourceOutputsEvent, engine.ResourceOutputsEventPayload{
+		Drift: &drift,
+	}))
+	assert.Contains(t, out, "Drift detected in 1 resources")
+	assert.Equal(t, engine.DriftReport{Resources: []engine.ResourceDrift{drift}}, report)
+}
@@ -105,11 +105,21 @@ func ConvertEngineEvent(e engine.Event, showSecrets bool) (apitype.EngineEvent,
 		for op, count := range p.ResourceChanges {
 			changes[apitype.OpType(op)] = count
 		}
+		// Convert the failures.
+		var failures []apitype.ResourceFailure
+		for _, f := range p.Failures {
+			failures = append(failures, apitype.ResourceFailure{
+				URN:     string(f.URN),
+				Op:      apitype.OpType(f.Op),
+				Message: f.Message,
+			})
+		}
 		apiEvent.SummaryEvent = &apitype.SummaryEvent{
 			MaybeCorrupt:    p.MaybeCorrupt,
 			DurationSeconds: int(p.Duration.Seconds()),
 			ResourceChanges: changes,
 			PolicyPacks:     p.PolicyPacks,
+			Failures:        failures,
 		}
 
 	case engine.ResourcePreEvent:
@@ -295,11 +305,20 @@ func ConvertJSONEvent(apiEvent apitype.EngineEvent) (engine.Event, error) {
 		for op, count := range p.ResourceChanges {
 			changes[display.StepOp(op)] = count
 		}
+		var failures []engine.ResourceFailure
+		for _, f := range p.Failures {
+			failures = append(failures, engine.ResourceFailure{
+				URN:     resource.URN(f.URN),
+				Op:      display.StepOp(f.Op),
+				Message: f.Message,
+			})
+		}
 		event = engine.NewEvent(engine.SummaryEvent, engine.SummaryEventPayload{
 			MaybeCorrupt:    p.MaybeCorrupt,
 			Duration:        time.Duration(p.DurationSeconds) * time.Second,
 			ResourceChanges: changes,
 			PolicyPacks:     p.PolicyPacks,
+			Failures:        failures,
 		})
 
 	case apiEvent.ResourcePreEvent != nil:
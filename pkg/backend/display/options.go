@@ -18,6 +18,7 @@ import (
 	"io"
 
 	"github.com/pulumi/pulumi/pkg/v3/backend/display/internal/terminal"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 )
 
@@ -33,6 +34,8 @@ const (
 	DisplayQuery
 	// DisplayWatch displays watch output.
 	DisplayWatch
+	// DisplayDrift displays a drift report for a refresh preview.
+	DisplayDrift
 )
 
 // Options controls how the output of events are rendered
@@ -55,6 +58,7 @@ type Options struct {
 	Stdout               io.Writer           // the writer to use for stdout. Defaults to os.Stdout if unset.
 	Stderr               io.Writer           // the writer to use for stderr. Defaults to os.Stderr if unset.
 	SuppressTimings      bool                // true to suppress displaying timings of resource actions
+	DriftReport          *engine.DriftReport // if set, receives the report rendered by a drift display.
 
 	// testing-only options
 	term                terminal.Terminal
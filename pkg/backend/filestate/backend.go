@@ -24,6 +24,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -88,6 +89,18 @@ var (
 	//
 	// This opt-out is intended to be removed in a future release.
 	PulumiFilestateLegacyLayoutEnvVar = env.SelfManagedStateLegacyLayout.Var().Name()
+
+	// PulumiFilestateLockTTLEnvVar is the name of an environment variable
+	// that can be set to the number of seconds for which locks on stacks are leased.
+	PulumiFilestateLockTTLEnvVar = env.SelfManagedStateLockTTL.Var().Name()
+
+	// PulumiFilestateJournalEnvVar is the name of an environment variable
+	// that can be set to journal the steps of updates instead of rewriting the checkpoint after every step.
+	PulumiFilestateJournalEnvVar = env.SelfManagedStateJournal.Var().Name()
+
+	// PulumiFilestateJournalCompactionIntervalEnvVar is the name of an environment variable
+	// that can be set to the number of journal entries after which the journal is compacted into a checkpoint.
+	PulumiFilestateJournalCompactionIntervalEnvVar = env.SelfManagedStateJournalCompactionInterval.Var().Name()
 )
 
 // Backend extends the base backend interface with specific information about local backends.
@@ -97,6 +110,9 @@ type Backend interface {
 
 	// Upgrade to the latest state store version.
 	Upgrade(ctx context.Context) error
+
+	// ListLocks returns the locks that are currently held on the given stack.
+	ListLocks(ctx context.Context, stackRef backend.StackReference) ([]LockInfo, error)
 }
 
 type localBackend struct {
@@ -113,6 +129,10 @@ type localBackend struct {
 
 	lockID string
 
+	// leases tracks the heartbeats renewing the locks held by this backend, keyed by lock path.
+	leases     map[string]*lockLease
+	leasesLock sync.Mutex
+
 	gzip bool
 
 	Getenv func(string) string // == os.Getenv
@@ -127,6 +147,8 @@ type localBackend struct {
 	store referenceStore
 }
 
+var _ backend.SpecificDeploymentExporter = &localBackend{}
+
 type localBackendReference struct {
 	name    tokens.Name
 	project tokens.Name
@@ -183,6 +205,7 @@ func (r *localBackendReference) Validate() error       { return r.store.Validate
 func (r *localBackendReference) StackBasePath() string { return r.store.StackBasePath(r) }
 func (r *localBackendReference) HistoryDir() string    { return r.store.HistoryDir(r) }
 func (r *localBackendReference) BackupDir() string     { return r.store.BackupDir(r) }
+func (r *localBackendReference) JournalDir() string    { return r.store.JournalDir(r) }
 
 func IsFileStateBackendURL(urlstr string) bool {
 	u, err := url.Parse(urlstr)
@@ -778,6 +801,10 @@ func (b *localBackend) renameStack(ctx context.Context, oldRef *localBackendRefe
 	}
 	defer b.Unlock(ctx, oldRef)
 
+	if err := b.recoverJournal(ctx, oldRef); err != nil {
+		return err
+	}
+
 	// Get the current state from the stack to be renamed.
 	snap, _, err := b.getStack(ctx, oldRef)
 	if err != nil {
@@ -933,6 +960,13 @@ func (b *localBackend) apply(
 			colors.SpecHeadline+"%s (%s):"+colors.Reset+"\n"), actionLabel, stackRef)
 	}
 
+	// Recover the state left behind by a journaled update that didn't finish before starting a new one.
+	if !opts.DryRun {
+		if err := b.recoverJournal(ctx, localStackRef); err != nil {
+			return nil, nil, result.FromError(err)
+		}
+	}
+
 	// Start the update.
 	update, err := b.newUpdate(ctx, localStackRef, op)
 	if err != nil {
@@ -965,11 +999,26 @@ func (b *localBackend) apply(
 		close(eventsDone)
 	}()
 
-	// Create the management machinery.
-	persister := b.newSnapshotPersister(ctx, localStackRef, op.SecretsManager)
-	manager := backend.NewSnapshotManager(persister, update.GetTarget().Snapshot)
+	// Create the management machinery. Refreshes rewrite the base snapshot in memory, which can't be journaled, so
+	// they always save the whole checkpoint.
+	var manager engine.SnapshotManager
+	if b.journalEnabled() && !opts.DryRun && kind != apitype.RefreshUpdate && !op.Opts.Engine.Refresh {
+		manager, err = b.newJournalSnapshotManager(
+			ctx, localStackRef, op.SecretsManager, update.GetTarget().Snapshot, op.Proj.Outputs)
+		if err != nil {
+			return nil, nil, result.FromError(err)
+		}
+	} else {
+		persister := b.newSnapshotPersister(ctx, localStackRef, op.SecretsManager)
+		snapshotManager := backend.NewSnapshotManager(persister, update.GetTarget().Snapshot)
+		snapshotManager.SetOutputsSchema(op.Proj.Outputs)
+		manager = snapshotManager
+	}
+	// Losing the lock on the stack to another process cancels the update.
+	cancelCtx, stopCancel := b.cancelOnLostLock(stackRef, scope.Context())
+	defer stopCancel()
 	engineCtx := &engine.Context{
-		Cancel:          scope.Context(),
+		Cancel:          cancelCtx,
 		Events:          engineEvents,
 		SnapshotManager: manager,
 		BackendClient:   backend.NewBackendClient(b, op.SecretsProvider),
@@ -1000,12 +1049,18 @@ func (b *localBackend) apply(
 	<-displayDone
 	scope.Close() // Don't take any cancellations anymore, we're shutting down.
 	close(engineEvents)
-	contract.IgnoreClose(manager)
+	// Closing the snapshot manager saves the final checkpoint, e.g. by compacting the journal.
+	closeErr := manager.Close()
 
 	// Make sure the goroutine writing to displayEvents and events has exited before proceeding.
 	<-eventsDone
 	close(displayEvents)
 
+	// A checkpoint that couldn't be saved fails the update.
+	if updateRes == nil && closeErr != nil {
+		updateRes = result.FromError(fmt.Errorf("saving checkpoint: %w", closeErr))
+	}
+
 	// Save update results.
 	backendUpdateResult := backend.SucceededResult
 	if updateRes != nil {
@@ -1170,6 +1225,37 @@ func (b *localBackend) ExportDeployment(ctx context.Context,
 	}, nil
 }
 
+// ExportDeploymentForVersion exports the deployment that was saved at the end of the given update of the stack.
+// Updates are numbered from 1, as reported by `pulumi stack history`.
+func (b *localBackend) ExportDeploymentForVersion(ctx context.Context,
+	stk backend.Stack, version string,
+) (*apitype.UntypedDeployment, error) {
+	localStackRef, err := b.getReference(stk.Ref())
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q: %w", version, err)
+	}
+
+	chk, err := b.getHistoryCheckpoint(ctx, localStackRef, v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	data, err := encoding.JSON.Marshal(chk.Latest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apitype.UntypedDeployment{
+		Version:    3,
+		Deployment: json.RawMessage(data),
+	}, nil
+}
+
 func (b *localBackend) ImportDeployment(ctx context.Context, stk backend.Stack,
 	deployment *apitype.UntypedDeployment,
 ) error {
@@ -1184,6 +1270,12 @@ func (b *localBackend) ImportDeployment(ctx context.Context, stk backend.Stack,
 	}
 	defer b.Unlock(ctx, localStackRef)
 
+	// Recover the state left behind by a journaled update that didn't finish, so that it is backed up like any other
+	// checkpoint before the imported deployment replaces it.
+	if err := b.recoverJournal(ctx, localStackRef); err != nil {
+		return err
+	}
+
 	stackName := localStackRef.FullyQualifiedName()
 	chk, err := stack.MarshalUntypedDeploymentToVersionedCheckpoint(stackName, deployment)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -412,6 +413,56 @@ func TestRenameWorks(t *testing.T) {
 	assert.Equal(t, apitype.DestroyUpdate, history[0].Kind)
 }
 
+func TestExportDeploymentForVersion(t *testing.T) {
+	t.Parallel()
+
+	// Login to a temp dir filestate backend
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	b, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+	lb := b.(*localBackend)
+
+	stackRef, err := lb.parseStackReference("organization/project/a")
+	require.NoError(t, err)
+	s, err := b.CreateStack(ctx, stackRef, "", nil)
+	require.NoError(t, err)
+
+	// Record two updates, each with a different number of resources.
+	for i := 1; i <= 2; i++ {
+		var resources []apitype.ResourceV3
+		for j := 0; j < i; j++ {
+			resources = append(resources, apitype.ResourceV3{
+				URN:  resource.NewURN("a", "project", "", "a:b:c", tokens.QName(fmt.Sprintf("res%d", j))),
+				Type: "a:b:c",
+			})
+		}
+		data, err := json.Marshal(apitype.DeploymentV3{Resources: resources})
+		require.NoError(t, err)
+		err = b.ImportDeployment(ctx, s, &apitype.UntypedDeployment{Version: 3, Deployment: data})
+		require.NoError(t, err)
+		err = lb.addToHistory(ctx, stackRef, backend.UpdateInfo{Kind: apitype.UpdateUpdate})
+		require.NoError(t, err)
+	}
+
+	history, err := b.GetHistory(ctx, stackRef, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, 2, history[0].Version)
+	assert.Equal(t, 1, history[1].Version)
+
+	for _, version := range []int{1, 2} {
+		deployment, err := lb.ExportDeploymentForVersion(ctx, s, strconv.Itoa(version))
+		require.NoError(t, err)
+		var dep apitype.DeploymentV3
+		require.NoError(t, json.Unmarshal(deployment.Deployment, &dep))
+		assert.Len(t, dep.Resources, version)
+	}
+
+	_, err = lb.ExportDeploymentForVersion(ctx, s, "3")
+	assert.ErrorContains(t, err, "version 3 of stack organization/project/a does not exist")
+}
+
 func TestLoginToNonExistingFolderFails(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,638 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gocloud.dev/gcerrors"
+
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/version"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// In journal mode, rather than rewriting the whole checkpoint of a stack after every step of an update, the
+// filestate backend appends each engine.JournalEntry to a journal, and periodically compacts the journal into a full
+// checkpoint. Since blobs can't be appended to, the journal is a directory with one file per entry.
+//
+// The journal is split into epochs. Each epoch starts with a base snapshot, which is written to
+// <epoch>.base.json, and is followed by entries that are written to <epoch>.<sequence>.entry.json. Compacting the
+// journal saves the checkpoint of the stack, then starts a new epoch whose base is that checkpoint and deletes the
+// previous epochs. If the CLI crashes during an update, the next operation on the stack replays the latest epoch to
+// recover the state of the stack.
+
+const (
+	journalBaseSuffix  = ".base.json"
+	journalEntrySuffix = ".entry.json"
+
+	// defaultJournalCompactionInterval is the default number of journal entries after which the journal is compacted.
+	defaultJournalCompactionInterval = 1000
+)
+
+// journalRecord is the persisted form of an engine.JournalEntry.
+type journalRecord struct {
+	Kind engine.JournalEntryKind `json:"kind"`
+	Op   display.StepOp          `json:"op"`
+	URN  resource.URN            `json:"urn"`
+	Old  *journalState           `json:"old,omitempty"`
+	New  *journalState           `json:"new,omitempty"`
+}
+
+// journalState is a resource state that is referred to by a journal entry. States are identified by an ID that is
+// unique within an epoch: the states of the base snapshot are identified by their index, and states that are created
+// during the epoch get the following IDs. Since the engine mutates states in place, every entry records the latest
+// value of the states that it refers to.
+type journalState struct {
+	ID    int                `json:"id"`
+	State apitype.ResourceV3 `json:"state"`
+}
+
+// journalEnabled returns true if updates should be journaled, as controlled by PULUMI_SELF_MANAGED_STATE_JOURNAL.
+func (b *localBackend) journalEnabled() bool {
+	return cmdutil.IsTruthy(b.Getenv(PulumiFilestateJournalEnvVar))
+}
+
+// journalCompactionInterval returns the number of entries after which the journal is compacted, which can be
+// overridden with PULUMI_SELF_MANAGED_STATE_JOURNAL_COMPACTION_INTERVAL.
+func (b *localBackend) journalCompactionInterval() int {
+	if v := b.Getenv(PulumiFilestateJournalCompactionIntervalEnvVar); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+		b.d.Warningf(diag.Message("", "ignoring invalid value %q for %s"),
+			v, PulumiFilestateJournalCompactionIntervalEnvVar)
+	}
+	return defaultJournalCompactionInterval
+}
+
+// journalSnapshotManager is an engine.SnapshotManager that journals the steps of an update.
+type journalSnapshotManager struct {
+	// TODO[pulumi/pulumi#12593]:
+	// Remove this once SnapshotManager is updated to take a context.
+	ctx context.Context
+
+	ref     *localBackendReference
+	backend *localBackend
+	sm      secrets.Manager
+	enc     config.Encrypter
+
+	compactionInterval int
+
+	// outputsSchema holds the types that the project declares for the stack's outputs, which are recorded in the
+	// manifest of each checkpoint.
+	outputsSchema map[string]workspace.ProjectOutputType
+
+	mu       sync.Mutex
+	epoch    int64                   // the current epoch.
+	base     *deploy.Snapshot        // the base snapshot of the current epoch.
+	entries  engine.JournalEntries   // the entries of the current epoch.
+	ids      map[*resource.State]int // the IDs of the states known to the current epoch.
+	inflight map[deploy.Step]bool    // the steps that have begun but not ended yet.
+	err      error                   // the first error that occurred while writing the journal, if any.
+}
+
+var _ engine.SnapshotManager = (*journalSnapshotManager)(nil)
+
+func (b *localBackend) newJournalSnapshotManager(
+	ctx context.Context,
+	ref *localBackendReference,
+	sm secrets.Manager,
+	base *deploy.Snapshot,
+	outputsSchema map[string]workspace.ProjectOutputType,
+) (*journalSnapshotManager, error) {
+	var enc config.Encrypter = config.NewPanicCrypter()
+	if sm != nil {
+		e, err := sm.Encrypter()
+		if err != nil {
+			return nil, fmt.Errorf("getting encrypter for journal: %w", err)
+		}
+		enc = e
+	}
+
+	jm := &journalSnapshotManager{
+		ctx:                ctx,
+		ref:                ref,
+		backend:            b,
+		sm:                 sm,
+		enc:                enc,
+		compactionInterval: b.journalCompactionInterval(),
+		outputsSchema:      outputsSchema,
+		inflight:           make(map[deploy.Step]bool),
+	}
+	if err := jm.startEpoch(base, nil); err != nil {
+		return nil, err
+	}
+	return jm, nil
+}
+
+type journalSnapshotMutation struct {
+	manager *journalSnapshotManager
+}
+
+func (m *journalSnapshotMutation) End(step deploy.Step, successful bool) error {
+	kind := engine.JournalEntryFailure
+	if successful {
+		kind = engine.JournalEntrySuccess
+	}
+	return m.manager.record(kind, step)
+}
+
+func (jm *journalSnapshotManager) BeginMutation(step deploy.Step) (engine.SnapshotMutation, error) {
+	if err := jm.record(engine.JournalEntryBegin, step); err != nil {
+		return nil, err
+	}
+	return &journalSnapshotMutation{jm}, nil
+}
+
+func (jm *journalSnapshotManager) RegisterResourceOutputs(step deploy.Step) error {
+	return jm.record(engine.JournalEntryOutputs, step)
+}
+
+// Close compacts the journal into the checkpoint of the stack and deletes it.
+func (jm *journalSnapshotManager) Close() error {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	if jm.err != nil {
+		// Leave the journal in place so that the next operation on the stack can recover from it.
+		return jm.err
+	}
+
+	if _, err := jm.save(); err != nil {
+		return err
+	}
+	return removeAllByPrefix(jm.ctx, jm.backend.bucket, jm.ref.JournalDir())
+}
+
+// record appends an entry for the given step to the journal, compacting the journal if it has grown too large.
+func (jm *journalSnapshotManager) record(kind engine.JournalEntryKind, step deploy.Step) error {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	if jm.err != nil {
+		return jm.err
+	}
+
+	switch kind {
+	case engine.JournalEntryBegin:
+		jm.inflight[step] = true
+	case engine.JournalEntrySuccess, engine.JournalEntryFailure:
+		delete(jm.inflight, step)
+	}
+
+	if err := jm.writeEntry(kind, step); err != nil {
+		jm.err = fmt.Errorf("writing journal entry: %w", err)
+		return jm.err
+	}
+
+	if len(jm.entries) >= jm.compactionInterval {
+		if err := jm.compact(); err != nil {
+			jm.err = fmt.Errorf("compacting journal: %w", err)
+			return jm.err
+		}
+	}
+	return nil
+}
+
+// writeEntry persists an entry for the given step and appends it to the in-memory journal. The in-memory entry
+// refers to the canonical states of the epoch, so that it replays exactly like the persisted entry.
+func (jm *journalSnapshotManager) writeEntry(kind engine.JournalEntryKind, step deploy.Step) error {
+	old, oldState, err := jm.resolve(step.Old(), true)
+	if err != nil {
+		return err
+	}
+	new, newState, err := jm.resolve(step.New(), false)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(journalRecord{
+		Kind: kind,
+		Op:   step.Op(),
+		URN:  step.URN(),
+		Old:  oldState,
+		New:  newState,
+	})
+	if err != nil {
+		return err
+	}
+
+	key := jm.entryKey(len(jm.entries))
+	if err := jm.backend.bucket.WriteAll(jm.ctx, key, data, nil); err != nil {
+		return err
+	}
+
+	jm.entries = append(jm.entries, engine.JournalEntry{
+		Kind: kind,
+		Step: engine.NewJournalStep(step.Op(), step.URN(), old, new),
+	})
+	return nil
+}
+
+// resolve returns the canonical state for the given state in the current epoch, along with its persisted form.
+func (jm *journalSnapshotManager) resolve(
+	state *resource.State, old bool,
+) (*resource.State, *journalState, error) {
+	if state == nil {
+		return nil, nil, nil
+	}
+
+	id, ok := jm.ids[state]
+	if !ok && old && jm.base != nil {
+		// Normalizing URN references when compacting the journal may have copied states of the base snapshot.
+		// Fall back to the base state for the same resource, and make the copy its canonical state.
+		for i, res := range jm.base.Resources {
+			if res.URN == state.URN && res.Delete == state.Delete {
+				jm.replaceBaseState(i, state)
+				id, ok = i, true
+				jm.ids[state] = id
+				break
+			}
+		}
+	}
+	if !ok {
+		id = len(jm.ids)
+		jm.ids[state] = id
+	}
+	// The canonical state of a resource of the base snapshot is the one in the base, which may have been replaced.
+	if jm.base != nil && id < len(jm.base.Resources) {
+		state = jm.base.Resources[id]
+	}
+
+	sres, err := stack.SerializeResource(state, jm.enc, false /* showSecrets */)
+	if err != nil {
+		return nil, nil, err
+	}
+	return state, &journalState{ID: id, State: sres}, nil
+}
+
+// replaceBaseState replaces the state at the given index of the base snapshot of the epoch. The base is copied
+// rather than modified in place, since it's shared with the snapshot that the epoch started from.
+func (jm *journalSnapshotManager) replaceBaseState(i int, state *resource.State) {
+	base := *jm.base
+	base.Resources = make([]*resource.State, len(jm.base.Resources))
+	copy(base.Resources, jm.base.Resources)
+	base.Resources[i] = state
+	jm.base = &base
+}
+
+// compact saves the checkpoint of the stack and starts a new epoch based on it.
+func (jm *journalSnapshotManager) compact() error {
+	snap, err := jm.save()
+	if err != nil {
+		return err
+	}
+
+	oldEpoch := jm.epoch
+	if err := jm.startEpoch(snap, jm.inflightOps()); err != nil {
+		return err
+	}
+
+	// Steps that are still in flight must be ended in the new epoch, so begin them again.
+	steps := make([]deploy.Step, 0, len(jm.inflight))
+	for step := range jm.inflight {
+		steps = append(steps, step)
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].URN() < steps[j].URN() })
+	for _, step := range steps {
+		if err := jm.writeEntry(engine.JournalEntryBegin, step); err != nil {
+			return err
+		}
+	}
+
+	return jm.backend.removeJournalEpochsBefore(jm.ctx, jm.ref, oldEpoch+1)
+}
+
+// inflightOps returns the states that have pending operations because of the steps that are in flight.
+func (jm *journalSnapshotManager) inflightOps() map[*resource.State]bool {
+	states := make(map[*resource.State]bool)
+	for step := range jm.inflight {
+		if old := step.Old(); old != nil {
+			states[old] = true
+		}
+		if new := step.New(); new != nil {
+			states[new] = true
+		}
+	}
+	return states
+}
+
+// save replays the current epoch and saves the result as the checkpoint of the stack.
+func (jm *journalSnapshotManager) save() (*deploy.Snapshot, error) {
+	snap, err := jm.entries.Snap(jm.base)
+	if err != nil {
+		return nil, fmt.Errorf("replaying journal: %w", err)
+	}
+	snap.SecretsManager = jm.sm
+	snap.Manifest = deploy.Manifest{
+		Time:          time.Now(),
+		Version:       version.Version,
+		OutputsSchema: jm.outputsSchema,
+	}
+	snap.Manifest.Magic = snap.Manifest.NewMagic()
+
+	if _, err := jm.backend.saveStack(jm.ctx, jm.ref, snap, jm.sm); err != nil {
+		return nil, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return snap, nil
+}
+
+// startEpoch starts a new epoch of the journal with the given base snapshot. Pending operations on the given states
+// are left out of the base, since they are recorded again by the new epoch.
+func (jm *journalSnapshotManager) startEpoch(base *deploy.Snapshot, skipOps map[*resource.State]bool) error {
+	jm.epoch = time.Now().UnixNano()
+	jm.entries = nil
+	jm.ids = make(map[*resource.State]int)
+	jm.base = base
+
+	persisted := base
+	if base == nil {
+		persisted = deploy.NewSnapshot(deploy.Manifest{}, jm.sm, nil, nil)
+	} else {
+		for i, res := range base.Resources {
+			jm.ids[res] = i
+		}
+
+		if len(skipOps) != 0 {
+			trimmed := *base
+			trimmed.PendingOperations = nil
+			for _, op := range base.PendingOperations {
+				if !skipOps[op.Resource] {
+					trimmed.PendingOperations = append(trimmed.PendingOperations, op)
+				}
+			}
+			jm.base, persisted = &trimmed, &trimmed
+		}
+	}
+
+	chk, err := stack.SerializeCheckpoint(jm.ref.FullyQualifiedName(), persisted, jm.sm, false /* showSecrets */)
+	if err != nil {
+		return fmt.Errorf("serializing journal base: %w", err)
+	}
+	m := encoding.JSON
+	if jm.backend.gzip {
+		m = encoding.Gzip(m)
+	}
+	data, err := m.Marshal(chk)
+	if err != nil {
+		return err
+	}
+	return jm.backend.bucket.WriteAll(jm.ctx, jm.baseKey(), data, nil)
+}
+
+func (jm *journalSnapshotManager) baseKey() string {
+	return path.Join(jm.ref.JournalDir(), fmt.Sprintf("%020d%s", jm.epoch, journalBaseSuffix))
+}
+
+func (jm *journalSnapshotManager) entryKey(seq int) string {
+	return path.Join(jm.ref.JournalDir(), fmt.Sprintf("%020d.%010d%s", jm.epoch, seq, journalEntrySuffix))
+}
+
+// parseJournalKey parses the epoch of a journal file. ok is false if the file is not part of a journal.
+func parseJournalKey(key string) (epoch int64, base bool, ok bool) {
+	name := path.Base(key)
+	switch {
+	case strings.HasSuffix(name, journalBaseSuffix):
+		base = true
+		name = strings.TrimSuffix(name, journalBaseSuffix)
+	case strings.HasSuffix(name, journalEntrySuffix):
+		name = strings.TrimSuffix(name, journalEntrySuffix)
+		dot := strings.IndexByte(name, '.')
+		if dot == -1 {
+			return 0, false, false
+		}
+		name = name[:dot]
+	default:
+		return 0, false, false
+	}
+
+	epoch, err := strconv.ParseInt(name, 10, 64)
+	if err != nil {
+		return 0, false, false
+	}
+	return epoch, base, true
+}
+
+// removeJournalEpochsBefore deletes the files of all journal epochs older than the given one.
+func (b *localBackend) removeJournalEpochsBefore(ctx context.Context, ref *localBackendReference, epoch int64) error {
+	files, err := listBucket(ctx, b.bucket, ref.JournalDir())
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil
+		}
+		return err
+	}
+	for _, file := range files {
+		if e, _, ok := parseJournalKey(file.Key); ok && e < epoch {
+			if err := b.bucket.Delete(ctx, file.Key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pendingJournal returns the keys of the base snapshot and of the entries of the journal that was left behind by an
+// update that is still running or that didn't finish. baseKey is empty if there is no such journal; found is true if
+// there are journal files nonetheless.
+func (b *localBackend) pendingJournal(ctx context.Context, ref *localBackendReference,
+) (baseKey string, entryKeys []string, found bool, err error) {
+	files, err := listBucket(ctx, b.bucket, ref.JournalDir())
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return "", nil, false, nil
+		}
+		return "", nil, false, err
+	}
+
+	// Find the latest epoch that has a base snapshot. Its entries are listed in order, since their names are
+	// zero-padded.
+	epoch, hasBase := int64(0), false
+	for _, file := range files {
+		if e, base, ok := parseJournalKey(file.Key); ok && base && (!hasBase || e > epoch) {
+			epoch, hasBase = e, true
+		}
+	}
+	if !hasBase {
+		return "", nil, len(files) != 0, nil
+	}
+
+	for _, file := range files {
+		if e, base, ok := parseJournalKey(file.Key); ok && e == epoch {
+			if base {
+				baseKey = file.Key
+			} else {
+				entryKeys = append(entryKeys, file.Key)
+			}
+		}
+	}
+	return baseKey, entryKeys, true, nil
+}
+
+// replayPendingJournal rebuilds the snapshot of a stack from the journal that was left behind by an update that is
+// still running or that didn't finish. It returns nil if there is no such journal.
+func (b *localBackend) replayPendingJournal(ctx context.Context, ref *localBackendReference) (*deploy.Snapshot, error) {
+	baseKey, entryKeys, _, err := b.pendingJournal(ctx, ref)
+	if err != nil || baseKey == "" {
+		return nil, err
+	}
+	snap, err := b.replayJournal(ctx, baseKey, entryKeys)
+	if err != nil {
+		return nil, fmt.Errorf("replaying the journal of stack %s: %w", ref, err)
+	}
+	return snap, nil
+}
+
+// recoverJournal replays the journal that was left behind by an update that didn't finish, e.g. because the CLI
+// crashed, and saves the result as the checkpoint of the stack. The stack must be locked.
+func (b *localBackend) recoverJournal(ctx context.Context, ref *localBackendReference) error {
+	baseKey, entryKeys, found, err := b.pendingJournal(ctx, ref)
+	if err != nil || !found {
+		return err
+	}
+	if baseKey == "" {
+		return removeAllByPrefix(ctx, b.bucket, ref.JournalDir())
+	}
+
+	snap, err := b.replayJournal(ctx, baseKey, entryKeys)
+	if err != nil {
+		return fmt.Errorf("recovering the journal of stack %s: %w", ref, err)
+	}
+	if _, err := b.saveStack(ctx, ref, snap, snap.SecretsManager); err != nil {
+		return err
+	}
+
+	b.d.Warningf(diag.Message("", "recovered %d journal entries of an update of stack %s that did not finish"),
+		len(entryKeys), ref)
+	return removeAllByPrefix(ctx, b.bucket, ref.JournalDir())
+}
+
+// replayJournal rebuilds the snapshot of a stack from the base snapshot and entries of a journal epoch.
+func (b *localBackend) replayJournal(ctx context.Context, baseKey string, entryKeys []string) (*deploy.Snapshot, error) {
+	bytes, err := b.bucket.ReadAll(ctx, baseKey)
+	if err != nil {
+		return nil, err
+	}
+	m := encoding.JSON
+	if encoding.IsCompressed(bytes) {
+		m = encoding.Gzip(m)
+	}
+	chk, err := stack.UnmarshalVersionedCheckpointToLatestCheckpoint(m, bytes)
+	if err != nil {
+		return nil, err
+	}
+	base, err := stack.DeserializeCheckpoint(ctx, stack.DefaultSecretsProvider, chk)
+	if err != nil {
+		return nil, err
+	}
+
+	var dec config.Decrypter = config.NewPanicCrypter()
+	var enc config.Encrypter = config.NewPanicCrypter()
+	if base.SecretsManager != nil {
+		if dec, err = base.SecretsManager.Decrypter(); err != nil {
+			return nil, err
+		}
+		if enc, err = base.SecretsManager.Encrypter(); err != nil {
+			return nil, err
+		}
+	}
+
+	states := make(map[int]*resource.State)
+	for i, res := range base.Resources {
+		states[i] = res
+	}
+	resolve := func(js *journalState) (*resource.State, error) {
+		if js == nil {
+			return nil, nil
+		}
+		state, err := stack.DeserializeResource(js.State, dec, enc)
+		if err != nil {
+			return nil, err
+		}
+		// Update the canonical state in place, just like the engine did.
+		if existing, ok := states[js.ID]; ok {
+			*existing = *state
+			return existing, nil
+		}
+		states[js.ID] = state
+		return state, nil
+	}
+
+	var entries engine.JournalEntries
+	for _, key := range entryKeys {
+		data, err := b.bucket.ReadAll(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		var record journalRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			// The last entry may have been cut short by the crash.
+			if key == entryKeys[len(entryKeys)-1] {
+				logging.V(5).Infof("ignoring truncated journal entry %s: %v", key, err)
+				break
+			}
+			return nil, fmt.Errorf("reading journal entry %s: %w", key, err)
+		}
+
+		old, err := resolve(record.Old)
+		if err != nil {
+			return nil, err
+		}
+		new, err := resolve(record.New)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, engine.JournalEntry{
+			Kind: record.Kind,
+			Step: engine.NewJournalStep(record.Op, record.URN, old, new),
+		})
+	}
+
+	snap, err := entries.Snap(base)
+	if err != nil {
+		return nil, err
+	}
+	if snap.SecretsManager == nil {
+		snap.SecretsManager = base.SecretsManager
+	}
+	// The journal doesn't record the outputs schema of the interrupted update, so keep the last checkpoint's.
+	snap.Manifest = deploy.Manifest{
+		Time:          time.Now(),
+		Version:       version.Version,
+		OutputsSchema: base.Manifest.OutputsSchema,
+	}
+	snap.Manifest.Magic = snap.Manifest.NewMagic()
+	return snap, nil
+}
@@ -0,0 +1,293 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func TestParseJournalKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key   string
+		epoch int64
+		base  bool
+		ok    bool
+	}{
+		{".pulumi/journals/dev/00000000000000000042.base.json", 42, true, true},
+		{".pulumi/journals/dev/00000000000000000042.0000000007.entry.json", 42, false, true},
+		{".pulumi/journals/dev/00000000000000000042.json", 0, false, false},
+		{".pulumi/journals/dev/abc.base.json", 0, false, false},
+		{".pulumi/journals/dev/42entry.json", 0, false, false},
+	}
+	for _, tt := range tests {
+		epoch, base, ok := parseJournalKey(tt.key)
+		assert.Equal(t, tt.ok, ok, tt.key)
+		assert.Equal(t, tt.epoch, epoch, tt.key)
+		assert.Equal(t, tt.base, base, tt.key)
+	}
+}
+
+func newJournalTestState(name string) *resource.State {
+	return &resource.State{
+		Type:    "a:b:c",
+		URN:     resource.NewURN("a", "project", "", "a:b:c", tokens.QName(name)),
+		Inputs:  resource.PropertyMap{"name": resource.NewStringProperty(name)},
+		Outputs: resource.PropertyMap{"name": resource.NewStringProperty(name)},
+	}
+}
+
+// journalCreate records the begin and success entries of a step creating the given state.
+func journalCreate(t *testing.T, jm *journalSnapshotManager, state *resource.State) {
+	step := engine.NewJournalStep(deploy.OpCreate, state.URN, nil, state)
+	mutation, err := jm.BeginMutation(step)
+	require.NoError(t, err)
+	require.NoError(t, mutation.End(step, true))
+}
+
+func journalResourceNames(snap *deploy.Snapshot) []string {
+	var names []string
+	for _, res := range snap.Resources {
+		names = append(names, string(res.URN.Name()))
+	}
+	return names
+}
+
+func TestJournalSnapshotManager(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil,
+		&localBackendOptions{
+			Getenv: mapGetenv(map[string]string{
+				"PULUMI_SELF_MANAGED_STATE_JOURNAL_COMPACTION_INTERVAL": "4",
+			}),
+		},
+	)
+	require.NoError(t, err)
+
+	ref, err := b.parseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+
+	jm, err := b.newJournalSnapshotManager(ctx, ref, nil, nil, nil)
+	require.NoError(t, err)
+
+	// The fourth entry compacts the journal, which saves the checkpoint of the stack.
+	journalCreate(t, jm, newJournalTestState("a"))
+	journalCreate(t, jm, newJournalTestState("b"))
+	snap, _, err := b.getStack(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, journalResourceNames(snap))
+
+	// Deleting a resource of the base snapshot of the new epoch refers to its canonical state.
+	del := newJournalTestState("a")
+	step := engine.NewJournalStep(deploy.OpDelete, del.URN, del, nil)
+	mutation, err := jm.BeginMutation(step)
+	require.NoError(t, err)
+	require.NoError(t, mutation.End(step, true))
+
+	require.NoError(t, jm.Close())
+	snap, _, err = b.getStack(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, journalResourceNames(snap))
+
+	// Closing the manager deletes the journal.
+	files, err := listBucket(ctx, b.bucket, ref.JournalDir())
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestJournalSnapshotManagerDoesNotModifyBase(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil, nil)
+	require.NoError(t, err)
+
+	ref, err := b.parseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+
+	a, c := newJournalTestState("a"), newJournalTestState("c")
+	base := deploy.NewSnapshot(deploy.Manifest{}, nil, []*resource.State{a, c}, nil)
+	jm, err := b.newJournalSnapshotManager(ctx, ref, nil, base, nil)
+	require.NoError(t, err)
+
+	// Update a copy of a base state, as if its URN references had been normalized.
+	copied := *a
+	copied.Outputs = resource.PropertyMap{"name": resource.NewStringProperty("copied")}
+	updated := newJournalTestState("a")
+	step := engine.NewJournalStep(deploy.OpUpdate, a.URN, &copied, updated)
+	mutation, err := jm.BeginMutation(step)
+	require.NoError(t, err)
+	require.NoError(t, mutation.End(step, true))
+	require.NoError(t, jm.Close())
+
+	// The base snapshot still has its own states.
+	assert.Equal(t, []*resource.State{a, c}, base.Resources)
+	assert.Equal(t, resource.NewStringProperty("a"), a.Outputs["name"])
+
+	snap, _, err := b.getStack(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, journalResourceNames(snap))
+}
+
+func TestRecoverJournal(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil, nil)
+	require.NoError(t, err)
+
+	ref, err := b.parseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+
+	jm, err := b.newJournalSnapshotManager(ctx, ref, nil, nil, nil)
+	require.NoError(t, err)
+	journalCreate(t, jm, newJournalTestState("a"))
+
+	// Begin a step that never ends, and never close the manager, as if the CLI crashed.
+	pending := newJournalTestState("b")
+	_, err = jm.BeginMutation(engine.NewJournalStep(deploy.OpCreate, pending.URN, nil, pending))
+	require.NoError(t, err)
+
+	// The checkpoint of the stack hasn't been written yet, but readers see the state rebuilt from the journal.
+	chk, err := b.getCheckpoint(ctx, ref)
+	require.NoError(t, err)
+	snap, err := stack.DeserializeCheckpoint(ctx, stack.DefaultSecretsProvider, chk)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, journalResourceNames(snap))
+	files, err := listBucket(ctx, b.bucket, ref.JournalDir())
+	require.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	require.NoError(t, b.recoverJournal(ctx, ref))
+	snap, _, err = b.getStack(ctx, ref)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, journalResourceNames(snap))
+	require.Len(t, snap.PendingOperations, 1)
+	assert.Equal(t, pending.URN, snap.PendingOperations[0].Resource.URN)
+
+	files, err = listBucket(ctx, b.bucket, ref.JournalDir())
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	// Recovering a stack without a journal is a no-op.
+	require.NoError(t, b.recoverJournal(ctx, ref))
+}
+
+func TestImportDeploymentRecoversJournal(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil, nil)
+	require.NoError(t, err)
+
+	ref, err := b.parseStackReference("organization/project/a")
+	require.NoError(t, err)
+	s, err := b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+
+	jm, err := b.newJournalSnapshotManager(ctx, ref, nil, nil, nil)
+	require.NoError(t, err)
+	a := newJournalTestState("a")
+	journalCreate(t, jm, a)
+
+	// Exports see the state rebuilt from the journal.
+	exported, err := b.ExportDeployment(ctx, s)
+	require.NoError(t, err)
+	assert.Contains(t, string(exported.Deployment), string(a.URN))
+
+	// Importing a deployment recovers the journal first, so that its state is backed up.
+	require.NoError(t, b.ImportDeployment(ctx, s, &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: json.RawMessage("{}"),
+	}))
+	files, err := listBucket(ctx, b.bucket, ref.JournalDir())
+	require.NoError(t, err)
+	assert.Empty(t, files)
+
+	backup, err := b.bucket.ReadAll(ctx, b.stackPath(ctx, ref)+".bak")
+	require.NoError(t, err)
+	assert.Contains(t, string(backup), string(a.URN))
+
+	snap, _, err := b.getStack(ctx, ref)
+	require.NoError(t, err)
+	assert.Empty(t, journalResourceNames(snap))
+}
+
+func TestExportDeploymentForVersionIgnoresJournal(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil, nil)
+	require.NoError(t, err)
+
+	ref, err := b.parseStackReference("organization/project/a")
+	require.NoError(t, err)
+	s, err := b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+
+	old := newJournalTestState("old")
+	data, err := json.Marshal(apitype.DeploymentV3{
+		Resources: []apitype.ResourceV3{{URN: old.URN, Type: old.Type}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, b.ImportDeployment(ctx, s, &apitype.UntypedDeployment{Version: 3, Deployment: data}))
+	require.NoError(t, b.addToHistory(ctx, ref, backend.UpdateInfo{Kind: apitype.UpdateUpdate}))
+
+	// Leave a journaled update behind.
+	jm, err := b.newJournalSnapshotManager(ctx, ref, nil, nil, nil)
+	require.NoError(t, err)
+	a := newJournalTestState("a")
+	journalCreate(t, jm, a)
+
+	// The latest state is rebuilt from the journal...
+	exported, err := b.ExportDeployment(ctx, s)
+	require.NoError(t, err)
+	assert.Contains(t, string(exported.Deployment), string(a.URN))
+
+	// ...but older versions still export the state that was saved at the end of them.
+	exported, err = b.ExportDeploymentForVersion(ctx, s, "1")
+	require.NoError(t, err)
+	assert.Contains(t, string(exported.Deployment), string(old.URN))
+	assert.NotContains(t, string(exported.Deployment), string(a.URN))
+}
@@ -16,6 +16,8 @@ package filestate
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -23,9 +25,13 @@ import (
 	"os/user"
 	"path"
 	"path/filepath"
+	"strconv"
 	"time"
 
+	"gocloud.dev/gcerrors"
+
 	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/util/cancel"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
@@ -33,11 +39,21 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
+// defaultLockTTL is the default duration for which a lock on a stack is leased. The process that holds the lock
+// renews its lease periodically, so the lease only expires if that process stops, e.g. because it crashed.
+const defaultLockTTL = 5 * time.Minute
+
 type lockContent struct {
 	Pid       int       `json:"pid"`
 	Username  string    `json:"username"`
 	Hostname  string    `json:"hostname"`
 	Timestamp time.Time `json:"timestamp"`
+	// Expires is the time at which the lease on the lock expires. Locks written by older versions of the CLI don't
+	// have an expiry time, and never expire.
+	Expires time.Time `json:"expires"`
+	// Nonce identifies the call to Lock that wrote the lock, so that a lock that was taken over and recreated can be
+	// told apart from the one that this process holds.
+	Nonce string `json:"nonce,omitempty"`
 }
 
 func newLockContent() (*lockContent, error) {
@@ -49,18 +65,110 @@ func newLockContent() (*lockContent, error) {
 	if err != nil {
 		return nil, err
 	}
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
 	return &lockContent{
 		Pid:       os.Getpid(),
 		Username:  u.Username,
 		Hostname:  hostname,
 		Timestamp: time.Now(),
+		Nonce:     hex.EncodeToString(nonce),
 	}, nil
 }
 
-// checkForLock looks for any existing locks for this stack, and returns a helpful diagnostic if there is one.
+// LockInfo describes a lock that is held on a stack.
+type LockInfo struct {
+	// URL is the location of the lock file.
+	URL       string    `json:"url"`
+	Pid       int       `json:"pid"`
+	Username  string    `json:"username"`
+	Hostname  string    `json:"hostname"`
+	Timestamp time.Time `json:"timestamp"`
+	// Expires is the time at which the lease on the lock expires, or the zero time if the lock never expires.
+	Expires time.Time `json:"expires,omitempty"`
+	// Expired is true if the lease on the lock has expired. Expired locks are taken over by the next process that
+	// locks the stack.
+	Expired bool `json:"expired"`
+
+	key string
+}
+
+// lockLease tracks the heartbeat that renews the lease on a lock held by this backend.
+type lockLease struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	// lost is closed if another process breaks the lock or takes it over while it is held.
+	lost chan struct{}
+}
+
+// lockTTL returns the duration for which locks are leased, which can be overridden with
+// PULUMI_SELF_MANAGED_STATE_LOCK_TTL.
+func (b *localBackend) lockTTL() time.Duration {
+	if v := b.Getenv(PulumiFilestateLockTTLEnvVar); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		b.d.Warningf(diag.Message("", "ignoring invalid value %q for %s"), v, PulumiFilestateLockTTLEnvVar)
+	}
+	return defaultLockTTL
+}
+
+// listLocks returns all locks held on the given stack, including the lock held by this backend.
+func (b *localBackend) listLocks(ctx context.Context, stackRef backend.StackReference) ([]LockInfo, error) {
+	allFiles, err := listBucket(ctx, b.bucket, stackLockDir(stackRef.FullyQualifiedName()))
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	var locks []LockInfo
+	for _, file := range allFiles {
+		if file.IsDir {
+			continue
+		}
+
+		content, err := b.bucket.ReadAll(ctx, file.Key)
+		if err != nil {
+			// The lock may have been released since we listed the directory.
+			if gcerrors.Code(err) == gcerrors.NotFound {
+				continue
+			}
+			return nil, err
+		}
+		l := &lockContent{}
+		err = json.Unmarshal(content, &l)
+		if err != nil {
+			return nil, err
+		}
+
+		locks = append(locks, LockInfo{
+			URL:       b.url + "/" + file.Key,
+			Pid:       l.Pid,
+			Username:  l.Username,
+			Hostname:  l.Hostname,
+			Timestamp: l.Timestamp,
+			Expires:   l.Expires,
+			Expired:   !l.Expires.IsZero() && now.After(l.Expires),
+			key:       file.Key,
+		})
+	}
+	return locks, nil
+}
+
+// ListLocks returns the locks that are currently held on the given stack.
+func (b *localBackend) ListLocks(ctx context.Context, stackRef backend.StackReference) ([]LockInfo, error) {
+	return b.listLocks(ctx, stackRef)
+}
+
+// checkForLock looks for any existing locks for this stack, and returns a helpful diagnostic if there is one. Locks
+// whose lease has expired are deleted.
 func (b *localBackend) checkForLock(ctx context.Context, stackRef backend.StackReference) error {
-	stackName := stackRef.FullyQualifiedName()
-	allFiles, err := listBucket(ctx, b.bucket, stackLockDir(stackName))
+	locks, err := b.listLocks(ctx, stackRef)
 	if err != nil {
 		return err
 	}
@@ -69,38 +177,38 @@ func (b *localBackend) checkForLock(ctx context.Context, stackRef backend.StackR
 	// We need to convert it to a slash path (/) to compare it to
 	// the keys in the bucket which are always slash paths.
 	wantLock := filepath.ToSlash(b.lockPath(stackRef))
-	var lockKeys []string
-	for _, file := range allFiles {
-		if file.IsDir {
+	var heldLocks []LockInfo
+	for _, l := range locks {
+		if l.key == wantLock {
 			continue
 		}
-		if file.Key != wantLock {
-			lockKeys = append(lockKeys, file.Key)
+		if l.Expired {
+			// The process that held this lock stopped renewing its lease, so it's safe to take it over.
+			b.d.Warningf(diag.Message("", "taking over the lock at %v, which expired at %v (created by %v@%v, pid %v)"),
+				l.URL, l.Expires.Format(time.RFC3339), l.Username, l.Hostname, l.Pid)
+			if err := b.bucket.Delete(ctx, l.key); err != nil && gcerrors.Code(err) != gcerrors.NotFound {
+				return err
+			}
+			continue
 		}
+		heldLocks = append(heldLocks, l)
 	}
 
-	if len(lockKeys) > 0 {
+	if len(heldLocks) > 0 {
 		errorString := fmt.Sprintf("the stack is currently locked by %v lock(s). Either wait for the other "+
-			"process(es) to end or delete the lock file with `pulumi cancel`.", len(lockKeys))
-
-		for _, lock := range lockKeys {
-			content, err := b.bucket.ReadAll(ctx, lock)
-			if err != nil {
-				return err
-			}
-			l := &lockContent{}
-			err = json.Unmarshal(content, &l)
-			if err != nil {
-				return err
-			}
+			"process(es) to end or delete the lock file with `pulumi cancel`.", len(heldLocks))
 
+		for _, l := range heldLocks {
 			errorString += fmt.Sprintf("\n  %v: created by %v@%v (pid %v) at %v",
-				b.url+"/"+lock,
+				l.URL,
 				l.Username,
 				l.Hostname,
 				l.Pid,
 				l.Timestamp.Format(time.RFC3339),
 			)
+			if !l.Expires.IsZero() {
+				errorString += fmt.Sprintf(", expires at %v", l.Expires.Format(time.RFC3339))
+			}
 		}
 
 		return errors.New(errorString)
@@ -108,6 +216,18 @@ func (b *localBackend) checkForLock(ctx context.Context, stackRef backend.StackR
 	return nil
 }
 
+// writeLock writes the lock file of this backend for the given stack, leasing it for the given duration.
+func (b *localBackend) writeLock(
+	ctx context.Context, stackRef backend.StackReference, content *lockContent, ttl time.Duration,
+) error {
+	content.Expires = time.Now().Add(ttl)
+	bytes, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	return b.bucket.WriteAll(ctx, b.lockPath(stackRef), bytes, nil)
+}
+
 func (b *localBackend) Lock(ctx context.Context, stackRef backend.StackReference) error {
 	//
 	err := b.checkForLock(ctx, stackRef)
@@ -118,11 +238,8 @@ func (b *localBackend) Lock(ctx context.Context, stackRef backend.StackReference
 	if err != nil {
 		return err
 	}
-	content, err := json.Marshal(lockContent)
-	if err != nil {
-		return err
-	}
-	err = b.bucket.WriteAll(ctx, b.lockPath(stackRef), content, nil)
+	ttl := b.lockTTL()
+	err = b.writeLock(ctx, stackRef, lockContent, ttl)
 	if err != nil {
 		return err
 	}
@@ -131,15 +248,162 @@ func (b *localBackend) Lock(ctx context.Context, stackRef backend.StackReference
 		b.Unlock(ctx, stackRef)
 		return err
 	}
+	b.startHeartbeat(stackRef, lockContent, ttl)
 	return nil
 }
 
+// startHeartbeat starts renewing the lease on the lock held on the given stack until it is unlocked.
+func (b *localBackend) startHeartbeat(stackRef backend.StackReference, content *lockContent, ttl time.Duration) {
+	// The heartbeat must outlive the context of the call to Lock, so it gets its own.
+	ctx, cancel := context.WithCancel(context.Background())
+	lease := &lockLease{cancel: cancel, done: make(chan struct{}), lost: make(chan struct{})}
+
+	key := b.lockPath(stackRef)
+	b.leasesLock.Lock()
+	if b.leases == nil {
+		b.leases = make(map[string]*lockLease)
+	}
+	old := b.leases[key]
+	b.leases[key] = lease
+	b.leasesLock.Unlock()
+	if old != nil {
+		old.stop()
+	}
+
+	go func() {
+		defer close(lease.done)
+
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			owned, err := b.renewLock(ctx, stackRef, content, ttl)
+			if err != nil {
+				if ctx.Err() == nil {
+					b.d.Warningf(diag.Message("", "there was a problem renewing the lock at %v: %v"),
+						path.Join(b.url, key), err)
+				}
+				continue
+			}
+			if !owned {
+				if ctx.Err() == nil {
+					b.d.Errorf(diag.Message("", "the lock at %v was broken or taken over by another process; "+
+						"cancelling the update"), path.Join(b.url, key))
+					close(lease.lost)
+				}
+				return
+			}
+		}
+	}()
+}
+
+// renewLock renews the lease on the lock held on the given stack. It returns false if this process no longer owns the
+// lock because its file is gone, holds the content written by another call to Lock, or was taken over by another
+// process while it was being renewed.
+func (b *localBackend) renewLock(
+	ctx context.Context, stackRef backend.StackReference, content *lockContent, ttl time.Duration,
+) (bool, error) {
+	// Another process may have deleted or replaced the lock file when it took over an expired lease. Don't
+	// recreate it in that case.
+	key := b.lockPath(stackRef)
+	bytes, err := b.bucket.ReadAll(ctx, key)
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	var current lockContent
+	if err := json.Unmarshal(bytes, &current); err != nil || current.Nonce != content.Nonce {
+		return false, nil
+	}
+
+	if err := b.writeLock(ctx, stackRef, content, ttl); err != nil {
+		return false, err
+	}
+
+	// The lock may have been taken over between reading and writing it, in which case the other process holds a
+	// live lock of its own. Other processes only take over a lock once its lease has expired, so such a lock was
+	// written after the lease that we just renewed expired, and is the newest one. Any other live lock belongs to a
+	// process that is about to give up because it saw our lock, or that crashed while trying to lock the stack.
+	locks, err := b.listLocks(ctx, stackRef)
+	if err != nil {
+		return false, err
+	}
+	wantLock := filepath.ToSlash(key)
+	var newest *LockInfo
+	for i, l := range locks {
+		if l.key != wantLock && !l.Expired && (newest == nil || l.Timestamp.After(newest.Timestamp)) {
+			newest = &locks[i]
+		}
+	}
+	if newest != nil && newest.Timestamp.After(current.Expires) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// cancelOnLostLock returns a cancellation context that is canceled and terminated along with the given one, and that
+// is also canceled if this backend loses the lock that it holds on the given stack. The returned function must be
+// called once the context is no longer used.
+func (b *localBackend) cancelOnLostLock(
+	stackRef backend.StackReference, cancelCtx *cancel.Context,
+) (*cancel.Context, func()) {
+	b.leasesLock.Lock()
+	lease := b.leases[b.lockPath(stackRef)]
+	b.leasesLock.Unlock()
+	if lease == nil {
+		return cancelCtx, func() {}
+	}
+
+	ctx, source := cancel.NewContext(context.Background())
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-cancelCtx.Canceled():
+			source.Cancel()
+		case <-lease.lost:
+			source.Cancel()
+		case <-done:
+			return
+		}
+
+		select {
+		case <-cancelCtx.Terminated():
+			source.Terminate()
+		case <-done:
+		}
+	}()
+	return ctx, func() { close(done) }
+}
+
+// stop stops renewing the lease and waits for any renewal in progress to finish.
+func (l *lockLease) stop() {
+	l.cancel()
+	<-l.done
+}
+
 func (b *localBackend) Unlock(ctx context.Context, stackRef backend.StackReference) {
-	err := b.bucket.Delete(ctx, b.lockPath(stackRef))
+	// Stop renewing the lease first so that the heartbeat doesn't recreate the lock file.
+	key := b.lockPath(stackRef)
+	b.leasesLock.Lock()
+	lease := b.leases[key]
+	delete(b.leases, key)
+	b.leasesLock.Unlock()
+	if lease != nil {
+		lease.stop()
+	}
+
+	err := b.bucket.Delete(ctx, key)
 	if err != nil {
 		b.d.Errorf(
 			diag.Message("", "there was a problem deleting the lock at %v, manual clean up may be required: %v"),
-			path.Join(b.url, b.lockPath(stackRef)),
+			path.Join(b.url, key),
 			err)
 	}
 }
@@ -0,0 +1,316 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/util/cancel"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+// writeForeignLock writes a lock file for the given stack as if it was held by another process.
+func writeForeignLock(
+	t *testing.T, b *localBackend, ref backend.StackReference, id string, expires time.Time,
+) string {
+	content, err := json.Marshal(&lockContent{
+		Pid:       1234,
+		Username:  "someone",
+		Hostname:  "ci-runner",
+		Timestamp: time.Now(),
+		Expires:   expires,
+	})
+	require.NoError(t, err)
+
+	key := path.Join(stackLockDir(ref.FullyQualifiedName()), id+".json")
+	require.NoError(t, b.bucket.WriteAll(context.Background(), key, content, nil))
+	return key
+}
+
+func TestLockTakesOverExpiredLease(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil, nil)
+	require.NoError(t, err)
+
+	ref, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+
+	// A lock left behind by a crashed process is reported as expired, and taken over by the next lock.
+	expired := writeForeignLock(t, b, ref, "crashed", time.Now().Add(-time.Minute))
+	locks, err := b.ListLocks(ctx, ref)
+	require.NoError(t, err)
+	require.Len(t, locks, 1)
+	assert.True(t, locks[0].Expired)
+	assert.Equal(t, "someone", locks[0].Username)
+
+	require.NoError(t, b.Lock(ctx, ref))
+	exists, err := b.bucket.Exists(ctx, expired)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	locks, err = b.ListLocks(ctx, ref)
+	require.NoError(t, err)
+	require.Len(t, locks, 1)
+	assert.False(t, locks[0].Expired)
+	assert.True(t, locks[0].Expires.After(time.Now()))
+
+	b.Unlock(ctx, ref)
+	locks, err = b.ListLocks(ctx, ref)
+	require.NoError(t, err)
+	assert.Empty(t, locks)
+}
+
+func TestLockRespectsLiveLeases(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil, nil)
+	require.NoError(t, err)
+
+	ref, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+
+	// A lease that hasn't expired yet blocks other processes.
+	live := writeForeignLock(t, b, ref, "live", time.Now().Add(time.Hour))
+	err = b.Lock(ctx, ref)
+	assert.ErrorContains(t, err, "the stack is currently locked by 1 lock(s)")
+	require.NoError(t, b.bucket.Delete(ctx, live))
+
+	// So does a lock written by an older CLI, which has no expiry time.
+	writeForeignLock(t, b, ref, "legacy", time.Time{})
+	err = b.Lock(ctx, ref)
+	assert.ErrorContains(t, err, "the stack is currently locked by 1 lock(s)")
+}
+
+func TestLockHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil,
+		&localBackendOptions{
+			Getenv: mapGetenv(map[string]string{
+				"PULUMI_SELF_MANAGED_STATE_LOCK_TTL": "1",
+			}),
+		},
+	)
+	require.NoError(t, err)
+
+	ref, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, b.Lock(ctx, ref))
+	locks, err := b.ListLocks(ctx, ref)
+	require.NoError(t, err)
+	require.Len(t, locks, 1)
+	firstExpiry := locks[0].Expires
+
+	// The lease is renewed while the lock is held.
+	assert.Eventually(t, func() bool {
+		locks, err := b.ListLocks(ctx, ref)
+		return err == nil && len(locks) == 1 && locks[0].Expires.After(firstExpiry)
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// Unlocking stops the heartbeat, so the lock file isn't recreated.
+	b.Unlock(ctx, ref)
+	time.Sleep(500 * time.Millisecond)
+	exists, err := b.bucket.Exists(ctx, b.lockPath(ref))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestLockHeartbeatDetectsLostLock(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		// takeOver simulates another process taking over the lock held by b.
+		takeOver func(t *testing.T, b *localBackend, ref backend.StackReference)
+	}{
+		{
+			name: "deleted",
+			takeOver: func(t *testing.T, b *localBackend, ref backend.StackReference) {
+				require.NoError(t, b.bucket.Delete(context.Background(), b.lockPath(ref)))
+				writeForeignLock(t, b, ref, "other", time.Now().Add(time.Hour))
+			},
+		},
+		{
+			name: "replaced",
+			takeOver: func(t *testing.T, b *localBackend, ref backend.StackReference) {
+				content, err := json.Marshal(&lockContent{Pid: 1234, Expires: time.Now().Add(time.Hour), Nonce: "other"})
+				require.NoError(t, err)
+				require.NoError(t, b.bucket.WriteAll(context.Background(), b.lockPath(ref), content, nil))
+			},
+		},
+		{
+			// The other process took over the lock after its lease expired, while it was being renewed, so both locks
+			// are live.
+			name: "contended",
+			takeOver: func(t *testing.T, b *localBackend, ref backend.StackReference) {
+				writeForeignLock(t, b, ref, "other", time.Now().Add(time.Hour))
+
+				bytes, err := b.bucket.ReadAll(context.Background(), b.lockPath(ref))
+				require.NoError(t, err)
+				var content lockContent
+				require.NoError(t, json.Unmarshal(bytes, &content))
+				content.Expires = time.Now().Add(-time.Minute)
+				bytes, err = json.Marshal(&content)
+				require.NoError(t, err)
+				require.NoError(t, b.bucket.WriteAll(context.Background(), b.lockPath(ref), bytes, nil))
+			},
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := t.TempDir()
+			ctx := context.Background()
+			b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil,
+				&localBackendOptions{
+					Getenv: mapGetenv(map[string]string{
+						"PULUMI_SELF_MANAGED_STATE_LOCK_TTL": "1",
+					}),
+				},
+			)
+			require.NoError(t, err)
+
+			ref, err := b.ParseStackReference("organization/project/a")
+			require.NoError(t, err)
+			_, err = b.CreateStack(ctx, ref, "", nil)
+			require.NoError(t, err)
+
+			require.NoError(t, b.Lock(ctx, ref))
+			defer b.Unlock(ctx, ref)
+
+			scopeCtx, _ := cancel.NewContext(ctx)
+			cancelCtx, stop := b.cancelOnLostLock(ref, scopeCtx)
+			defer stop()
+
+			c.takeOver(t, b, ref)
+
+			// Losing the lock cancels the update, but doesn't terminate it.
+			select {
+			case <-cancelCtx.Canceled():
+			case <-time.After(5 * time.Second):
+				require.Fail(t, "losing the lock didn't cancel the update")
+			}
+			assert.NoError(t, cancelCtx.TerminateErr())
+		})
+	}
+}
+
+func TestLockHeartbeatIgnoresFailedLockAttempts(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil,
+		&localBackendOptions{
+			Getenv: mapGetenv(map[string]string{
+				"PULUMI_SELF_MANAGED_STATE_LOCK_TTL": "1",
+			}),
+		},
+	)
+	require.NoError(t, err)
+
+	ref, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, b.Lock(ctx, ref))
+	defer b.Unlock(ctx, ref)
+
+	// The lock written by another process is removed before the next heartbeat, as it is when the process sees our
+	// lock and gives up.
+	bytes, err := b.bucket.ReadAll(ctx, b.lockPath(ref))
+	require.NoError(t, err)
+	var content lockContent
+	require.NoError(t, json.Unmarshal(bytes, &content))
+	other := writeForeignLock(t, b, ref, "other", time.Now().Add(time.Hour))
+	owned, err := b.renewLock(ctx, ref, &content, time.Second)
+	require.NoError(t, err)
+	assert.True(t, owned)
+	require.NoError(t, b.bucket.Delete(ctx, other))
+
+	scopeCtx, _ := cancel.NewContext(ctx)
+	cancelCtx, stop := b.cancelOnLostLock(ref, scopeCtx)
+	defer stop()
+	select {
+	case <-cancelCtx.Canceled():
+		assert.Fail(t, "a failed attempt to lock the stack canceled the update")
+	case <-time.After(1500 * time.Millisecond):
+	}
+}
+
+func TestLockHeartbeatIgnoresStaleContenders(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	ctx := context.Background()
+	b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil,
+		&localBackendOptions{
+			Getenv: mapGetenv(map[string]string{
+				"PULUMI_SELF_MANAGED_STATE_LOCK_TTL": "1",
+			}),
+		},
+	)
+	require.NoError(t, err)
+
+	ref, err := b.ParseStackReference("organization/project/a")
+	require.NoError(t, err)
+	_, err = b.CreateStack(ctx, ref, "", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, b.Lock(ctx, ref))
+	defer b.Unlock(ctx, ref)
+
+	scopeCtx, _ := cancel.NewContext(ctx)
+	cancelCtx, stop := b.cancelOnLostLock(ref, scopeCtx)
+	defer stop()
+
+	// A process that crashed right after writing its lock leaves the lock behind until its lease expires. It was
+	// written while our lease was live, so it doesn't take over the lock, however many heartbeats see it.
+	writeForeignLock(t, b, ref, "crashed", time.Now().Add(time.Hour))
+	select {
+	case <-cancelCtx.Canceled():
+		assert.Fail(t, "a stale lock left behind by another process canceled the update")
+	case <-time.After(1500 * time.Millisecond):
+	}
+	exists, err := b.bucket.Exists(ctx, b.lockPath(ref))
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
@@ -175,7 +175,23 @@ func (b *localBackend) getCheckpoint(ctx context.Context, ref *localBackendRefer
 		m = encoding.Gzip(m)
 	}
 
-	return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(m, bytes)
+	chk, err := stack.UnmarshalVersionedCheckpointToLatestCheckpoint(m, bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// While a journaled update is running, or if it didn't finish, the checkpoint is out of date. The latest state
+	// of the stack is rebuilt from the journal instead.
+	snap, err := b.replayPendingJournal(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if snap != nil {
+		if chk.Latest, err = stack.SerializeDeployment(snap, snap.SecretsManager, false); err != nil {
+			return nil, err
+		}
+	}
+	return chk, nil
 }
 
 func (b *localBackend) saveCheckpoint(
@@ -306,6 +322,10 @@ func (b *localBackend) removeStack(ctx context.Context, ref *localBackendReferen
 	file := b.stackPath(ctx, ref)
 	backupTarget(ctx, b.bucket, file, false)
 
+	if err := removeAllByPrefix(ctx, b.bucket, ref.JournalDir()); err != nil {
+		return err
+	}
+
 	historyDir := ref.HistoryDir()
 	return removeAllByPrefix(ctx, b.bucket, historyDir)
 }
@@ -408,18 +428,12 @@ func (b *localBackend) stackPath(ctx context.Context, ref *localBackendReference
 	return plainPath
 }
 
-// getHistory returns locally stored update history. The first element of the result will be
-// the most recent update record.
-func (b *localBackend) getHistory(
-	ctx context.Context,
-	stack *localBackendReference,
-	pageSize int, page int,
-) ([]backend.UpdateInfo, error) {
+// listHistory returns the history files of the given stack. The first element of the result will be the file for
+// the most recent update. If the stack has no history, nil is returned.
+func (b *localBackend) listHistory(ctx context.Context, stack *localBackendReference) ([]*blob.ListObject, error) {
 	contract.Requiref(stack != nil, "stack", "must not be nil")
 
 	dir := stack.HistoryDir()
-	// TODO: we could consider optimizing the list operation using `page` and `pageSize`.
-	// Unfortunately, this is mildly invasive given the gocloud List API.
 	allFiles, err := listBucket(ctx, b.bucket, dir)
 	if err != nil {
 		// History doesn't exist until a stack has been updated.
@@ -447,6 +461,27 @@ func (b *localBackend) getHistory(
 		historyEntries = append(historyEntries, file)
 	}
 
+	return historyEntries, nil
+}
+
+// getHistory returns locally stored update history. The first element of the result will be
+// the most recent update record.
+//
+// Updates are numbered in the order in which they happened, starting at 1 for the oldest update.
+func (b *localBackend) getHistory(
+	ctx context.Context,
+	stack *localBackendReference,
+	pageSize int, page int,
+) ([]backend.UpdateInfo, error) {
+	contract.Requiref(stack != nil, "stack", "must not be nil")
+
+	// TODO: we could consider optimizing the list operation using `page` and `pageSize`.
+	// Unfortunately, this is mildly invasive given the gocloud List API.
+	historyEntries, err := b.listHistory(ctx, stack)
+	if err != nil {
+		return nil, err
+	}
+
 	start := 0
 	end := len(historyEntries) - 1
 	if pageSize > 0 {
@@ -479,6 +514,9 @@ func (b *localBackend) getHistory(
 		if err != nil {
 			return nil, fmt.Errorf("reading history file %s: %w", filepath, err)
 		}
+		if update.Version == 0 {
+			update.Version = len(historyEntries) - i
+		}
 
 		updates = append(updates, update)
 	}
@@ -486,6 +524,36 @@ func (b *localBackend) getHistory(
 	return updates, nil
 }
 
+// getHistoryCheckpoint loads the checkpoint that was saved at the end of the given version of the stack, using the
+// same numbering as getHistory.
+func (b *localBackend) getHistoryCheckpoint(
+	ctx context.Context,
+	ref *localBackendReference,
+	version int,
+) (*apitype.CheckpointV3, error) {
+	historyEntries, err := b.listHistory(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if version < 1 || version > len(historyEntries) {
+		return nil, fmt.Errorf("version %d of stack %s does not exist", version, ref)
+	}
+
+	// Every history file has a checkpoint file next to it that shares its prefix.
+	historyFile := historyEntries[len(historyEntries)-version].Key
+	checkpointFile := strings.Replace(historyFile, ".history.json", ".checkpoint.json", 1)
+	bytes, err := b.bucket.ReadAll(ctx, checkpointFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint file %s: %w", checkpointFile, err)
+	}
+	m := encoding.JSON
+	if encoding.IsCompressed(bytes) {
+		m = encoding.Gzip(m)
+	}
+
+	return stack.UnmarshalVersionedCheckpointToLatestCheckpoint(m, bytes)
+}
+
 func (b *localBackend) renameHistory(ctx context.Context, oldName, newName *localBackendReference) error {
 	contract.Requiref(oldName != nil, "oldName", "must not be nil")
 	contract.Requiref(newName != nil, "newName", "must not be nil")
@@ -44,6 +44,10 @@ var (
 	// BackupsDir is a path under the state's root directory
 	// where the filestate backend stores backups of stacks.
 	BackupsDir = filepath.Join(workspace.BookkeepingDir, workspace.BackupDir)
+
+	// JournalsDir is a path under the state's root directory
+	// where the filestate backend stores the journals of stacks that are being updated.
+	JournalsDir = filepath.Join(workspace.BookkeepingDir, "journals")
 )
 
 // referenceStore stores and provides access to stack information.
@@ -73,6 +77,12 @@ type referenceStore interface {
 	// This must be under BackupsDir.
 	BackupDir(*localBackendReference) string
 
+	// JournalDir returns the path to the directory
+	// where the journal for this stack is stored.
+	//
+	// This must be under JournalsDir.
+	JournalDir(*localBackendReference) string
+
 	// ListReferences lists all stack references in the store.
 	ListReferences(context.Context) ([]*localBackendReference, error)
 
@@ -130,6 +140,11 @@ func (p *projectReferenceStore) BackupDir(stack *localBackendReference) string {
 	return filepath.Join(BackupsDir, fsutil.NamePath(stack.project), fsutil.NamePath(stack.name))
 }
 
+func (p *projectReferenceStore) JournalDir(stack *localBackendReference) string {
+	contract.Requiref(stack.project != "", "ref.project", "must not be empty")
+	return filepath.Join(JournalsDir, fsutil.NamePath(stack.project), fsutil.NamePath(stack.name))
+}
+
 func (p *projectReferenceStore) ParseReference(stackRef string) (*localBackendReference, error) {
 	// We accept the following forms:
 	//
@@ -336,6 +351,11 @@ func (p *legacyReferenceStore) BackupDir(stack *localBackendReference) string {
 	return filepath.Join(BackupsDir, fsutil.NamePath(stack.name))
 }
 
+func (p *legacyReferenceStore) JournalDir(stack *localBackendReference) string {
+	contract.Requiref(stack.project == "", "ref.project", "must be empty")
+	return filepath.Join(JournalsDir, fsutil.NamePath(stack.name))
+}
+
 func (p *legacyReferenceStore) ParseReference(stackRef string) (*localBackendReference, error) {
 	if !tokens.IsName(stackRef) || len(stackRef) > 100 {
 		return nil, fmt.Errorf(
@@ -1179,6 +1179,7 @@ func (b *cloudBackend) runEngineAction(
 	}
 	persister := b.newSnapshotPersister(ctx, u.update, u.tokenSource, sm)
 	snapshotManager := backend.NewSnapshotManager(persister, u.GetTarget().Snapshot)
+	snapshotManager.SetOutputsSchema(op.Proj.Outputs)
 
 	// Depending on the action, kick off the relevant engine activity.  Note that we don't immediately check and
 	// return error conditions, because we will do so below after waiting for the display channels to close.
@@ -1796,6 +1797,18 @@ func (c httpstateBackendClient) GetStackResourceOutputs(
 	return c.backend.GetStackResourceOutputs(ctx, name)
 }
 
+func (c httpstateBackendClient) GetStackOutputsSchema(
+	ctx context.Context, name string,
+) (map[string]workspace.ProjectOutputType, error) {
+	// Like stack references, require that the name is fully qualified.
+	if strings.Count(name, "/") != 2 {
+		return nil, fmt.Errorf("a stack reference's name should be of the form " +
+			"'<organization>/<project>/<stack>'. See https://pulumi.io/help/stack-reference for more information.")
+	}
+
+	return c.backend.GetStackOutputsSchema(ctx, name)
+}
+
 // Represents feature-detected capabilities of the service the backend is connected to.
 type capabilities struct {
 	// If non-nil, indicates that delta checkpoint updates are supported.
@@ -29,6 +29,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
 // SnapshotPersister is an interface implemented by our backends that implements snapshot
@@ -67,6 +68,10 @@ type SnapshotManager struct {
 	mutationRequests chan<- mutationRequest   // The queue of mutation requests, to be retired serially by the manager
 	cancel           chan bool                // A channel used to request cancellation of any new mutation requests.
 	done             <-chan error             // A channel that sends a single result when the manager has shut down.
+
+	// outputsSchema holds the types that the project declares for the stack's outputs, which are recorded in the
+	// manifest of each snapshot.
+	outputsSchema map[string]workspace.ProjectOutputType
 }
 
 var _ engine.SnapshotManager = (*SnapshotManager)(nil)
@@ -629,6 +634,7 @@ func (sm *SnapshotManager) snap() *deploy.Snapshot {
 		Time:    time.Now(),
 		Version: version.Version,
 		// Plugins: sm.plugins, - Explicitly dropped, since we don't use the plugin list in the manifest anymore.
+		OutputsSchema: sm.outputsSchema,
 	}
 
 	manifest.Magic = manifest.NewMagic()
@@ -700,6 +706,13 @@ func (sm *SnapshotManager) unsafeServiceLoop(mutationRequests chan mutationReque
 	}
 }
 
+// SetOutputsSchema records the types that the project declares for the stack's outputs in the snapshots that the
+// manager saves, so that stack references can check the outputs that they read. It must be called before the manager
+// is handed to the engine.
+func (sm *SnapshotManager) SetOutputsSchema(outputsSchema map[string]workspace.ProjectOutputType) {
+	sm.outputsSchema = outputsSchema
+}
+
 // NewSnapshotManager creates a new SnapshotManager for the given stack name, using the given persister
 // and base snapshot.
 //
@@ -30,6 +30,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
 type MockRegisterResourceEvent struct {
@@ -622,6 +623,32 @@ func TestDeletion(t *testing.T) {
 	assert.Len(t, lastSnap.Resources, 0)
 }
 
+func TestOutputsSchemaIsRecordedInManifest(t *testing.T) {
+	t.Parallel()
+
+	resourceA := NewResource("a")
+	snap := NewSnapshot([]*resource.State{
+		resourceA,
+	})
+
+	outputsSchema := map[string]workspace.ProjectOutputType{"vpcId": {Type: "string"}}
+	manager, sp := MockSetup(t, snap)
+	manager.SetOutputsSchema(outputsSchema)
+	step := deploy.NewDeleteStep(nil, map[resource.URN]bool{}, resourceA)
+	mutation, err := manager.BeginMutation(step)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	err = mutation.End(step, true)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	for _, saved := range sp.SavedSnapshots {
+		assert.Equal(t, outputsSchema, saved.Manifest.OutputsSchema)
+	}
+}
+
 func TestFailedDelete(t *testing.T) {
 	t.Parallel()
 
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -32,6 +33,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
@@ -42,6 +44,7 @@ import (
 func newConfigCmd() *cobra.Command {
 	var stack string
 	var showSecrets bool
+	var showOrigin bool
 	var jsonOut bool
 
 	cmd := &cobra.Command{
@@ -49,7 +52,17 @@ func newConfigCmd() *cobra.Command {
 		Short: "Manage configuration",
 		Long: "Lists all configuration values for a specific stack. To add a new configuration value, run\n" +
 			"`pulumi config set`. To remove and existing value run `pulumi config rm`. To get the value of\n" +
-			"for a specific configuration key, use `pulumi config get <key-name>`.",
+			"for a specific configuration key, use `pulumi config get <key-name>`.\n" +
+			"\n" +
+			"A stack's config file may inherit config from other stack config files or shared config\n" +
+			"fragments by listing their paths, relative to the file, under `extends`. Later files take\n" +
+			"precedence over earlier ones, and the stack's own config takes precedence over all of them.\n" +
+			"Use `--show-origin` to show the file that each value comes from.\n" +
+			"\n" +
+			"A config value may also be read from an environment variable or a file when it is used, e.g.\n" +
+			"`aws:region: {fromEnv: AWS_REGION}` or `app:cert: {fromFile: ./cert.pem, secret: true}`.\n" +
+			"File paths are relative to the working directory. Listing config shows references without\n" +
+			"reading them.",
 		Args: cmdutil.NoArgs,
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := commandContext()
@@ -67,13 +80,16 @@ func newConfigCmd() *cobra.Command {
 				return err
 			}
 
-			return listConfig(ctx, project, stack, showSecrets, jsonOut)
+			return listConfig(ctx, project, stack, showSecrets, showOrigin, jsonOut)
 		}),
 	}
 
 	cmd.Flags().BoolVar(
 		&showSecrets, "show-secrets", false,
 		"Show secret values when listing config instead of displaying blinded values")
+	cmd.Flags().BoolVar(
+		&showOrigin, "show-origin", false,
+		"Show the file that each value comes from, e.g. a config file that the stack extends")
 	cmd.Flags().BoolVarP(
 		&jsonOut, "json", "j", false,
 		"Emit output as JSON")
@@ -91,6 +107,7 @@ func newConfigCmd() *cobra.Command {
 	cmd.AddCommand(newConfigSetAllCmd(&stack))
 	cmd.AddCommand(newConfigRefreshCmd(&stack))
 	cmd.AddCommand(newConfigCopyCmd(&stack))
+	cmd.AddCommand(newConfigDiffCmd(&stack))
 
 	return cmd
 }
@@ -584,6 +601,10 @@ func newConfigSetCmd(stack *string) *cobra.Command {
 				return err
 			}
 
+			if err = validateConfigSet(s, project, ps, key, value, path); err != nil {
+				return err
+			}
+
 			return saveProjectStack(s, ps)
 		}),
 	}
@@ -743,6 +764,141 @@ func saveProjectStack(stack backend.Stack, ps *workspace.ProjectStack) error {
 	return ps.Save(stackConfigFile)
 }
 
+// validateConfigSet checks a value that is set for the given key against the type that the project declares for the
+// key, if any. Values that are set by path are checked as part of their top-level value, unless it is secret.
+func validateConfigSet(stack backend.Stack, project *workspace.Project, ps *workspace.ProjectStack,
+	key config.Key, value string, path bool,
+) error {
+	stackName := stack.Ref().Name().String()
+	if !path {
+		return workspace.ValidateStackConfigValue(
+			stackName, project, key, config.NewValue(value), config.NewPanicCrypter())
+	}
+
+	p, err := resource.ParsePropertyPath(key.Name())
+	if err != nil {
+		return err
+	}
+	name, ok := p[0].(string)
+	if !ok {
+		return nil
+	}
+	rootKey := config.MustMakeKey(key.Namespace(), name)
+	rootValue, ok := ps.Config[rootKey]
+	if !ok || rootValue.Secure() {
+		return nil
+	}
+	return workspace.ValidateStackConfigValue(stackName, project, rootKey, rootValue, config.NewPanicCrypter())
+}
+
+// locateConfigError prefixes errors about invalid stack config values with the location of the offending value in the
+// stack's config file.
+func locateConfigError(project *workspace.Project, stack backend.Stack, err error) error {
+	var validationErr *workspace.ConfigValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	path, pathErr := projectStackPath(stack)
+	if pathErr != nil {
+		return err
+	}
+	ps, loadErr := workspace.LoadProjectStack(project, path)
+	if loadErr != nil {
+		return err
+	}
+	line := ps.ConfigLine(project.Name.String(), validationErr.Key)
+	if line == 0 {
+		return err
+	}
+	return fmt.Errorf("%s:%d: %w", displayPath(path), line, err)
+}
+
+// projectStackPath returns the path of the stack's config file.
+func projectStackPath(stack backend.Stack) (string, error) {
+	if stackConfigFile != "" {
+		return stackConfigFile, nil
+	}
+	_, path, err := workspace.DetectProjectStackPath(stack.Ref().Name().Q())
+	return path, err
+}
+
+// displayPath returns the given path relative to the working directory if it is within it.
+func displayPath(path string) string {
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return path
+}
+
+// mergeStackConfigLayers returns the stack's config merged with the config of the files that it extends, along with
+// the paths of the files that the inherited keys come from. If sm is not nil, the inherited secrets of files that use
+// other secrets managers are re-encrypted with it, so that all of the merged config can be decrypted by sm. Files
+// that contain secure values must declare the secrets settings that they were encrypted with.
+func mergeStackConfigLayers(stack backend.Stack, project *workspace.Project, ps *workspace.ProjectStack,
+	sm secrets.Manager,
+) (config.Map, map[config.Key]string, error) {
+	if len(ps.Extends) == 0 {
+		return ps.Config, nil, nil
+	}
+
+	layers, err := ps.LoadConfigLayers(project)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sm != nil {
+		for i, layer := range layers {
+			if !layer.Stack.Config.HasSecureValue() {
+				continue
+			}
+			if !hasSecretsSettings(layer.Stack) {
+				return nil, nil, fmt.Errorf("%s contains secure values but does not declare the secrets settings "+
+					"they were encrypted with; set its secretsprovider (and encryptedkey or encryptionsalt)",
+					displayPath(layer.Path))
+			}
+			if sameSecretsSettings(ps, layer.Stack) {
+				continue
+			}
+
+			layerSM, _, err := getStackSecretsManager(stack, layer.Stack)
+			if err != nil {
+				return nil, nil, fmt.Errorf("getting the secrets manager of %s: %w", displayPath(layer.Path), err)
+			}
+			dec, err := layerSM.Decrypter()
+			if err != nil {
+				return nil, nil, err
+			}
+			enc, err := sm.Encrypter()
+			if err != nil {
+				return nil, nil, err
+			}
+			reencrypted := *layer.Stack
+			if reencrypted.Config, err = layer.Stack.Config.Copy(dec, enc); err != nil {
+				return nil, nil, fmt.Errorf("re-encrypting the config of %s: %w", displayPath(layer.Path), err)
+			}
+			layers[i].Stack = &reencrypted
+		}
+	}
+
+	merged, origins := workspace.MergeConfigLayers(layers, ps.Config)
+	return merged, origins, nil
+}
+
+// hasSecretsSettings returns true if the given config layer declares the secrets settings of its secure values.
+func hasSecretsSettings(layer *workspace.ProjectStack) bool {
+	return layer.SecretsProvider != "" || layer.EncryptedKey != "" || layer.EncryptionSalt != ""
+}
+
+// sameSecretsSettings returns true if the secrets of the given config layer are encrypted by the stack's secrets
+// manager.
+func sameSecretsSettings(ps *workspace.ProjectStack, layer *workspace.ProjectStack) bool {
+	return layer.SecretsProvider == ps.SecretsProvider &&
+		layer.EncryptedKey == ps.EncryptedKey &&
+		layer.EncryptionSalt == ps.EncryptionSalt
+}
+
 func parseConfigKey(key string) (config.Key, error) {
 	// As a convenience, we'll treat any key with no delimiter as if:
 	// <program-name>:<key> had been written instead
@@ -783,12 +939,17 @@ type configValueJSON struct {
 	Value       *string     `json:"value,omitempty"`
 	ObjectValue interface{} `json:"objectValue,omitempty"`
 	Secret      bool        `json:"secret"`
+	// Origin is the path of the file that the value comes from, if --show-origin was passed.
+	Origin string `json:"origin,omitempty"`
+	// Reference is the environment variable or file that the value is read from, if any.
+	Reference *config.Reference `json:"reference,omitempty"`
 }
 
 func listConfig(ctx context.Context,
 	project *workspace.Project,
 	stack backend.Stack,
 	showSecrets bool,
+	showOrigin bool,
 	jsonOut bool,
 ) error {
 	ps, err := loadProjectStack(project, stack)
@@ -796,20 +957,15 @@ func listConfig(ctx context.Context,
 		return err
 	}
 
-	stackName := stack.Ref().Name().String()
-	// when listing configuration values
-	// also show values coming from the project
-	err = workspace.ApplyProjectConfig(stackName, project, ps.Config)
+	cfg, origins, err := mergeStackConfigLayers(stack, project, ps, nil)
 	if err != nil {
 		return err
 	}
 
-	cfg := ps.Config
-
 	// By default, we will use a blinding decrypter to show "[secret]". If requested, display secrets in plaintext.
 	decrypter := config.NewBlindingDecrypter()
 	if cfg.HasSecureValue() && showSecrets {
-		stackDecrypter, needsSave, err := getStackDecrypter(stack, ps)
+		sm, needsSave, err := getStackSecretsManager(stack, ps)
 		if err != nil {
 			return err
 		}
@@ -819,7 +975,40 @@ func listConfig(ctx context.Context,
 				return fmt.Errorf("save stack config: %w", err)
 			}
 		}
-		decrypter = stackDecrypter
+		// Inherited secrets may have been encrypted by other secrets managers.
+		if cfg, origins, err = mergeStackConfigLayers(stack, project, ps, sm); err != nil {
+			return err
+		}
+		if decrypter, err = sm.Decrypter(); err != nil {
+			return err
+		}
+	}
+
+	// when listing configuration values
+	// also show values coming from the project
+	stackName := stack.Ref().Name().String()
+	stackKeys := make(map[config.Key]bool, len(cfg))
+	for key := range cfg {
+		stackKeys[key] = true
+	}
+	err = workspace.ApplyProjectConfig(stackName, project, cfg)
+	if err != nil {
+		return err
+	}
+
+	// origin returns the file that the value of the given key comes from.
+	origin := func(key config.Key) string {
+		if path, ok := origins[key]; ok {
+			return displayPath(path)
+		}
+		path, err := projectStackPath(stack)
+		if !stackKeys[key] {
+			path, err = workspace.DetectProjectPath()
+		}
+		if err != nil {
+			return ""
+		}
+		return displayPath(path)
 	}
 
 	var keys config.KeyArray
@@ -836,6 +1025,15 @@ func listConfig(ctx context.Context,
 			entry := configValueJSON{
 				Secret: cfg[key].Secure(),
 			}
+			if showOrigin {
+				entry.Origin = origin(key)
+			}
+			if ref, ok := cfg[key].Reference(); ok {
+				// References are listed as they are; their values are only read when they are used.
+				entry.Reference = &ref
+				configValues[key.String()] = entry
+				continue
+			}
 
 			decrypted, err := cfg[key].Value(decrypter)
 			if err != nil {
@@ -868,16 +1066,30 @@ func listConfig(ctx context.Context,
 	} else {
 		rows := []cmdutil.TableRow{}
 		for _, key := range keys {
-			decrypted, err := cfg[key].Value(decrypter)
-			if err != nil {
-				return fmt.Errorf("could not decrypt configuration value: %w", err)
+			var value string
+			if ref, ok := cfg[key].Reference(); ok {
+				value = fmt.Sprintf("(read from %v)", ref)
+			} else {
+				decrypted, err := cfg[key].Value(decrypter)
+				if err != nil {
+					return fmt.Errorf("could not decrypt configuration value: %w", err)
+				}
+				value = decrypted
 			}
 
-			rows = append(rows, cmdutil.TableRow{Columns: []string{prettyKey(key), decrypted}})
+			columns := []string{prettyKey(key), value}
+			if showOrigin {
+				columns = append(columns, origin(key))
+			}
+			rows = append(rows, cmdutil.TableRow{Columns: columns})
 		}
 
+		headers := []string{"KEY", "VALUE"}
+		if showOrigin {
+			headers = append(headers, "ORIGIN")
+		}
 		cmdutil.PrintTable(cmdutil.Table{
-			Headers: []string{"KEY", "VALUE"},
+			Headers: headers,
 			Rows:    rows,
 		})
 	}
@@ -899,24 +1111,32 @@ func getConfig(ctx context.Context, stack backend.Stack, key config.Key, path, j
 		return err
 	}
 
+	cfg, origins, err := mergeStackConfigLayers(stack, project, ps, nil)
+	if err != nil {
+		return err
+	}
+
 	stackName := stack.Ref().Name().String()
 	// when asking for a configuration value, include values from the project config
-	err = workspace.ApplyProjectConfig(stackName, project, ps.Config)
+	err = workspace.ApplyProjectConfig(stackName, project, cfg)
 	if err != nil {
 		return err
 	}
-	cfg := ps.Config
 
 	v, ok, err := cfg.Get(key, path)
 	if err != nil {
 		return err
 	}
 	if ok {
+		ref, isRef := v.Reference()
+
 		var d config.Decrypter
-		if v.Secure() {
-			var err error
-			var needsSave bool
-			if d, needsSave, err = getStackDecrypter(stack, ps); err != nil {
+		if isRef {
+			// References are read rather than decrypted, so they don't need the stack's secrets manager.
+			d = config.NewPanicCrypter()
+		} else if v.Secure() {
+			sm, needsSave, err := getStackSecretsManager(stack, ps)
+			if err != nil {
 				return fmt.Errorf("could not create a decrypter: %w", err)
 			}
 			// This may have setup the stack's secrets provider, so save the stack if needed.
@@ -925,6 +1145,20 @@ func getConfig(ctx context.Context, stack backend.Stack, key config.Key, path, j
 					return fmt.Errorf("save stack config: %w", err)
 				}
 			}
+			if d, err = sm.Decrypter(); err != nil {
+				return fmt.Errorf("could not create a decrypter: %w", err)
+			}
+
+			// Inherited secrets may have been encrypted by other secrets managers.
+			if len(origins) > 0 {
+				merged, _, err := mergeStackConfigLayers(stack, project, ps, sm)
+				if err != nil {
+					return err
+				}
+				if v, _, err = merged.Get(key, path); err != nil {
+					return err
+				}
+			}
 		} else {
 			d = config.NewPanicCrypter()
 		}
@@ -938,6 +1172,9 @@ func getConfig(ctx context.Context, stack backend.Stack, key config.Key, path, j
 				Value:  &raw,
 				Secret: v.Secure(),
 			}
+			if isRef {
+				value.Reference = &ref
+			}
 
 			if v.Object() {
 				var obj interface{}
@@ -954,6 +1191,10 @@ func getConfig(ctx context.Context, stack backend.Stack, key config.Key, path, j
 			fmt.Println(string(out))
 		} else {
 			fmt.Printf("%v\n", raw)
+			if isRef {
+				// Describe the reference on stderr so that scripts can keep reading the value from stdout.
+				fmt.Fprintf(os.Stderr, "(read from %v)\n", ref)
+			}
 		}
 
 		log3rdPartySecretsProviderDecryptionEvent(ctx, stack, key.Name(), "")
@@ -1032,12 +1273,17 @@ func getStackConfiguration(
 		}
 	}
 
+	cfg, _, err := mergeStackConfigLayers(stack, project, workspaceStack, sm)
+	if err != nil {
+		return defaultStackConfig, nil, fmt.Errorf("loading inherited stack config: %w", err)
+	}
+
 	// If there are no secrets in the configuration, we should never use the decrypter, so it is safe to return
 	// one which panics if it is used. This provides for some nice UX in the common case (since, for example, building
 	// the correct decrypter for the local backend would involve prompting for a passphrase)
-	if !workspaceStack.Config.HasSecureValue() {
+	if !cfg.HasSecureValue() {
 		return backend.StackConfiguration{
-			Config:    workspaceStack.Config,
+			Config:    cfg,
 			Decrypter: config.NewPanicCrypter(),
 		}, sm, nil
 	}
@@ -1048,7 +1294,7 @@ func getStackConfiguration(
 	}
 
 	return backend.StackConfiguration{
-		Config:    workspaceStack.Config,
+		Config:    cfg,
 		Decrypter: crypter,
 	}, sm, nil
 }
@@ -0,0 +1,367 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+const (
+	configDiffAdd    = "add"
+	configDiffRemove = "remove"
+	configDiffChange = "change"
+)
+
+// configDiffJSON is the shape of the --json output of a difference between two configs. While we can add fields to
+// this structure in the future, we should not change existing fields.
+type configDiffJSON struct {
+	Key string `json:"key"`
+	// Kind is one of "add", "remove" or "change".
+	Kind string `json:"kind"`
+	// Old and New are the values of the key in the other and the current config. They are not set for secrets unless
+	// --show-secrets was passed.
+	Old    *string `json:"old,omitempty"`
+	New    *string `json:"new,omitempty"`
+	Secret bool    `json:"secret"`
+	// NotCompared is true for a secret whose ciphertexts differ but whose values weren't compared because
+	// --show-secrets wasn't passed, so it may or may not have changed.
+	NotCompared bool `json:"notCompared,omitempty"`
+}
+
+func newConfigDiffCmd(stack *string) *cobra.Command {
+	var otherStackName string
+	var deployed bool
+	var showSecrets bool
+	var jsonOut bool
+
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show the differences between the config of two stacks",
+		Long: "Show the differences between the config of two stacks.\n" +
+			"\n" +
+			"Compares the current stack's config with the config of the stack given by `--other`, or with the\n" +
+			"config of the current stack's latest deployment if `--deployed` is passed. Passing both compares the\n" +
+			"current stack's config with the config of the other stack's latest deployment. Keys that only the\n" +
+			"current stack has are shown as added, and keys that only the other config has as removed.\n" +
+			"\n" +
+			"`--config-file` only applies to the current stack; the other stack's config is read from its own\n" +
+			"stack config file.\n" +
+			"\n" +
+			"Secrets are only decrypted if `--show-secrets` is passed. Otherwise they are compared by their\n" +
+			"ciphertexts, and secrets whose ciphertexts differ are shown as not compared, since secrets that\n" +
+			"were encrypted separately may still be equal.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := commandContext()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if otherStackName == "" && !deployed {
+				return errors.New("either --other or --deployed must be passed")
+			}
+
+			project, _, err := readProject()
+			if err != nil {
+				return err
+			}
+
+			currentStack, err := requireStack(ctx, *stack, stackLoadOnly, opts)
+			if err != nil {
+				return err
+			}
+			newConfig, newDecrypter, err := loadConfigForDiff(project, currentStack, stackConfigFile, showSecrets)
+			if err != nil {
+				return err
+			}
+
+			otherStack := currentStack
+			if otherStackName != "" {
+				if otherStackName == currentStack.Ref().Name().String() && !deployed {
+					return errors.New("current stack and other stack are the same")
+				}
+				if otherStack, err = requireStack(ctx, otherStackName, stackLoadOnly, opts); err != nil {
+					return err
+				}
+			}
+
+			var oldConfig config.Map
+			var oldDecrypter config.Decrypter
+			if deployed {
+				if oldConfig, err = backend.GetLatestConfiguration(ctx, otherStack); err != nil {
+					return fmt.Errorf("getting the config of the latest deployment of stack '%s': %w",
+						otherStack.Ref(), err)
+				}
+				if showSecrets && oldConfig.HasSecureValue() {
+					// The config of a deployment is encrypted with the secrets manager of its stack.
+					ps, err := loadDiffProjectStack(project, otherStack, diffConfigFile(otherStack, currentStack))
+					if err != nil {
+						return err
+					}
+					if oldDecrypter, _, err = getStackDecrypter(otherStack, ps); err != nil {
+						return fmt.Errorf("could not create a decrypter: %w", err)
+					}
+				}
+			} else {
+				oldConfig, oldDecrypter, err = loadConfigForDiff(
+					project, otherStack, diffConfigFile(otherStack, currentStack), showSecrets)
+				if err != nil {
+					return err
+				}
+			}
+
+			diffs, err := diffConfig(project, oldConfig, newConfig, oldDecrypter, newDecrypter)
+			if err != nil {
+				return err
+			}
+
+			if showSecrets {
+				log3rdPartySecretsProviderDecryptionEvent(ctx, currentStack, "", "pulumi config diff")
+			}
+
+			if jsonOut {
+				if diffs == nil {
+					diffs = []configDiffJSON{}
+				}
+				return printJSON(diffs)
+			}
+			printConfigDiff(diffs, opts)
+			return nil
+		}),
+	}
+
+	diffCmd.Flags().StringVarP(
+		&otherStackName, "other", "o", "",
+		"The name of the other stack to compare the current stack's config with")
+	diffCmd.Flags().BoolVar(
+		&deployed, "deployed", false,
+		"Compare with the config of the latest deployment of the stack")
+	diffCmd.Flags().BoolVar(
+		&showSecrets, "show-secrets", false,
+		"Decrypt and show secret values")
+	diffCmd.Flags().BoolVarP(
+		&jsonOut, "json", "j", false,
+		"Emit output as JSON")
+
+	return diffCmd
+}
+
+// diffConfigFile returns the config file to read the config of the given stack from: --config-file for the current
+// stack, and the stack's own config file for the other stack.
+func diffConfigFile(stack, currentStack backend.Stack) string {
+	if stack == currentStack {
+		return stackConfigFile
+	}
+	return ""
+}
+
+// loadDiffProjectStack loads the given stack's settings from configFile, or from the stack's config file if
+// configFile is empty.
+func loadDiffProjectStack(project *workspace.Project, stack backend.Stack,
+	configFile string,
+) (*workspace.ProjectStack, error) {
+	if configFile == "" {
+		return workspace.DetectProjectStack(stack.Ref().Name().Q())
+	}
+	return workspace.LoadProjectStack(project, configFile)
+}
+
+// loadConfigForDiff returns the config of the given stack, including its inherited config and the project's config,
+// along with a decrypter for its secrets if showSecrets is true and there are any. The stack's settings are read
+// from configFile, or from the stack's config file if configFile is empty.
+func loadConfigForDiff(project *workspace.Project, stack backend.Stack, configFile string,
+	showSecrets bool,
+) (config.Map, config.Decrypter, error) {
+	ps, err := loadDiffProjectStack(project, stack, configFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, _, err := mergeStackConfigLayers(stack, project, ps, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var decrypter config.Decrypter
+	if showSecrets && cfg.HasSecureValue() {
+		if cfg, decrypter, err = decryptConfigForDiff(project, stack, ps, configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Copy the config so that the project's config isn't added to the stack's.
+	merged := make(config.Map, len(cfg))
+	for k, v := range cfg {
+		merged[k] = v
+	}
+	if err = workspace.ApplyProjectConfig(stack.Ref().Name().String(), project, merged); err != nil {
+		return nil, nil, err
+	}
+	return merged, decrypter, nil
+}
+
+// decryptConfigForDiff returns the config of the given stack with the secrets manager of the stack, along with a
+// decrypter for its secrets.
+func decryptConfigForDiff(project *workspace.Project, stack backend.Stack, ps *workspace.ProjectStack,
+	configFile string,
+) (config.Map, config.Decrypter, error) {
+	sm, needsSave, err := getStackSecretsManager(stack, ps)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create a decrypter: %w", err)
+	}
+	// This may have setup the stack's secrets provider, so save the stack if needed.
+	if needsSave {
+		if configFile == "" {
+			err = workspace.SaveProjectStack(stack.Ref().Name().Q(), ps)
+		} else {
+			err = ps.Save(configFile)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("save stack config: %w", err)
+		}
+	}
+	// Inherited secrets may have been encrypted by other secrets managers.
+	cfg, _, err := mergeStackConfigLayers(stack, project, ps, sm)
+	if err != nil {
+		return nil, nil, err
+	}
+	decrypter, err := sm.Decrypter()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create a decrypter: %w", err)
+	}
+	return cfg, decrypter, nil
+}
+
+// diffConfig returns the differences between the old and new config, sorted by key. Secure values are decrypted with
+// the given decrypters, or compared by their ciphertexts if the decrypters are nil, in which case secrets whose
+// ciphertexts differ are marked as not compared.
+func diffConfig(project *workspace.Project, oldConfig, newConfig config.Map,
+	oldDecrypter, newDecrypter config.Decrypter,
+) ([]configDiffJSON, error) {
+	var keys config.KeyArray
+	for k := range oldConfig {
+		keys = append(keys, k)
+	}
+	for k := range newConfig {
+		if _, has := oldConfig[k]; !has {
+			keys = append(keys, k)
+		}
+	}
+	sort.Sort(keys)
+
+	var diffs []configDiffJSON
+	for _, k := range keys {
+		diff := configDiffJSON{Key: prettyKeyForProject(k, project)}
+
+		var oldCompare, newCompare string
+		oldValue, hasOld := oldConfig[k]
+		if hasOld {
+			compare, display, err := configDiffValue(oldValue, oldDecrypter)
+			if err != nil {
+				return nil, fmt.Errorf("reading the value of '%s': %w", diff.Key, err)
+			}
+			oldCompare, diff.Old = compare, display
+			diff.Secret = oldValue.Secure()
+		}
+		newValue, hasNew := newConfig[k]
+		if hasNew {
+			compare, display, err := configDiffValue(newValue, newDecrypter)
+			if err != nil {
+				return nil, fmt.Errorf("reading the value of '%s': %w", diff.Key, err)
+			}
+			newCompare, diff.New = compare, display
+			diff.Secret = diff.Secret || newValue.Secure()
+		}
+
+		switch {
+		case !hasOld:
+			diff.Kind = configDiffAdd
+		case !hasNew:
+			diff.Kind = configDiffRemove
+		case oldCompare != newCompare || oldValue.Secure() != newValue.Secure():
+			diff.Kind = configDiffChange
+			diff.NotCompared = oldValue.Secure() && newValue.Secure() && (diff.Old == nil || diff.New == nil)
+		default:
+			continue
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// configDiffValue returns the string to compare the given value by, and the string to show for it, which is nil for
+// secrets that aren't decrypted. References are compared and shown as they are written in config files.
+func configDiffValue(v config.Value, decrypter config.Decrypter) (string, *string, error) {
+	_, isRef := v.Reference()
+	if isRef || (v.Secure() && decrypter == nil) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", nil, err
+		}
+		compare := string(b)
+		if isRef {
+			return compare, &compare, nil
+		}
+		return compare, nil, nil
+	}
+
+	if decrypter == nil {
+		decrypter = config.NewPanicCrypter()
+	}
+	value, err := v.Value(decrypter)
+	if err != nil {
+		return "", nil, err
+	}
+	return value, &value, nil
+}
+
+func printConfigDiff(diffs []configDiffJSON, opts display.Options) {
+	if len(diffs) == 0 {
+		fmt.Println("No config differences")
+		return
+	}
+
+	show := func(v *string) string {
+		if v == nil {
+			return "[secret]"
+		}
+		return *v
+	}
+	for _, diff := range diffs {
+		var line string
+		switch {
+		case diff.Kind == configDiffAdd:
+			line = fmt.Sprintf("%s+ %s: %s", colors.SpecCreate, diff.Key, show(diff.New))
+		case diff.Kind == configDiffRemove:
+			line = fmt.Sprintf("%s- %s: %s", colors.SpecDelete, diff.Key, show(diff.Old))
+		case diff.NotCompared:
+			line = fmt.Sprintf("%s~ %s: [secret] (not compared)", colors.SpecUpdate, diff.Key)
+		default:
+			line = fmt.Sprintf("%s~ %s: %s => %s", colors.SpecUpdate, diff.Key, show(diff.Old), show(diff.New))
+		}
+		fmt.Println(opts.Color.Colorize(line + colors.Reset))
+	}
+}
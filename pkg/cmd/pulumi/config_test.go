@@ -15,10 +15,17 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/age"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
@@ -46,3 +53,195 @@ func TestSecretDetection(t *testing.T) {
 	// The key name does not match the pattern, so even though this "looks like" a secret, we say it is not.
 	assert.False(t, looksLikeSecret(config.MustMakeKey("test", "okay"), "1415fc1f4eaeb5e096ee58c1480016638fff29bf"))
 }
+
+//nolint:paralleltest // mutates the global stackConfigFile
+func TestLocateConfigError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Pulumi.dev.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("config:\n  test:name: db\n  test:size: 50\n"), 0o600))
+	stackConfigFile = path
+	defer func() { stackConfigFile = "" }()
+
+	typeName := "integer"
+	maximum := 10.0
+	proj := &workspace.Project{
+		Name:    tokens.PackageName("test"),
+		Runtime: workspace.NewProjectRuntimeInfo("nodejs", nil),
+		Config: map[string]workspace.ProjectConfigType{
+			"size": {
+				Type:                     &typeName,
+				ProjectConfigConstraints: workspace.ProjectConfigConstraints{Maximum: &maximum},
+			},
+		},
+	}
+	s := &backend.MockStack{}
+
+	ps, err := loadProjectStack(proj, s)
+	require.NoError(t, err)
+	configErr := workspace.ValidateStackConfigAndApplyProjectConfig("dev", proj, ps.Config, config.NewPanicCrypter())
+	require.Error(t, configErr)
+
+	err = locateConfigError(proj, s, configErr)
+	assert.EqualError(t, err, path+":3: Stack 'dev' with configuration key 'size' must be at most 10")
+	assert.ErrorIs(t, err, configErr)
+
+	// Other errors are returned unchanged.
+	other := errors.New("oops")
+	assert.Equal(t, other, locateConfigError(proj, s, other))
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestMergeStackConfigLayers(t *testing.T) {
+	dir := t.TempDir()
+	recipients := filepath.Join(dir, "recipients.txt")
+	identity := filepath.Join(dir, "identity.txt")
+	require.NoError(t, os.WriteFile(recipients,
+		[]byte("age1zvkyg2lqzraa2lnjvqej32nkuu0ues2s82hzrye869xeexvn73equnujwj\n"), 0o600))
+	require.NoError(t, os.WriteFile(identity,
+		[]byte("AGE-SECRET-KEY-1GFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPQ4EGAEX\n"), 0o600))
+	t.Setenv(age.IdentityFileEnvVar, identity)
+
+	proj := &workspace.Project{
+		Name:    tokens.PackageName("test"),
+		Runtime: workspace.NewProjectRuntimeInfo("nodejs", nil),
+	}
+
+	// The base file's secrets are encrypted with a different data key than the stack's.
+	base := &workspace.ProjectStack{Config: config.Map{}}
+	baseSM, err := age.NewAgeSecretsManager(base, age.Scheme+recipients, false)
+	require.NoError(t, err)
+	enc, err := baseSM.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue(context.Background(), "hunter2")
+	require.NoError(t, err)
+	base.Config[config.MustMakeKey("test", "password")] = config.NewSecureValue(ciphertext)
+	base.Config[config.MustMakeKey("test", "size")] = config.NewValue("small")
+	require.NoError(t, base.Save(filepath.Join(dir, "Pulumi.base.yaml")))
+
+	dev := &workspace.ProjectStack{
+		Extends: []string{"Pulumi.base.yaml"},
+		Config:  config.Map{config.MustMakeKey("test", "size"): config.NewValue("large")},
+	}
+	_, err = age.NewAgeSecretsManager(dev, age.Scheme+recipients, false)
+	require.NoError(t, err)
+	require.NotEqual(t, base.EncryptedKey, dev.EncryptedKey)
+	require.NoError(t, dev.Save(filepath.Join(dir, "Pulumi.dev.yaml")))
+
+	ps, err := workspace.LoadProjectStack(proj, filepath.Join(dir, "Pulumi.dev.yaml"))
+	require.NoError(t, err)
+	s := &backend.MockStack{}
+
+	cfg, origins, err := mergeStackConfigLayers(s, proj, ps, nil)
+	require.NoError(t, err)
+	assert.Equal(t, config.NewValue("large"), cfg[config.MustMakeKey("test", "size")])
+	assert.Equal(t, map[config.Key]string{
+		config.MustMakeKey("test", "password"): filepath.Join(dir, "Pulumi.base.yaml"),
+	}, origins)
+	assert.Len(t, ps.Config, 1)
+
+	// With the stack's secrets manager, inherited secrets are re-encrypted so that it can decrypt them.
+	sm, _, err := getStackSecretsManager(s, ps)
+	require.NoError(t, err)
+	cfg, _, err = mergeStackConfigLayers(s, proj, ps, sm)
+	require.NoError(t, err)
+	dec, err := sm.Decrypter()
+	require.NoError(t, err)
+	password, err := cfg[config.MustMakeKey("test", "password")].Value(dec)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", password)
+
+	// A file with secure values that doesn't say how they were encrypted can't be decrypted.
+	base.SecretsProvider, base.EncryptedKey = "", ""
+	require.NoError(t, base.Save(filepath.Join(dir, "Pulumi.base.yaml")))
+	_, _, err = mergeStackConfigLayers(s, proj, ps, sm)
+	assert.ErrorContains(t, err, "Pulumi.base.yaml contains secure values but does not declare the secrets settings")
+}
+
+func TestDiffConfig(t *testing.T) {
+	t.Parallel()
+
+	proj := &workspace.Project{Name: tokens.PackageName("test")}
+	encrypt := func(v string) config.Value {
+		ciphertext, err := config.Base64Crypter.EncryptValue(context.Background(), v)
+		require.NoError(t, err)
+		return config.NewSecureValue(ciphertext)
+	}
+
+	oldConfig := config.Map{
+		config.MustMakeKey("test", "removed"): config.NewValue("a"),
+		config.MustMakeKey("test", "same"):    config.NewValue("b"),
+		config.MustMakeKey("test", "changed"): config.NewValue("c"),
+		config.MustMakeKey("test", "secret"):  encrypt("d"),
+		config.MustMakeKey("test", "region"):  config.NewReferenceValue(config.Reference{FromEnv: "REGION"}),
+	}
+	newConfig := config.Map{
+		config.MustMakeKey("test", "same"):    config.NewValue("b"),
+		config.MustMakeKey("test", "changed"): config.NewValue("C"),
+		config.MustMakeKey("test", "secret"):  encrypt("e"),
+		config.MustMakeKey("test", "region"):  config.NewReferenceValue(config.Reference{FromEnv: "REGION"}),
+		config.MustMakeKey("aws", "added"):    config.NewValue("f"),
+	}
+
+	str := func(s string) *string { return &s }
+
+	// Without decrypters, secrets are compared by their ciphertexts and not shown.
+	diffs, err := diffConfig(proj, oldConfig, newConfig, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []configDiffJSON{
+		{Key: "aws:added", Kind: configDiffAdd, New: str("f")},
+		{Key: "changed", Kind: configDiffChange, Old: str("c"), New: str("C")},
+		{Key: "removed", Kind: configDiffRemove, Old: str("a")},
+		{Key: "secret", Kind: configDiffChange, Secret: true, NotCompared: true},
+	}, diffs)
+
+	diffs, err = diffConfig(proj, oldConfig, newConfig, config.Base64Crypter, config.Base64Crypter)
+	require.NoError(t, err)
+	assert.Contains(t, diffs, configDiffJSON{
+		Key: "secret", Kind: configDiffChange, Old: str("d"), New: str("e"), Secret: true,
+	})
+
+	// Secrets with the same ciphertexts are equal without decrypting them.
+	newConfig[config.MustMakeKey("test", "secret")] = encrypt("d")
+	diffs, err = diffConfig(proj, oldConfig, newConfig, nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, diffs, 3)
+
+	// Equal secrets aren't shown as changed once they're decrypted, even if they were encrypted separately.
+	nonceCrypter := config.NewSymmetricCrypter(make([]byte, config.SymmetricCrypterKeyBytes))
+	encryptWithNonce := func(v string) config.Value {
+		ciphertext, err := nonceCrypter.EncryptValue(context.Background(), v)
+		require.NoError(t, err)
+		return config.NewSecureValue(ciphertext)
+	}
+	oldConfig[config.MustMakeKey("test", "secret")] = encryptWithNonce("d")
+	newConfig[config.MustMakeKey("test", "secret")] = encryptWithNonce("d")
+	require.NotEqual(t, oldConfig[config.MustMakeKey("test", "secret")], newConfig[config.MustMakeKey("test", "secret")])
+	diffs, err = diffConfig(proj, oldConfig, newConfig, nonceCrypter, nonceCrypter)
+	require.NoError(t, err)
+	assert.Len(t, diffs, 3)
+	for _, diff := range diffs {
+		assert.NotEqual(t, "secret", diff.Key)
+	}
+
+	// Without decrypters, they can't be compared.
+	diffs, err = diffConfig(proj, oldConfig, newConfig, nil, nil)
+	require.NoError(t, err)
+	assert.Contains(t, diffs, configDiffJSON{Key: "secret", Kind: configDiffChange, Secret: true, NotCompared: true})
+}
+
+//nolint:paralleltest // mutates the global stackConfigFile
+func TestConfigDiffStacks(t *testing.T) {
+	// The current stack is selected by the parent command's --stack, and the other stack by --other.
+	cmd := newConfigCmd()
+	diffCmd, _, err := cmd.Find([]string{"diff"})
+	require.NoError(t, err)
+	require.NoError(t, cmd.PersistentFlags().Set("stack", "dev"))
+	assert.Equal(t, "dev", diffCmd.InheritedFlags().Lookup("stack").Value.String())
+	assert.NotNil(t, diffCmd.Flags().Lookup("other"))
+
+	// --config-file only applies to the current stack.
+	stackConfigFile = "Pulumi.custom.yaml"
+	defer func() { stackConfigFile = "" }()
+	current, other := &backend.MockStack{}, &backend.MockStack{}
+	assert.Equal(t, "Pulumi.custom.yaml", diffConfigFile(current, current))
+	assert.Equal(t, "", diffConfigFile(other, current))
+}
@@ -21,8 +21,10 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/age"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/cloud"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/deepcopy"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
@@ -59,7 +61,13 @@ func getStackSecretsManager(s backend.Stack, ps *workspace.ProjectStack) (secret
 
 	var sm secrets.Manager
 	var err error
-	if ps.SecretsProvider != passphrase.Type && ps.SecretsProvider != "default" && ps.SecretsProvider != "" {
+	if age.IsAgeURL(ps.SecretsProvider) {
+		sm, err = age.NewAgeSecretsManager(
+			ps, ps.SecretsProvider, false /* rotateSecretsProvider */)
+	} else if plugin.IsPluginURL(ps.SecretsProvider) {
+		sm, err = plugin.NewPluginSecretsManager(
+			ps, ps.SecretsProvider, false /* rotateSecretsProvider */)
+	} else if ps.SecretsProvider != passphrase.Type && ps.SecretsProvider != "default" && ps.SecretsProvider != "" {
 		sm, err = cloud.NewCloudSecretsManager(
 			ps, ps.SecretsProvider, false /* rotateSecretsProvider */)
 	} else if ps.EncryptionSalt != "" {
@@ -95,7 +103,9 @@ func needsSaveProjectStackAfterSecretManger(stack backend.Stack,
 
 func validateSecretsProvider(typ string) error {
 	kind := strings.SplitN(typ, ":", 2)[0]
-	supportedKinds := []string{"default", "passphrase", "awskms", "azurekeyvault", "gcpkms", "hashivault"}
+	supportedKinds := []string{
+		"default", "passphrase", "awskms", "azurekeyvault", "gcpkms", "hashivault", "plugin", "age",
+	}
 	for _, supportedKind := range supportedKinds {
 		if kind == supportedKind {
 			return nil
@@ -67,6 +67,7 @@ func newDestroyCmd() *cobra.Command {
 	var targets *[]string
 	var targetDependents bool
 	var excludeProtected bool
+	var continueOnError bool
 
 	use, cmdArgs := "destroy", cmdutil.NoArgs
 	if remoteSupported() {
@@ -147,6 +148,9 @@ func newDestroyCmd() *cobra.Command {
 				if err != nil {
 					return result.FromError(err)
 				}
+				if continueOnError {
+					return result.FromError(errors.New("--continue-on-error is not supported with --remote"))
+				}
 
 				return runDeployment(ctx, opts.Display, apitype.Destroy, stackName, args[0], remoteArgs)
 			}
@@ -214,7 +218,7 @@ func newDestroyCmd() *cobra.Command {
 			stackName := s.Ref().Name().String()
 			configError := workspace.ValidateStackConfigAndApplyProjectConfig(stackName, proj, cfg.Config, decrypter)
 			if configError != nil {
-				return result.FromError(fmt.Errorf("validating stack config: %w", configError))
+				return result.FromError(fmt.Errorf("validating stack config: %w", locateConfigError(proj, s, configError)))
 			}
 
 			refreshOption, err := getRefreshOption(proj, refresh)
@@ -256,6 +260,7 @@ func newDestroyCmd() *cobra.Command {
 				DisableResourceReferences: disableResourceReferences(),
 				DisableOutputValues:       disableOutputValues(),
 				Experimental:              hasExperimentalCommands(),
+				ContinueOnError:           continueOnError,
 			}
 
 			_, res := s.Destroy(ctx, backend.UpdateOperation{
@@ -325,6 +330,9 @@ func newDestroyCmd() *cobra.Command {
 		"Allows destroying of dependent targets discovered but not specified in --target list")
 	cmd.PersistentFlags().BoolVar(&excludeProtected, "exclude-protected", false, "Do not destroy protected resources."+
 		" Destroy all other resources.")
+	cmd.PersistentFlags().BoolVar(
+		&continueOnError, "continue-on-error", false,
+		"Continue destroying the resources that no failed resource depends on, and report all failures at the end")
 
 	// Flags for engine.UpdateOptions.
 	cmd.PersistentFlags().BoolVar(
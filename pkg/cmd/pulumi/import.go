@@ -387,6 +387,9 @@ func newImportCmd() *cobra.Command {
 
 	var from string
 
+	var discoverType string
+	var filters []string
+
 	cmd := &cobra.Command{
 		Use:   "import [type] [name] [id]",
 		Args:  cmdutil.MaximumNArgs(3),
@@ -459,7 +462,20 @@ func newImportCmd() *cobra.Command {
 			"Each resource may specify which input properties to import with;\n" +
 			"\n" +
 			"If a resource does not specify any properties the default behaviour is to\n" +
-			"import using all required properties.\n",
+			"import using all required properties.\n" +
+			"\n" +
+			"Input properties of the imported resources whose values are the IDs or ARNs of\n" +
+			"other imported resources are generated as references to these resources, and the\n" +
+			"generated definitions are ordered such that resources come after the resources\n" +
+			"they refer to.\n" +
+			"\n" +
+			"Resources of a given type may also be discovered by the default provider for\n" +
+			"their package, if it supports listing resources. The provider is configured using\n" +
+			"the stack's configuration, and only the resources whose properties have the values\n" +
+			"given by filters are listed. The resources to import are then chosen from the list,\n" +
+			"or all of them are imported if `--yes` is passed:\n" +
+			"\n" +
+			"    pulumi import --discover 'aws:ec2/vpc:Vpc' --filter 'tags.Env=dev'\n",
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
 			ctx := commandContext()
 
@@ -474,7 +490,16 @@ func newImportCmd() *cobra.Command {
 			}
 
 			var importFile importFile
-			if importFilePath != "" {
+			if discoverType != "" {
+				if len(args) != 0 || parentSpec != "" || providerSpec != "" || len(properties) != 0 {
+					return result.Errorf("an inline resource may not be specified in conjunction with --discover")
+				}
+				if importFilePath != "" || from != "" {
+					return result.Errorf("an import file or converter may not be specified in conjunction with --discover")
+				}
+			} else if len(filters) != 0 {
+				return result.Errorf("--filter may only be specified in conjunction with --discover")
+			} else if importFilePath != "" {
 				if len(args) != 0 || parentSpec != "" || providerSpec != "" || len(properties) != 0 {
 					return result.Errorf("an inline resource may not be specified in conjunction with an import file")
 				}
@@ -549,9 +574,14 @@ func newImportCmd() *cobra.Command {
 				output = f
 			}
 
-			imports, nameTable, err := parseImportFile(importFile, protectResources)
-			if err != nil {
-				return result.FromError(err)
+			// The resources to discover are parsed once they have been listed and chosen.
+			var imports []deploy.Import
+			var nameTable importer.NameTable
+			if discoverType == "" {
+				imports, nameTable, err = parseImportFile(importFile, protectResources)
+				if err != nil {
+					return result.FromError(err)
+				}
 			}
 
 			yes = yes || skipPreview || skipConfirmations()
@@ -648,7 +678,7 @@ func newImportCmd() *cobra.Command {
 			stackName := s.Ref().Name().String()
 			configErr := workspace.ValidateStackConfigAndApplyProjectConfig(stackName, proj, cfg.Config, decrypter)
 			if configErr != nil {
-				return result.FromError(fmt.Errorf("validating stack config: %w", configErr))
+				return result.FromError(fmt.Errorf("validating stack config: %w", locateConfigError(proj, s, configErr)))
 			}
 
 			opts.Engine = engine.UpdateOptions{
@@ -658,6 +688,22 @@ func newImportCmd() *cobra.Command {
 				Experimental:  hasExperimentalCommands(),
 			}
 
+			if discoverType != "" {
+				discoverFilters, err := parseImportFilters(filters)
+				if err != nil {
+					return result.FromError(err)
+				}
+				importFile, err = discoverImportFile(ctx, s, proj, root, cfg, opts, tokens.Type(discoverType),
+					discoverFilters, yes)
+				if err != nil {
+					return result.FromError(fmt.Errorf("discovering resources: %w", err))
+				}
+				imports, nameTable, err = parseImportFile(importFile, protectResources)
+				if err != nil {
+					return result.FromError(err)
+				}
+			}
+
 			_, res := s.Import(ctx, backend.UpdateOperation{
 				Proj:               proj,
 				Root:               root,
@@ -770,6 +816,12 @@ func newImportCmd() *cobra.Command {
 	cmd.PersistentFlags().StringVar(
 		&from, "from", "",
 		"Invoke a converter to import the resources")
+	cmd.PersistentFlags().StringVar(
+		&discoverType, "discover", "",
+		"Discover the existing resources of this type using the default provider for its package")
+	cmd.PersistentFlags().StringSliceVar(
+		&filters, "filter", nil,
+		"Only discover the resources whose properties have the given values, in the format key=value")
 
 	if hasDebugCommands() {
 		cmd.PersistentFlags().StringVar(
@@ -0,0 +1,201 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	surveycore "github.com/AlecAivazis/survey/v2/core"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// discoverInfo is the engine.UpdateInfo that is used to list the resources to import, which are listed using the
+// stack's configuration and plugins.
+type discoverInfo struct {
+	root   string
+	proj   *workspace.Project
+	target *deploy.Target
+}
+
+func (u *discoverInfo) GetRoot() string {
+	return u.root
+}
+
+func (u *discoverInfo) GetProject() *workspace.Project {
+	return u.proj
+}
+
+func (u *discoverInfo) GetTarget() *deploy.Target {
+	return u.target
+}
+
+// parseImportFilters parses filters of the form key=value.
+func parseImportFilters(filters []string) (map[string]string, error) {
+	result := make(map[string]string, len(filters))
+	for _, filter := range filters {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("filter %q must be of the form key=value", filter)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// makeImportFileFromListedResources returns an import file for the given resources of the given type. Resources are
+// named after the names suggested by their provider, or after their IDs if there are none. Names that are already
+// taken are made unique with a numeric suffix.
+func makeImportFileFromListedResources(typ tokens.Type, resources []plugin.ListedResource) importFile {
+	taken := map[string]bool{}
+	specs := make([]importSpec, len(resources))
+	for i, res := range resources {
+		name := res.Name
+		if name == "" {
+			name = string(res.ID)
+		}
+		unique := name
+		for n := 2; taken[unique]; n++ {
+			unique = fmt.Sprintf("%s-%d", name, n)
+		}
+		taken[unique] = true
+
+		specs[i] = importSpec{
+			Type: typ,
+			Name: tokens.QName(unique),
+			ID:   res.ID,
+		}
+	}
+
+	return importFile{
+		NameTable: map[string]resource.URN{},
+		Resources: specs,
+	}
+}
+
+// describeListedResource returns a one-line description of the primitive properties of a listed resource. Secrets
+// are not shown.
+func describeListedResource(res plugin.ListedResource) string {
+	var parts []string
+	for _, k := range res.Properties.StableKeys() {
+		v := res.Properties[k]
+		switch {
+		case v.IsSecret():
+			parts = append(parts, fmt.Sprintf("%v=[secret]", k))
+		case v.IsString(), v.IsNumber(), v.IsBool():
+			parts = append(parts, fmt.Sprintf("%v=%v", k, v.V))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// discoverImportFile lists the existing resources of the given type that match the given filters and returns an
+// import file for those chosen by the user. If yes is true, all of the listed resources are imported.
+func discoverImportFile(ctx context.Context, s backend.Stack, proj *workspace.Project, root string,
+	cfg backend.StackConfiguration, opts backend.UpdateOptions, typ tokens.Type, filters map[string]string,
+	yes bool,
+) (importFile, error) {
+	snap, err := s.Snapshot(ctx, stack.DefaultSecretsProvider)
+	if err != nil {
+		return importFile{}, err
+	}
+	info := &discoverInfo{
+		root: root,
+		proj: proj,
+		target: &deploy.Target{
+			Name:      s.Ref().Name(),
+			Config:    cfg.Config,
+			Decrypter: cfg.Decrypter,
+			Snapshot:  snap,
+		},
+	}
+
+	listed, err := engine.ListResources(info, opts.Engine, cmdutil.Diag(), typ, filters)
+	if err != nil {
+		return importFile{}, err
+	}
+	if len(listed) == 0 {
+		return importFile{}, fmt.Errorf("no resources of type %v were found", typ)
+	}
+
+	rows := make([]cmdutil.TableRow, len(listed))
+	for i, res := range listed {
+		rows[i] = cmdutil.TableRow{Columns: []string{res.Name, string(res.ID), describeListedResource(res)}}
+	}
+	fmt.Printf("Found %d resources of type %v:\n\n", len(listed), typ)
+	cmdutil.PrintTable(cmdutil.Table{
+		Headers: []string{"NAME", "ID", "PROPERTIES"},
+		Rows:    rows,
+	})
+	fmt.Println()
+
+	if !yes {
+		listed, err = chooseListedResources(listed, opts.Display)
+		if err != nil {
+			return importFile{}, err
+		}
+		if len(listed) == 0 {
+			return importFile{}, errors.New("no resources were chosen to import")
+		}
+	}
+	return makeImportFileFromListedResources(typ, listed), nil
+}
+
+// chooseListedResources prompts the user to choose the listed resources to import.
+func chooseListedResources(listed []plugin.ListedResource, opts display.Options) ([]plugin.ListedResource, error) {
+	options := make([]string, len(listed))
+	byOption := make(map[string]plugin.ListedResource, len(listed))
+	for i, res := range listed {
+		option := string(res.ID)
+		if res.Name != "" {
+			option = fmt.Sprintf("%s (%s)", res.Name, res.ID)
+		}
+		options[i] = option
+		byOption[option] = res
+	}
+
+	// Customize the prompt a little bit (and disable color since it doesn't match our scheme).
+	surveycore.DisableColor = true
+	message := opts.Color.Colorize(colors.SpecPrompt + "\rPlease choose the resources to import:" + colors.Reset)
+
+	var chosen []string
+	if err := survey.AskOne(&survey.MultiSelect{
+		Message: message,
+		Options: options,
+		Default: options,
+	}, &chosen, surveyIcons(opts.Color)); err != nil {
+		return nil, errors.New("no resources were chosen to import")
+	}
+
+	result := make([]plugin.ListedResource, len(chosen))
+	for i, option := range chosen {
+		result[i] = byOption[option]
+	}
+	return result, nil
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 )
 
 func TestParseImportFile_errors(t *testing.T) {
@@ -164,3 +166,36 @@ func TestParseImportFile_errors(t *testing.T) {
 		})
 	}
 }
+
+func TestParseImportFilters(t *testing.T) {
+	t.Parallel()
+
+	filters, err := parseImportFilters([]string{"tags.Env=dev", "name=a=b", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"tags.Env": "dev", "name": "a=b", "empty": ""}, filters)
+
+	_, err = parseImportFilters([]string{"tags.Env"})
+	assert.ErrorContains(t, err, `filter "tags.Env" must be of the form key=value`)
+
+	_, err = parseImportFilters([]string{"=dev"})
+	assert.ErrorContains(t, err, `filter "=dev" must be of the form key=value`)
+}
+
+func TestMakeImportFileFromListedResources(t *testing.T) {
+	t.Parallel()
+
+	f := makeImportFileFromListedResources("aws:ec2/vpc:Vpc", []plugin.ListedResource{
+		{ID: "vpc-1", Name: "main"},
+		{ID: "vpc-2", Name: "main"},
+		{ID: "vpc-3"},
+	})
+	assert.Equal(t, []importSpec{
+		{Type: "aws:ec2/vpc:Vpc", Name: "main", ID: "vpc-1"},
+		{Type: "aws:ec2/vpc:Vpc", Name: "main-2", ID: "vpc-2"},
+		{Type: "aws:ec2/vpc:Vpc", Name: "vpc-3", ID: "vpc-3"},
+	}, f.Resources)
+
+	imports, _, err := parseImportFile(f, true)
+	require.NoError(t, err)
+	assert.Len(t, imports, 3)
+}
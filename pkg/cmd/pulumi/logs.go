@@ -83,7 +83,7 @@ func newLogsCmd() *cobra.Command {
 			stackName := s.Ref().Name().String()
 			configErr := workspace.ValidateStackConfigAndApplyProjectConfig(stackName, proj, cfg.Config, decrypter)
 			if configErr != nil {
-				return fmt.Errorf("validating stack config: %w", configErr)
+				return fmt.Errorf("validating stack config: %w", locateConfigError(proj, s, configErr))
 			}
 
 			startTime, err := parseSince(since, time.Now())
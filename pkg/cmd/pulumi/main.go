@@ -20,6 +20,7 @@ import (
 	"runtime"
 	"runtime/debug"
 
+	"github.com/pulumi/pulumi/pkg/v3/secrets/plugin"
 	"github.com/pulumi/pulumi/pkg/v3/version"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 )
@@ -45,7 +46,10 @@ func panicHandler() {
 
 func main() {
 	defer panicHandler()
-	if err := NewPulumiCmd().Execute(); err != nil {
+	err := NewPulumiCmd().Execute()
+	// Shut down any secrets plugins that were loaded while running the command.
+	contract.IgnoreError(plugin.CloseAll())
+	if err != nil {
 		_, err = fmt.Fprintf(os.Stderr, "An error occurred: %v\n", err)
 		contract.IgnoreError(err)
 		os.Exit(1)
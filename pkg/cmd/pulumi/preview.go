@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/secretscan"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
@@ -68,6 +70,9 @@ func newPreviewCmd() *cobra.Command {
 	var replaces []string
 	var targetReplaces []string
 	var targetDependents bool
+	var scanSecrets bool
+	var scanSecretsOpts secretscan.Options
+	var blastRadius bool
 
 	use, cmdArgs := "preview", cmdutil.NoArgs
 	if remoteSupported() {
@@ -89,9 +94,19 @@ func newPreviewCmd() *cobra.Command {
 			"actually take place.\n" +
 			"\n" +
 			"The program to run is loaded from the project in the current directory. Use the `-C` or\n" +
-			"`--cwd` flag to use a different directory.",
+			"`--cwd` flag to use a different directory.\n" +
+			"\n" +
+			"Use `--blast-radius` to list, for every resource that would be replaced or deleted, the\n" +
+			"resources that transitively depend on it, whether they would also be replaced or merely\n" +
+			"updated, and which of them are protected. With `--json`, this report is written to stdout\n" +
+			"as JSON in place of the preview, which is written to stderr.",
 		Args: cmdArgs,
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			// The blast radius is computed from the plan of a local preview.
+			if blastRadius && remoteArgs.remote {
+				return result.FromError(errors.New("--blast-radius is not supported for remote operations"))
+			}
+
 			ctx := commandContext()
 			displayType := display.DisplayProgress
 			if diffDisplay {
@@ -112,6 +127,14 @@ func newPreviewCmd() *cobra.Command {
 				Debug:                debug,
 			}
 
+			// The blast radius report takes the place of the JSON preview on stdout, so the preview itself is
+			// displayed on stderr.
+			if blastRadius && jsonDisplay {
+				displayOpts.JSONDisplay = false
+				displayOpts.IsInteractive = false
+				displayOpts.Stdout = os.Stderr
+			}
+
 			// we only suppress permalinks if the user passes true. the default is an empty string
 			// which we pass as 'false'
 			if suppressPermalink == "true" {
@@ -184,7 +207,7 @@ func newPreviewCmd() *cobra.Command {
 			stackName := s.Ref().Name().String()
 			configErr := workspace.ValidateStackConfigAndApplyProjectConfig(stackName, proj, cfg.Config, decrypter)
 			if configErr != nil {
-				return result.FromError(fmt.Errorf("validating stack config: %w", configErr))
+				return result.FromError(fmt.Errorf("validating stack config: %w", locateConfigError(proj, s, configErr)))
 			}
 
 			if err != nil {
@@ -221,9 +244,11 @@ func newPreviewCmd() *cobra.Command {
 					UpdateTargets:             deploy.NewUrnTargets(targetURNs),
 					TargetDependents:          targetDependents,
 					// If we're trying to save a plan then we _need_ to generate it. We also turn this on in
-					// experimental mode to just get more testing of it.
-					GeneratePlan: hasExperimentalCommands() || planFilePath != "",
+					// experimental mode to just get more testing of it. The blast radius is computed from the plan's
+					// operations.
+					GeneratePlan: hasExperimentalCommands() || planFilePath != "" || blastRadius,
 					Experimental: hasExperimentalCommands(),
+					ScanSecrets:  scanSecretsOptions(scanSecrets, scanSecretsOpts),
 				},
 				Display: displayOpts,
 			}
@@ -245,6 +270,16 @@ func newPreviewCmd() *cobra.Command {
 			case expectNop && changes != nil && engine.HasChanges(changes):
 				return result.FromError(errors.New("error: no changes were expected but changes were proposed"))
 			default:
+				if blastRadius {
+					snap, err := s.Snapshot(ctx, stack.DefaultSecretsProvider)
+					if err != nil {
+						return result.FromError(err)
+					}
+					display.ShowBlastRadius(engine.NewBlastRadiusReport(snap, plan), display.Options{
+						Color:       displayOpts.Color,
+						JSONDisplay: jsonDisplay,
+					})
+				}
 				if planFilePath != "" {
 					encrypter, err := sm.Encrypter()
 					if err != nil {
@@ -319,6 +354,15 @@ func newPreviewCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&targetDependents, "target-dependents", false,
 		"Allows updating of dependent targets discovered but not specified in --target list")
+	cmd.PersistentFlags().BoolVar(
+		&scanSecrets, "scan-secrets", false,
+		"Warn about resource outputs that look like secrets but are not marked as secret. The detectors are "+
+			"configured with the --scan-secrets-* flags, as for `pulumi stack scan-secrets`")
+	addScanSecretsFlags(cmd.PersistentFlags(), &scanSecretsOpts, "scan-secrets-")
+	cmd.PersistentFlags().BoolVar(
+		&blastRadius, "blast-radius", false,
+		"List the resources affected by each replace or delete, distinguishing those that are also replaced "+
+			"from those that are updated and highlighting protected resources")
 
 	// Flags for engine.UpdateOptions.
 	cmd.PersistentFlags().StringSliceVar(
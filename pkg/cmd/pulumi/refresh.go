@@ -40,6 +40,7 @@ import (
 
 func newRefreshCmd() *cobra.Command {
 	var debug bool
+	var detectOnly bool
 	var expectNop bool
 	var message string
 	var execKind string
@@ -83,18 +84,36 @@ func newRefreshCmd() *cobra.Command {
 			"the program text isn't updated accordingly, subsequent updates may still appear to be out of\n" +
 			"synch with respect to the cloud provider's source of truth.\n" +
 			"\n" +
+			"Pass `--detect-only` to report drift between the stack's state and the cloud provider without\n" +
+			"adopting any changes. The command then exits with a non-zero exit code if any drift is found,\n" +
+			"and `--json` emits the report in a machine-readable form.\n" +
+			"\n" +
 			"The program to run is loaded from the project in the current directory. Use the `-C` or\n" +
 			"`--cwd` flag to use a different directory.",
 		Args: cmdArgs,
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
 			ctx := commandContext()
 
+			if detectOnly {
+				if remoteArgs.remote {
+					return result.FromError(errors.New("--detect-only is not supported for remote operations"))
+				}
+				if skipPreview {
+					return result.FromError(errors.New("cannot set both --detect-only and --skip-preview"))
+				}
+				if clearPendingCreates || len(*importPendingCreates) > 0 {
+					return result.FromError(errors.New(
+						"--detect-only cannot be combined with flags that edit pending creates"))
+				}
+			}
+
 			// Remote implies we're skipping previews.
 			if remoteArgs.remote {
 				skipPreview = true
 			}
 
-			yes = yes || skipPreview || skipConfirmations()
+			// Detecting drift never modifies the stack, so there is nothing to confirm.
+			yes = yes || skipPreview || detectOnly || skipConfirmations()
 			interactive := cmdutil.Interactive()
 			if !interactive && !yes {
 				return result.FromError(
@@ -106,10 +125,15 @@ func newRefreshCmd() *cobra.Command {
 				return result.FromError(err)
 			}
 
+			opts.PreviewOnly = detectOnly
+
 			displayType := display.DisplayProgress
 			if diffDisplay {
 				displayType = display.DisplayDiff
 			}
+			if detectOnly {
+				displayType = display.DisplayDrift
+			}
 
 			opts.Display = display.Options{
 				Color:                cmdutil.GetGlobalColorization(),
@@ -124,6 +148,12 @@ func newRefreshCmd() *cobra.Command {
 				JSONDisplay:          jsonDisplay,
 			}
 
+			// The exit code of --detect-only follows the drift report, rather than the changes of the refresh.
+			var driftReport engine.DriftReport
+			if detectOnly {
+				opts.Display.DriftReport = &driftReport
+			}
+
 			// we only suppress permalinks if the user passes true. the default is an empty string
 			// which we pass as 'false'
 			if suppressPermalink == "true" {
@@ -187,7 +217,7 @@ func newRefreshCmd() *cobra.Command {
 			stackName := s.Ref().Name().String()
 			configErr := workspace.ValidateStackConfigAndApplyProjectConfig(stackName, proj, cfg.Config, decrypter)
 			if configErr != nil {
-				return result.FromError(fmt.Errorf("validating stack config: %w", configErr))
+				return result.FromError(fmt.Errorf("validating stack config: %w", locateConfigError(proj, s, configErr)))
 			}
 
 			if skipPendingCreates && clearPendingCreates {
@@ -221,7 +251,7 @@ func newRefreshCmd() *cobra.Command {
 			}
 
 			// We then allow the user to interactively handle remaining pending creates.
-			if interactive && hasPendingCreates(snap) && !skipPendingCreates {
+			if interactive && hasPendingCreates(snap) && !skipPendingCreates && !detectOnly {
 				if result := filterMapPendingCreates(ctx, s, opts.Display,
 					yes, interactiveFixPendingCreate); result != nil {
 					return result
@@ -270,6 +300,8 @@ func newRefreshCmd() *cobra.Command {
 				return result.FromError(errors.New("refresh cancelled"))
 			case res != nil:
 				return PrintEngineResult(res)
+			case detectOnly && driftReport.HasDrift():
+				return result.FromError(errors.New("drift was detected"))
 			case expectNop && changes != nil && engine.HasChanges(changes):
 				return result.FromError(errors.New("error: no changes were expected but changes occurred"))
 			default:
@@ -281,6 +313,9 @@ func newRefreshCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVarP(
 		&debug, "debug", "d", false,
 		"Print detailed debugging output during resource operations")
+	cmd.PersistentFlags().BoolVar(
+		&detectOnly, "detect-only", false,
+		"Only report drift between the stack's state and the cloud provider, without changing the state")
 	cmd.PersistentFlags().BoolVar(
 		&expectNop, "expect-no-changes", false,
 		"Return an error if any changes occur during this update")
@@ -179,11 +179,13 @@ func newStackCmd() *cobra.Command {
 	cmd.Flags().BoolVar(
 		&showStackName, "show-name", false, "Display only the stack name")
 
+	cmd.AddCommand(newStackDiffCmd())
 	cmd.AddCommand(newStackExportCmd())
 	cmd.AddCommand(newStackGraphCmd())
 	cmd.AddCommand(newStackImportCmd())
 	cmd.AddCommand(newStackInitCmd())
 	cmd.AddCommand(newStackLsCmd())
+	cmd.AddCommand(newStackLockCmd())
 	cmd.AddCommand(newStackOutputCmd())
 	cmd.AddCommand(newStackRmCmd())
 	cmd.AddCommand(newStackSelectCmd())
@@ -191,6 +193,9 @@ func newStackCmd() *cobra.Command {
 	cmd.AddCommand(newStackRenameCmd())
 	cmd.AddCommand(newStackChangeSecretsProviderCmd())
 	cmd.AddCommand(newStackHistoryCmd())
+	cmd.AddCommand(newStackRollbackCmd())
+	cmd.AddCommand(newStackRotateSecretsCmd())
+	cmd.AddCommand(newStackScanSecretsCmd())
 	cmd.AddCommand(newStackUnselectCmd())
 
 	return cmd
@@ -207,6 +212,11 @@ func fprintStackOutputs(w io.Writer, outputs map[string]interface{}) error {
 		return err
 	}
 
+	return fprintStackOutputsTable(w, outputs)
+}
+
+// fprintStackOutputsTable prints a table of the given outputs, sorted by name.
+func fprintStackOutputsTable(w io.Writer, outputs map[string]interface{}) error {
 	outKeys := make([]string, 0, len(outputs))
 	for v := range outputs {
 		outKeys = append(outKeys, v)
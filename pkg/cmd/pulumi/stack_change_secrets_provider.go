@@ -37,7 +37,8 @@ func newStackChangeSecretsProviderCmd() *cobra.Command {
 		Args:  cmdutil.ExactArgs(1),
 		Short: "Change the secrets provider for a stack",
 		Long: "Change the secrets provider for a stack. " +
-			"Valid secret providers types are `default`, `passphrase`, `awskms`, `azurekeyvault`, `gcpkms`, `hashivault`.\n\n" +
+			"Valid secret providers types are `default`, `passphrase`, `awskms`, `azurekeyvault`, `gcpkms`, " +
+			"`hashivault`, `plugin`, `age`.\n\n" +
 			"To change to using the Pulumi Default Secrets Provider, use the following:\n" +
 			"\n" +
 			"pulumi stack change-secrets-provider default" +
@@ -53,7 +54,14 @@ func newStackChangeSecretsProviderCmd() *cobra.Command {
 			"\"azurekeyvault://mykeyvaultname.vault.azure.net/keys/mykeyname\"`\n" +
 			"* `pulumi stack change-secrets-provider " +
 			"\"gcpkms://projects/<p>/locations/<l>/keyRings/<r>/cryptoKeys/<k>\"`\n" +
-			"* `pulumi stack change-secrets-provider \"hashivault://mykey\"`",
+			"* `pulumi stack change-secrets-provider \"hashivault://mykey\"`\n" +
+			"* `pulumi stack change-secrets-provider \"plugin://mysecretsplugin?key=mykey\"`\n" +
+			"\n" +
+			"To encrypt the stack's secrets for each of the age public keys listed in a file, use the following.\n" +
+			"Relative paths are relative to the project's root directory. Run the same command again after\n" +
+			"editing the file to add or remove recipients:\n" +
+			"\n" +
+			"* `pulumi stack change-secrets-provider \"age://recipients.txt\"`",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := commandContext()
 			opts := display.Options{
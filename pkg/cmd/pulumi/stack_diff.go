@@ -0,0 +1,267 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newStackDiffCmd() *cobra.Command {
+	var versions []string
+	var outputs bool
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "diff [<stack-a>] [<stack-b>]",
+		Args:  cmdutil.MaximumNArgs(2),
+		Short: "Compare the resources of two stacks or of two versions of a stack",
+		Long: "Compare the resources of two stacks or of two versions of a stack.\n" +
+			"\n" +
+			"With two stack names, the state of <stack-a> is compared to the state of <stack-b>. With a single\n" +
+			"stack name, that stack is compared to the current stack.\n" +
+			"\n" +
+			"Use --version to compare versions from the history of a single stack instead (see\n" +
+			"`pulumi stack history`). With one --version, that version is compared to the latest state of\n" +
+			"the stack; with two, the first version is compared to the second. In this mode the optional\n" +
+			"argument names the stack to use, which defaults to the current stack.\n" +
+			"\n" +
+			"Resources are matched by their URN without the stack and project qualifiers, so resources\n" +
+			"with the same type and name in different stacks or projects are compared with each other.\n" +
+			"Resource inputs are compared by default; pass --outputs to compare outputs instead.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := commandContext()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			var olds, news *deploy.Snapshot
+			switch {
+			case len(versions) > 2:
+				return errors.New("at most two versions may be specified with --version")
+			case len(versions) > 0:
+				if len(args) > 1 {
+					return errors.New("only one stack may be specified when comparing versions")
+				}
+				stackName := ""
+				if len(args) == 1 {
+					stackName = args[0]
+				}
+				s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+				if err != nil {
+					return err
+				}
+				if olds, err = loadStackDiffSnapshot(ctx, s, versions[0]); err != nil {
+					return err
+				}
+				newVersion := ""
+				if len(versions) == 2 {
+					newVersion = versions[1]
+				}
+				if news, err = loadStackDiffSnapshot(ctx, s, newVersion); err != nil {
+					return err
+				}
+			default:
+				if len(args) == 0 {
+					return errors.New("a stack to compare with must be specified")
+				}
+				a, err := requireStack(ctx, args[0], stackLoadOnly, opts)
+				if err != nil {
+					return err
+				}
+				bName := ""
+				if len(args) == 2 {
+					bName = args[1]
+				}
+				b, err := requireStack(ctx, bName, stackLoadOnly, opts)
+				if err != nil {
+					return err
+				}
+				if olds, err = loadStackDiffSnapshot(ctx, a, ""); err != nil {
+					return err
+				}
+				if news, err = loadStackDiffSnapshot(ctx, b, ""); err != nil {
+					return err
+				}
+			}
+
+			diffs := diffSnapshots(olds, news, outputs)
+			if jsonOut {
+				return printJSON(diffs)
+			}
+			renderStackDiff(os.Stdout, diffs, opts)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringArrayVar(
+		&versions, "version", nil,
+		"A version of the stack to compare. May be specified twice")
+	cmd.PersistentFlags().BoolVar(
+		&outputs, "outputs", false, "Compare resource outputs instead of inputs")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonOut, "json", "j", false, "Emit the differences as JSON")
+	return cmd
+}
+
+// loadStackDiffSnapshot exports the deployment of the given stack and deserializes it. If version is empty, the latest
+// deployment is used; otherwise the backend must support exporting previous deployments.
+func loadStackDiffSnapshot(ctx context.Context, s backend.Stack, version string) (*deploy.Snapshot, error) {
+	deployment, err := exportStackDeployment(ctx, s, version)
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := stack.DeserializeUntypedDeployment(ctx, deployment, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, checkDeploymentVersionError(err, s.Ref().Name().String())
+	}
+	return snap, nil
+}
+
+// stackResourceDiff describes how a single resource differs between two stack states. The key identifies the
+// resource in both states: it is the resource's URN without the stack and project qualifiers.
+type stackResourceDiff struct {
+	Key    string                   `json:"key"`
+	Op     string                   `json:"op"` // one of "create", "delete", or "update".
+	OldURN resource.URN             `json:"oldUrn,omitempty"`
+	NewURN resource.URN             `json:"newUrn,omitempty"`
+	Paths  *engine.PropertyPathDiff `json:"paths,omitempty"`
+
+	olds resource.PropertyMap
+	news resource.PropertyMap
+	diff *resource.ObjectDiff
+}
+
+// stackDiffKey returns the key that is used to match resources across stacks.
+func stackDiffKey(urn resource.URN) string {
+	return string(urn.QualifiedType()) + "::" + string(urn.Name())
+}
+
+// diffSnapshots compares the live resources of two snapshots, matching them by stackDiffKey. Resources that are
+// equal in both snapshots, as well as the root stack resources, are omitted. The result is sorted by key.
+func diffSnapshots(olds, news *deploy.Snapshot, outputs bool) []stackResourceDiff {
+	properties := func(res *resource.State) resource.PropertyMap {
+		if outputs {
+			return res.Outputs
+		}
+		return res.Inputs
+	}
+	index := func(snap *deploy.Snapshot) map[string]*resource.State {
+		m := make(map[string]*resource.State)
+		if snap == nil {
+			return m
+		}
+		for _, res := range snap.Resources {
+			if res.Delete || res.Type == resource.RootStackType {
+				continue
+			}
+			m[stackDiffKey(res.URN)] = res
+		}
+		return m
+	}
+
+	oldResources, newResources := index(olds), index(news)
+
+	var diffs []stackResourceDiff
+	for key, old := range oldResources {
+		new, ok := newResources[key]
+		if !ok {
+			diffs = append(diffs, stackResourceDiff{
+				Key:    key,
+				Op:     string(deploy.OpDelete),
+				OldURN: old.URN,
+				olds:   properties(old),
+			})
+			continue
+		}
+
+		oldProps, newProps := properties(old), properties(new)
+		if paths := engine.DiffPropertyPaths(oldProps, newProps); paths != nil {
+			diffs = append(diffs, stackResourceDiff{
+				Key:    key,
+				Op:     string(deploy.OpUpdate),
+				OldURN: old.URN,
+				NewURN: new.URN,
+				Paths:  paths,
+				olds:   oldProps,
+				news:   newProps,
+				diff:   oldProps.Diff(newProps),
+			})
+		}
+	}
+	for key, new := range newResources {
+		if _, ok := oldResources[key]; !ok {
+			diffs = append(diffs, stackResourceDiff{
+				Key:    key,
+				Op:     string(deploy.OpCreate),
+				NewURN: new.URN,
+				news:   properties(new),
+			})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
+
+// renderStackDiff prints the differences between two stacks using the same object diff rendering as previews.
+func renderStackDiff(out io.Writer, diffs []stackResourceDiff, opts display.Options) {
+	if len(diffs) == 0 {
+		fmt.Fprint(out, opts.Color.Colorize(colors.SpecInfo+"No differences"+colors.Reset+"\n"))
+		return
+	}
+
+	var creates, deletes, updates int
+	var b bytes.Buffer
+	for _, d := range diffs {
+		switch d.Op {
+		case string(deploy.OpCreate):
+			creates++
+			b.WriteString(fmt.Sprintf("%s+ %s%s\n", colors.SpecCreate, d.Key, colors.Reset))
+			display.PrintObject(&b, d.news, false, 2, deploy.OpCreate, true, false, false)
+		case string(deploy.OpDelete):
+			deletes++
+			b.WriteString(fmt.Sprintf("%s- %s%s\n", colors.SpecDelete, d.Key, colors.Reset))
+			display.PrintObject(&b, d.olds, false, 2, deploy.OpDelete, true, false, false)
+		case string(deploy.OpUpdate):
+			updates++
+			b.WriteString(fmt.Sprintf("%s~ %s%s\n", colors.SpecUpdate, d.Key, colors.Reset))
+			display.PrintObjectDiff(&b, *d.diff, nil, false, 2, false, false, false)
+		}
+	}
+	b.WriteString(fmt.Sprintf("\n%sResources:%s %d added, %d deleted, %d changed\n",
+		colors.SpecHeadline, colors.Reset, creates, deletes, updates))
+
+	fmt.Fprint(out, opts.Color.Colorize(b.String()))
+}
@@ -0,0 +1,89 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	t.Parallel()
+
+	stackRes := func(stack, name string, inputs resource.PropertyMap) *resource.State {
+		return &resource.State{
+			URN:    resource.URN("urn:pulumi:" + stack + "::proj-" + stack + "::pkg:index:Thing::" + name),
+			Type:   "pkg:index:Thing",
+			Inputs: inputs,
+		}
+	}
+	root := func(stack string) *resource.State {
+		return &resource.State{
+			URN:  resource.URN("urn:pulumi:" + stack + "::proj-" + stack + "::pulumi:pulumi:Stack::proj-" + stack),
+			Type: resource.RootStackType,
+		}
+	}
+
+	staging := &deploy.Snapshot{Resources: []*resource.State{
+		root("staging"),
+		stackRes("staging", "same", resource.PropertyMap{"a": resource.NewNumberProperty(1)}),
+		stackRes("staging", "changed", resource.PropertyMap{
+			"a": resource.NewNumberProperty(1),
+			"b": resource.NewStringProperty("x"),
+		}),
+		stackRes("staging", "removed", resource.PropertyMap{}),
+	}}
+	prod := &deploy.Snapshot{Resources: []*resource.State{
+		root("prod"),
+		stackRes("prod", "same", resource.PropertyMap{"a": resource.NewNumberProperty(1)}),
+		stackRes("prod", "changed", resource.PropertyMap{
+			"a": resource.NewNumberProperty(2),
+			"c": resource.NewStringProperty("y"),
+		}),
+		stackRes("prod", "added", resource.PropertyMap{}),
+	}}
+
+	diffs := diffSnapshots(staging, prod, false)
+	require.Len(t, diffs, 3)
+
+	assert.Equal(t, "pkg:index:Thing::added", diffs[0].Key)
+	assert.Equal(t, "create", diffs[0].Op)
+	assert.Equal(t, resource.URN("urn:pulumi:prod::proj-prod::pkg:index:Thing::added"), diffs[0].NewURN)
+
+	assert.Equal(t, "pkg:index:Thing::changed", diffs[1].Key)
+	assert.Equal(t, "update", diffs[1].Op)
+	require.NotNil(t, diffs[1].Paths)
+	assert.Equal(t, []string{"c"}, diffs[1].Paths.Added)
+	assert.Equal(t, []string{"b"}, diffs[1].Paths.Removed)
+	assert.Equal(t, []string{"a"}, diffs[1].Paths.Changed)
+
+	assert.Equal(t, "pkg:index:Thing::removed", diffs[2].Key)
+	assert.Equal(t, "delete", diffs[2].Op)
+	assert.Equal(t, resource.URN("urn:pulumi:staging::proj-staging::pkg:index:Thing::removed"), diffs[2].OldURN)
+
+	var b bytes.Buffer
+	renderStackDiff(&b, diffs, display.Options{Color: colors.Never})
+	assert.Contains(t, b.String(), "~ pkg:index:Thing::changed")
+	assert.Contains(t, b.String(), "Resources: 1 added, 1 deleted, 1 changed")
+
+	assert.Empty(t, diffSnapshots(staging, staging, false))
+}
@@ -15,8 +15,10 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
@@ -56,27 +58,9 @@ func newStackExportCmd() *cobra.Command {
 				return err
 			}
 
-			var deployment *apitype.UntypedDeployment
-			// Export the latest version of the checkpoint by default. Otherwise, we require that
-			// the backend/stack implements the ability the export previous checkpoints.
-			if version == "" {
-				deployment, err = s.ExportDeployment(ctx)
-				if err != nil {
-					return err
-				}
-			} else {
-				// Check that the stack and its backend supports the ability to do this.
-				be := s.Backend()
-				specificExpBE, ok := be.(backend.SpecificDeploymentExporter)
-				if !ok {
-					return fmt.Errorf("the current backend (%s) does not provide the ability to export previous deployments",
-						be.Name())
-				}
-
-				deployment, err = specificExpBE.ExportDeploymentForVersion(ctx, s, version)
-				if err != nil {
-					return err
-				}
+			deployment, err := exportStackDeployment(ctx, s, version)
+			if err != nil {
+				return err
 			}
 
 			// Read from stdin or a specified file.
@@ -90,39 +74,16 @@ func newStackExportCmd() *cobra.Command {
 
 			if showSecrets {
 				// log show secrets event
-				snap, err := stack.DeserializeUntypedDeployment(ctx, deployment, stack.DefaultSecretsProvider)
-				if err != nil {
-					return checkDeploymentVersionError(err, stackName)
-				}
-
-				serializedDeployment, err := stack.SerializeDeployment(snap, snap.SecretsManager, true)
-				if err != nil {
-					return err
-				}
-
-				data, err := json.Marshal(serializedDeployment)
+				deployment, err = revealDeploymentSecrets(ctx, deployment, stackName)
 				if err != nil {
 					return err
 				}
 
-				deployment = &apitype.UntypedDeployment{
-					Version:    3,
-					Deployment: data,
-				}
-
 				log3rdPartySecretsProviderDecryptionEvent(ctx, s, "", "pulumi stack export")
 			}
 
 			// Write the deployment.
-			enc := json.NewEncoder(writer)
-			enc.SetEscapeHTML(false)
-			enc.SetIndent("", "    ")
-
-			if err = enc.Encode(deployment); err != nil {
-				return fmt.Errorf("could not export deployment: %w", err)
-			}
-
-			return nil
+			return writeDeployment(writer, deployment)
 		}),
 	}
 	cmd.PersistentFlags().StringVarP(
@@ -135,3 +96,61 @@ func newStackExportCmd() *cobra.Command {
 		&showSecrets, "show-secrets", "", false, "Emit secrets in plaintext in exported stack. Defaults to `false`")
 	return cmd
 }
+
+// exportStackDeployment exports the deployment of the given stack. If version is empty, the latest version of the
+// checkpoint is exported. Otherwise, we require that the backend/stack implements the ability the export previous
+// checkpoints.
+func exportStackDeployment(
+	ctx context.Context, s backend.Stack, version string,
+) (*apitype.UntypedDeployment, error) {
+	if version == "" {
+		return s.ExportDeployment(ctx)
+	}
+
+	// Check that the stack and its backend supports the ability to do this.
+	be := s.Backend()
+	specificExpBE, ok := be.(backend.SpecificDeploymentExporter)
+	if !ok {
+		return nil, fmt.Errorf("the current backend (%s) does not provide the ability to export previous deployments",
+			be.Name())
+	}
+
+	return specificExpBE.ExportDeploymentForVersion(ctx, s, version)
+}
+
+// revealDeploymentSecrets returns a copy of the given deployment with all secrets in plaintext.
+func revealDeploymentSecrets(
+	ctx context.Context, deployment *apitype.UntypedDeployment, stackName string,
+) (*apitype.UntypedDeployment, error) {
+	snap, err := stack.DeserializeUntypedDeployment(ctx, deployment, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, checkDeploymentVersionError(err, stackName)
+	}
+
+	serializedDeployment, err := stack.SerializeDeployment(snap, snap.SecretsManager, true)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(serializedDeployment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apitype.UntypedDeployment{
+		Version:    3,
+		Deployment: data,
+	}, nil
+}
+
+// writeDeployment writes the given deployment to w as indented JSON.
+func writeDeployment(w io.Writer, deployment *apitype.UntypedDeployment) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+
+	if err := enc.Encode(deployment); err != nil {
+		return fmt.Errorf("could not export deployment: %w", err)
+	}
+	return nil
+}
@@ -16,15 +16,23 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/graph"
+	"github.com/pulumi/pulumi/pkg/v3/graph/cytoscapeconv"
 	"github.com/pulumi/pulumi/pkg/v3/graph/dotconv"
+	"github.com/pulumi/pulumi/pkg/v3/graph/graphmlconv"
+	"github.com/pulumi/pulumi/pkg/v3/graph/jsonconv"
+	"github.com/pulumi/pulumi/pkg/v3/graph/mermaidconv"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/spf13/cobra"
 )
@@ -44,8 +52,38 @@ var parentEdgeColor string
 // Whether or not to return resource name as the node label for each node of the graph.
 var shortNodeName bool
 
+// Whether or not we should include edges from providers to the resources that they manage.
+var includeProviderEdges bool
+
+// The color of provider edges in the graph. Defaults to #5C4B8A, a purple.
+var providerEdgeColor string
+
+// graphPrinters are the functions that print graphs in each of the formats that `pulumi stack graph` supports.
+var graphPrinters = map[string]func(graph.Graph, io.Writer) error{
+	"dot":       dotconv.Print,
+	"mermaid":   mermaidconv.Print,
+	"graphml":   graphmlconv.Print,
+	"json":      jsonconv.Print,
+	"cytoscape": cytoscapeconv.Print,
+}
+
+// graphFilter selects the resources to include in a stack's dependency graph. The zero value selects all resources.
+type graphFilter struct {
+	types   []string          // if non-empty, only resources of these types are included.
+	urns    deploy.UrnTargets // only resources whose URNs match these URNs or globs are included.
+	subtree resource.URN      // if non-empty, only this resource and its descendants are included.
+	focus   resource.URN      // if non-empty, only resources within depth edges of this resource are included.
+	depth   int               // the maximum number of edges between the focus and included resources.
+}
+
 func newStackGraphCmd() *cobra.Command {
 	var stackName string
+	var format string
+	var types []string
+	var urns []string
+	var subtree string
+	var focus string
+	var depth int
 
 	cmd := &cobra.Command{
 		Use:   "graph [filename]",
@@ -54,10 +92,30 @@ func newStackGraphCmd() *cobra.Command {
 		Long: "Export a stack's dependency graph to a file.\n" +
 			"\n" +
 			"This command can be used to view the dependency graph that a Pulumi program\n" +
-			"emitted when it was run. This graph is output in the DOT format. This command operates\n" +
-			"on your stack's most recent deployment.",
+			"emitted when it was run. This graph is output in the DOT format by default, or in\n" +
+			"the Mermaid, GraphML, JSON adjacency list or Cytoscape.js format given by `--format`.\n" +
+			"This command operates on your stack's most recent deployment.\n" +
+			"\n" +
+			"The graph may be limited to resources of some types (`--type`), to resources whose\n" +
+			"URNs match some URNs or globs (`--urn`), to a resource and its descendants\n" +
+			"(`--subtree`), or to the resources within a number of edges of a resource\n" +
+			"(`--focus` and `--depth`). Dependency edges are labeled with the properties that\n" +
+			"hold each dependency.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := commandContext()
+
+			printGraph, ok := graphPrinters[format]
+			if !ok {
+				return fmt.Errorf("unknown graph format %q; expected one of dot, mermaid, graphml, json or cytoscape",
+					format)
+			}
+			if depth < 0 {
+				return fmt.Errorf("--depth must not be negative")
+			}
+			if cmd.Flags().Changed("depth") && focus == "" {
+				return fmt.Errorf("--depth may only be specified in conjunction with --focus")
+			}
+
 			opts := display.Options{
 				Color: cmdutil.GetGlobalColorization(),
 			}
@@ -76,13 +134,22 @@ func newStackGraphCmd() *cobra.Command {
 				return fmt.Errorf("unable to find snapshot for stack %q", stackName)
 			}
 
-			dg := makeDependencyGraph(snap)
+			dg, err := makeDependencyGraph(snap, graphFilter{
+				types:   types,
+				urns:    deploy.NewUrnTargets(urns),
+				subtree: resource.URN(subtree),
+				focus:   resource.URN(focus),
+				depth:   depth,
+			})
+			if err != nil {
+				return err
+			}
 			file, err := os.Create(args[0])
 			if err != nil {
 				return err
 			}
 
-			if err := dotconv.Print(dg, file); err != nil {
+			if err := printGraph(dg, file); err != nil {
 				_ = file.Close()
 				return err
 			}
@@ -104,6 +171,22 @@ func newStackGraphCmd() *cobra.Command {
 		"Sets the color of parent edges in the graph")
 	cmd.PersistentFlags().BoolVar(&shortNodeName, "short-node-name", false,
 		"Sets the resource name as the node label for each node of the graph")
+	cmd.PersistentFlags().BoolVar(&includeProviderEdges, "include-provider-edges", false,
+		"Includes edges from providers to the resources that they manage")
+	cmd.PersistentFlags().StringVar(&providerEdgeColor, "provider-edge-color", "#5C4B8A",
+		"Sets the color of provider edges in the graph")
+	cmd.PersistentFlags().StringVar(&format, "format", "dot",
+		"The format of the graph: dot, mermaid, graphml, json or cytoscape")
+	cmd.PersistentFlags().StringSliceVar(&types, "type", nil,
+		"Only include resources of the given types")
+	cmd.PersistentFlags().StringSliceVar(&urns, "urn", nil,
+		"Only include resources whose URNs match the given URNs or globs, which may contain wildcards (*)")
+	cmd.PersistentFlags().StringVar(&subtree, "subtree", "",
+		"Only include the resource with the given URN and its descendants")
+	cmd.PersistentFlags().StringVar(&focus, "focus", "",
+		"Only include the resources within --depth edges of the resource with the given URN")
+	cmd.PersistentFlags().IntVar(&depth, "depth", 1,
+		"The maximum number of edges between the --focus resource and the included resources")
 	return cmd
 }
 
@@ -169,6 +252,35 @@ func (edge *parentEdge) Color() string {
 	return parentEdgeColor
 }
 
+// providerEdges represent edges from providers to the resources that they
+// manage. An edge exists from node A to node B if node A is the provider of
+// node B.
+type providerEdge struct {
+	to   *dependencyVertex
+	from *dependencyVertex
+}
+
+func (edge *providerEdge) Data() interface{} {
+	return nil
+}
+
+// In this simple case, edges have no label.
+func (edge *providerEdge) Label() string {
+	return ""
+}
+
+func (edge *providerEdge) To() graph.Vertex {
+	return edge.to
+}
+
+func (edge *providerEdge) From() graph.Vertex {
+	return edge.from
+}
+
+func (edge *providerEdge) Color() string {
+	return providerEdgeColor
+}
+
 // A dependencyVertex contains a reference to the graph to which it belongs
 // and to the resource state that it represents. Incoming and outgoing edges
 // are calculated on-demand using the combination of the graph and the state.
@@ -205,13 +317,14 @@ func (vertex *dependencyVertex) Outs() []graph.Edge {
 // the graph. It is constructed directly from a snapshot.
 type dependencyGraph struct {
 	vertices map[resource.URN]*dependencyVertex
+	order    []*dependencyVertex // the vertices in the order of their resources in the snapshot.
 }
 
 // Roots are edges that point to the root set of our graph. In our case,
 // for simplicity, we define the root set of our dependency graph to be everything.
 func (dg *dependencyGraph) Roots() []graph.Edge {
 	rootEdges := []graph.Edge{}
-	for _, vertex := range dg.vertices {
+	for _, vertex := range dg.order {
 		edge := &dependencyEdge{
 			to:   vertex,
 			from: nil,
@@ -224,55 +337,214 @@ func (dg *dependencyGraph) Roots() []graph.Edge {
 }
 
 // Makes a dependency graph from a deployment snapshot, allocating a vertex
-// for every resource in the graph.
-func makeDependencyGraph(snapshot *deploy.Snapshot) *dependencyGraph {
+// for every resource in the graph that the given filter selects.
+func makeDependencyGraph(snapshot *deploy.Snapshot, filter graphFilter) (*dependencyGraph, error) {
+	selected, err := filter.selectResources(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
 	dg := &dependencyGraph{
 		vertices: make(map[resource.URN]*dependencyVertex),
 	}
 
 	for _, resource := range snapshot.Resources {
+		if !selected[resource.URN] {
+			continue
+		}
 		vertex := &dependencyVertex{
 			graph:    dg,
 			resource: resource,
 		}
 
 		dg.vertices[resource.URN] = vertex
+		dg.order = append(dg.order, vertex)
 	}
 
-	for _, vertex := range dg.vertices {
-		if !ignoreDependencyEdges {
-			// If we have per-property dependency information, annotate the dependency edges
-			// we generate with the names of the properties associated with each dependency.
-			depBlame := make(map[resource.URN][]string)
-			for k, deps := range vertex.resource.PropertyDependencies {
-				for _, dep := range deps {
-					depBlame[dep] = append(depBlame[dep], string(k))
+	// Edges are only added between selected resources.
+	addEdge := func(from, to resource.URN, makeEdge func(from, to *dependencyVertex) graph.Edge) {
+		fromVertex, toVertex := dg.vertices[from], dg.vertices[to]
+		if fromVertex == nil || toVertex == nil {
+			return
+		}
+		edge := makeEdge(fromVertex, toVertex)
+		fromVertex.outgoingEdges = append(fromVertex.outgoingEdges, edge)
+		toVertex.incomingEdges = append(toVertex.incomingEdges, edge)
+	}
+
+	for _, vertex := range dg.order {
+		state := vertex.resource
+		for _, dep := range resourceEdges(state) {
+			switch dep.kind {
+			case dependencyEdgeKind:
+				// Dependency edges point from the resources on which this vertex depends to this vertex, and are
+				// labeled with the names of the properties associated with each dependency.
+				labels := dep.properties
+				addEdge(dep.urn, state.URN, func(from, to *dependencyVertex) graph.Edge {
+					return &dependencyEdge{to: to, from: from, labels: labels}
+				})
+			case parentEdgeKind:
+				// alongside the dependency graph sits the resource parentage graph, which
+				// is also displayed as part of this graph, although with different colored
+				// edges.
+				addEdge(state.URN, dep.urn, func(from, to *dependencyVertex) graph.Edge {
+					return &parentEdge{to: to, from: from}
+				})
+			case providerEdgeKind:
+				addEdge(dep.urn, state.URN, func(from, to *dependencyVertex) graph.Edge {
+					return &providerEdge{to: to, from: from}
+				})
+			}
+		}
+	}
+
+	return dg, nil
+}
+
+type resourceEdgeKind int
+
+const (
+	dependencyEdgeKind resourceEdgeKind = iota
+	parentEdgeKind
+	providerEdgeKind
+)
+
+// A resourceEdge is a relationship between a resource and another resource, e.g. one of its dependencies.
+type resourceEdge struct {
+	kind       resourceEdgeKind
+	urn        resource.URN // the URN of the other resource.
+	properties []string     // for dependencies, the names of the properties that hold the dependency.
+}
+
+// resourceEdges returns the relationships of the given resource to other resources that are included in the graph,
+// according to the edge flags.
+func resourceEdges(state *resource.State) []resourceEdge {
+	var edges []resourceEdge
+	if !ignoreDependencyEdges {
+		// If we have per-property dependency information, annotate the dependency edges
+		// we generate with the names of the properties associated with each dependency.
+		// Property dependencies that are missing from the resource's dependencies are
+		// included as well.
+		depBlame := make(map[resource.URN][]string)
+		deps := append([]resource.URN(nil), state.Dependencies...)
+		keys := make([]string, 0, len(state.PropertyDependencies))
+		for k := range state.PropertyDependencies {
+			keys = append(keys, string(k))
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, dep := range state.PropertyDependencies[resource.PropertyKey(k)] {
+				if _, has := depBlame[dep]; !has && !containsURN(state.Dependencies, dep) {
+					deps = append(deps, dep)
 				}
+				depBlame[dep] = append(depBlame[dep], k)
 			}
+		}
+		for _, dep := range deps {
+			edges = append(edges, resourceEdge{kind: dependencyEdgeKind, urn: dep, properties: depBlame[dep]})
+		}
+	}
+	if !ignoreParentEdges && state.Parent != "" {
+		edges = append(edges, resourceEdge{kind: parentEdgeKind, urn: state.Parent})
+	}
+	if includeProviderEdges && state.Provider != "" {
+		if ref, err := providers.ParseReference(state.Provider); err == nil {
+			edges = append(edges, resourceEdge{kind: providerEdgeKind, urn: ref.URN()})
+		}
+	}
+	return edges
+}
+
+func containsURN(urns []resource.URN, urn resource.URN) bool {
+	for _, u := range urns {
+		if u == urn {
+			return true
+		}
+	}
+	return false
+}
 
-			// Incoming edges are directly stored within the checkpoint file; they represent
-			// resources on which this vertex immediately depends upon.
-			for _, dep := range vertex.resource.Dependencies {
-				vertexWeDependOn := vertex.graph.vertices[dep]
-				edge := &dependencyEdge{to: vertex, from: vertexWeDependOn, labels: depBlame[dep]}
-				vertex.incomingEdges = append(vertex.incomingEdges, edge)
-				vertexWeDependOn.outgoingEdges = append(vertexWeDependOn.outgoingEdges, edge)
+// selectResources returns the URNs of the resources in the given snapshot that the filter selects.
+func (f graphFilter) selectResources(snapshot *deploy.Snapshot) (map[resource.URN]bool, error) {
+	byURN := make(map[resource.URN]*resource.State, len(snapshot.Resources))
+	for _, res := range snapshot.Resources {
+		byURN[res.URN] = res
+	}
+	for _, urn := range []resource.URN{f.subtree, f.focus} {
+		if _, has := byURN[urn]; urn != "" && !has {
+			return nil, fmt.Errorf("no resource with URN %v was found in the stack", urn)
+		}
+	}
+
+	// isInSubtree returns true if the resource is the subtree's root or one of its descendants.
+	isInSubtree := func(res *resource.State) bool {
+		for visited := map[resource.URN]bool{}; res != nil && !visited[res.URN]; res = byURN[res.Parent] {
+			if res.URN == f.subtree {
+				return true
 			}
+			visited[res.URN] = true
 		}
+		return false
+	}
 
-		// alongside the dependency graph sits the resource parentage graph, which
-		// is also displayed as part of this graph, although with different colored
-		// edges.
-		if !ignoreParentEdges {
-			if parent := vertex.resource.Parent; parent != resource.URN("") {
-				parentVertex := dg.vertices[parent]
-				vertex.outgoingEdges = append(vertex.outgoingEdges, &parentEdge{
-					to:   parentVertex,
-					from: vertex,
-				})
+	var neighborhood map[resource.URN]bool
+	if f.focus != "" {
+		neighborhood = f.neighborhood(snapshot)
+	}
+
+	selected := make(map[resource.URN]bool)
+	for _, res := range snapshot.Resources {
+		switch {
+		case len(f.types) != 0 && !containsType(f.types, res.Type):
+		case !f.urns.Contains(res.URN):
+		case f.subtree != "" && !isInSubtree(res):
+		case neighborhood != nil && !neighborhood[res.URN]:
+		default:
+			selected[res.URN] = true
+		}
+	}
+	return selected, nil
+}
+
+// neighborhood returns the URNs of the resources within the filter's depth of its focus, following the edges that
+// are included in the graph in either direction.
+func (f graphFilter) neighborhood(snapshot *deploy.Snapshot) map[resource.URN]bool {
+	adjacent := make(map[resource.URN][]resource.URN)
+	for _, res := range snapshot.Resources {
+		for _, edge := range resourceEdges(res) {
+			adjacent[res.URN] = append(adjacent[res.URN], edge.urn)
+			adjacent[edge.urn] = append(adjacent[edge.urn], res.URN)
+		}
+	}
+
+	distances := map[resource.URN]int{f.focus: 0}
+	frontier := []resource.URN{f.focus}
+	for len(frontier) > 0 {
+		urn := frontier[0]
+		frontier = frontier[1:]
+		if distances[urn] == f.depth {
+			continue
+		}
+		for _, next := range adjacent[urn] {
+			if _, has := distances[next]; !has {
+				distances[next] = distances[urn] + 1
+				frontier = append(frontier, next)
 			}
 		}
 	}
 
-	return dg
+	neighborhood := make(map[resource.URN]bool, len(distances))
+	for urn := range distances {
+		neighborhood[urn] = true
+	}
+	return neighborhood
+}
+
+func containsType(types []string, typ tokens.Type) bool {
+	for _, t := range types {
+		if tokens.Type(t) == typ {
+			return true
+		}
+	}
+	return false
 }
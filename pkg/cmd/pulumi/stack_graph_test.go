@@ -0,0 +1,218 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/graph"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+// graphSnapshot returns a snapshot with a provider, a component and two children of the component, the second of
+// which depends on the first.
+func graphSnapshot(t *testing.T) *deploy.Snapshot {
+	newURN := func(typ tokens.Type, name string) resource.URN {
+		return resource.NewURN("stack", "proj", "", typ, tokens.QName(name))
+	}
+
+	provURN := newURN("pulumi:providers:aws", "default")
+	provRef, err := providers.NewReference(provURN, "prov-id")
+	require.NoError(t, err)
+
+	component := newURN("my:index:Component", "app")
+	vpc := newURN("aws:ec2/vpc:Vpc", "vpc")
+	subnet := newURN("aws:ec2/subnet:Subnet", "subnet")
+	return &deploy.Snapshot{
+		Resources: []*resource.State{
+			{URN: provURN, Type: "pulumi:providers:aws", Custom: true, ID: "prov-id"},
+			{URN: component, Type: "my:index:Component"},
+			{URN: vpc, Type: "aws:ec2/vpc:Vpc", Custom: true, Parent: component, Provider: provRef.String()},
+			{
+				URN:          subnet,
+				Type:         "aws:ec2/subnet:Subnet",
+				Custom:       true,
+				Parent:       component,
+				Provider:     provRef.String(),
+				Dependencies: []resource.URN{vpc},
+				PropertyDependencies: map[resource.PropertyKey][]resource.URN{
+					"vpcId":     {vpc},
+					"cidrBlock": {vpc},
+				},
+			},
+		},
+	}
+}
+
+// graphLabels returns the labels of the vertices of the given graph and of their outgoing edges.
+func graphLabels(dg *dependencyGraph) ([]string, map[string][]string) {
+	var vertices []string
+	edges := map[string][]string{}
+	for _, v := range graph.Vertices(dg) {
+		vertices = append(vertices, v.Label())
+		for _, out := range v.Outs() {
+			edges[v.Label()] = append(edges[v.Label()], out.To().Label()+" "+out.Label())
+		}
+	}
+	return vertices, edges
+}
+
+//nolint:paralleltest // changes global flags
+func TestMakeDependencyGraph(t *testing.T) {
+	snap := graphSnapshot(t)
+
+	shortNodeName, includeProviderEdges = true, true
+	defer func() { shortNodeName, includeProviderEdges = false, false }()
+
+	dg, err := makeDependencyGraph(snap, graphFilter{})
+	require.NoError(t, err)
+	vertices, edges := graphLabels(dg)
+	assert.Equal(t, []string{"default", "app", "vpc", "subnet"}, vertices)
+	assert.Equal(t, map[string][]string{
+		"default": {"vpc ", "subnet "},
+		"vpc":     {"app ", "subnet cidrBlock, vpcId"},
+		"subnet":  {"app "},
+	}, edges)
+
+	// Filter by type.
+	dg, err = makeDependencyGraph(snap, graphFilter{types: []string{"aws:ec2/vpc:Vpc", "aws:ec2/subnet:Subnet"}})
+	require.NoError(t, err)
+	vertices, edges = graphLabels(dg)
+	assert.Equal(t, []string{"vpc", "subnet"}, vertices)
+	assert.Equal(t, map[string][]string{"vpc": {"subnet cidrBlock, vpcId"}}, edges)
+
+	// Filter by URN glob.
+	dg, err = makeDependencyGraph(snap, graphFilter{urns: deploy.NewUrnTargets([]string{"**aws:ec2**"})})
+	require.NoError(t, err)
+	vertices, _ = graphLabels(dg)
+	assert.Equal(t, []string{"vpc", "subnet"}, vertices)
+
+	// Filter by subtree.
+	app := snap.Resources[1].URN
+	dg, err = makeDependencyGraph(snap, graphFilter{subtree: app})
+	require.NoError(t, err)
+	vertices, _ = graphLabels(dg)
+	assert.Equal(t, []string{"app", "vpc", "subnet"}, vertices)
+
+	// Limit the graph to the neighborhood of the component.
+	dg, err = makeDependencyGraph(snap, graphFilter{focus: app, depth: 0})
+	require.NoError(t, err)
+	vertices, _ = graphLabels(dg)
+	assert.Equal(t, []string{"app"}, vertices)
+
+	dg, err = makeDependencyGraph(snap, graphFilter{focus: app, depth: 1})
+	require.NoError(t, err)
+	vertices, _ = graphLabels(dg)
+	assert.Equal(t, []string{"app", "vpc", "subnet"}, vertices)
+
+	dg, err = makeDependencyGraph(snap, graphFilter{focus: app, depth: 2})
+	require.NoError(t, err)
+	vertices, _ = graphLabels(dg)
+	assert.Equal(t, []string{"default", "app", "vpc", "subnet"}, vertices)
+
+	_, err = makeDependencyGraph(snap, graphFilter{focus: "urn:pulumi:stack::proj::my:index:Component::missing"})
+	assert.ErrorContains(t, err, "no resource with URN urn:pulumi:stack::proj::my:index:Component::missing")
+}
+
+//nolint:paralleltest // changes global flags
+func TestPrintDependencyGraph(t *testing.T) {
+	shortNodeName, dependencyEdgeColor = true, "#246C60"
+	defer func() { shortNodeName, dependencyEdgeColor = false, "" }()
+
+	dg, err := makeDependencyGraph(graphSnapshot(t), graphFilter{
+		types: []string{"aws:ec2/vpc:Vpc", "aws:ec2/subnet:Subnet"},
+	})
+	require.NoError(t, err)
+
+	printGraph := func(format string) string {
+		var buf bytes.Buffer
+		require.NoError(t, graphPrinters[format](dg, &buf))
+		return buf.String()
+	}
+
+	assert.Equal(t, "flowchart TD\n"+
+		"    Resource0[\"vpc\"]\n"+
+		"    Resource1[\"subnet\"]\n"+
+		"    Resource0 -->|\"cidrBlock, vpcId\"| Resource1\n"+
+		"    linkStyle 0 stroke:#246C60\n", printGraph("mermaid"))
+
+	var adjacency map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(printGraph("json")), &adjacency))
+	assert.Equal(t, map[string]interface{}{
+		"vertices": []interface{}{
+			map[string]interface{}{
+				"id":    "Resource0",
+				"urn":   "urn:pulumi:stack::proj::aws:ec2/vpc:Vpc::vpc",
+				"label": "vpc",
+				"outs": []interface{}{
+					map[string]interface{}{"to": "Resource1", "label": "cidrBlock, vpcId", "color": "#246C60"},
+				},
+			},
+			map[string]interface{}{
+				"id":    "Resource1",
+				"urn":   "urn:pulumi:stack::proj::aws:ec2/subnet:Subnet::subnet",
+				"label": "subnet",
+				"outs":  []interface{}{},
+			},
+		},
+	}, adjacency)
+
+	var cytoscape struct {
+		Elements struct {
+			Nodes []struct {
+				Data map[string]string `json:"data"`
+			} `json:"nodes"`
+			Edges []struct {
+				Data map[string]string `json:"data"`
+			} `json:"edges"`
+		} `json:"elements"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(printGraph("cytoscape")), &cytoscape))
+	require.Len(t, cytoscape.Elements.Nodes, 2)
+	require.Len(t, cytoscape.Elements.Edges, 1)
+	assert.Equal(t, map[string]string{
+		"id":     "Edge0",
+		"source": "Resource0",
+		"target": "Resource1",
+		"label":  "cidrBlock, vpcId",
+		"color":  "#246C60",
+	}, cytoscape.Elements.Edges[0].Data)
+
+	var graphml struct {
+		Nodes []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"graph>node"`
+		Edges []struct {
+			Source string `xml:"source,attr"`
+			Target string `xml:"target,attr"`
+		} `xml:"graph>edge"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(printGraph("graphml")), &graphml))
+	require.Len(t, graphml.Nodes, 2)
+	require.Len(t, graphml.Edges, 1)
+	assert.Equal(t, "Resource0", graphml.Edges[0].Source)
+	assert.Equal(t, "Resource1", graphml.Edges[0].Target)
+
+	assert.Contains(t, printGraph("dot"), "Resource0 -> Resource1 [color = \"#246C60\", label = \"cidrBlock, vpcId\"];")
+}
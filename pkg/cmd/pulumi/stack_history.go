@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +30,7 @@ func newStackHistoryCmd() *cobra.Command {
 	var pageSize int
 	var page int
 	var showFullDates bool
+	var showCheckpoint int
 
 	cmd := &cobra.Command{
 		Use:        "history",
@@ -35,7 +39,10 @@ func newStackHistoryCmd() *cobra.Command {
 		Short:      "Display history for a stack",
 		Long: `Display history for a stack
 
-This command displays data about previous updates for a stack.`,
+This command displays data about previous updates for a stack.
+
+Pass --show-checkpoint to print the checkpoint that was saved at the end of a given update
+instead. The checkpoint is printed in the same format as ` + "`pulumi stack export`" + `.`,
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := commandContext()
 			opts := display.Options{
@@ -45,6 +52,9 @@ This command displays data about previous updates for a stack.`,
 			if err != nil {
 				return err
 			}
+			if showCheckpoint != 0 {
+				return showHistoryCheckpoint(ctx, s, showCheckpoint, showSecrets)
+			}
 			b := s.Backend()
 			updates, err := b.GetHistory(ctx, s.Ref(), pageSize, page)
 			if err != nil {
@@ -98,9 +108,34 @@ This command displays data about previous updates for a stack.`,
 		&pageSize, "page-size", 10, "Used with 'page' to control number of results returned")
 	cmd.PersistentFlags().IntVar(
 		&page, "page", 1, "Used with 'page-size' to paginate results")
+	cmd.PersistentFlags().IntVar(
+		&showCheckpoint, "show-checkpoint", 0, "Print the checkpoint saved by the given version of the stack")
 	return cmd
 }
 
+// showHistoryCheckpoint prints the checkpoint that was saved at the end of the given version of the stack.
+func showHistoryCheckpoint(ctx context.Context, s backend.Stack, version int, showSecrets bool) error {
+	if version < 0 {
+		return fmt.Errorf("invalid version %d", version)
+	}
+
+	deployment, err := exportStackDeployment(ctx, s, strconv.Itoa(version))
+	if err != nil {
+		return err
+	}
+
+	if showSecrets {
+		deployment, err = revealDeploymentSecrets(ctx, deployment, s.Ref().Name().String())
+		if err != nil {
+			return err
+		}
+
+		log3rdPartySecretsProviderDecryptionEvent(ctx, s, "", "pulumi stack history")
+	}
+
+	return writeDeployment(os.Stdout, deployment)
+}
+
 // updateInfoJSON is the shape of the --json output for a configuration value.  While we can add fields to this
 // structure in the future, we should not change existing fields.
 type updateInfoJSON struct {
@@ -30,7 +30,7 @@ import (
 
 const (
 	possibleSecretsProviderChoices = "The type of the provider that should be used to encrypt and decrypt secrets\n" +
-		"(possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault)"
+		"(possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault, plugin, age)"
 )
 
 func newStackInitCmd() *cobra.Command {
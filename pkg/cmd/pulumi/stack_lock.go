@@ -0,0 +1,192 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+func newStackLockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lock",
+		Short: "Inspect and break the locks held on a stack",
+		Long: "Inspect and break the locks held on a stack.\n" +
+			"\n" +
+			"Self-managed backends lock a stack while it is being updated. Locks are leased, and the process\n" +
+			"holding a lock renews its lease while it runs; a lock whose lease has expired is taken over by\n" +
+			"the next operation on the stack. These commands are only supported by self-managed backends.",
+		Args: cmdutil.NoArgs,
+	}
+
+	cmd.AddCommand(newStackLockStatusCmd())
+	cmd.AddCommand(newStackLockBreakCmd())
+	return cmd
+}
+
+func newStackLockStatusCmd() *cobra.Command {
+	var stackName string
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the locks held on a stack",
+		Args:  cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := commandContext()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, lb, err := requireLockableStack(ctx, stackName, opts)
+			if err != nil {
+				return err
+			}
+			locks, err := lb.ListLocks(ctx, s.Ref())
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				if locks == nil {
+					locks = []filestate.LockInfo{}
+				}
+				return printJSON(locks)
+			}
+			printStackLocks(os.Stdout, s.Ref(), locks)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonOut, "json", "j", false, "Emit output as JSON")
+	return cmd
+}
+
+func newStackLockBreakCmd() *cobra.Command {
+	var stackName string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "break",
+		Short: "Break the locks held on a stack",
+		Long: "Break the locks held on a stack.\n" +
+			"\n" +
+			"This command deletes all locks held on a stack, including locks held by operations that are\n" +
+			"still running. Only use it if you are sure that no other process is updating the stack.",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			ctx := commandContext()
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			s, lb, err := requireLockableStack(ctx, stackName, opts)
+			if err != nil {
+				return result.FromError(err)
+			}
+			locks, err := lb.ListLocks(ctx, s.Ref())
+			if err != nil {
+				return result.FromError(err)
+			}
+			printStackLocks(os.Stdout, s.Ref(), locks)
+			if len(locks) == 0 {
+				return nil
+			}
+
+			if !yes && !confirmPrompt("This will break the locks above.", s.Ref().String(), opts) {
+				return result.Bail()
+			}
+
+			if err := lb.CancelCurrentUpdate(ctx, s.Ref()); err != nil {
+				return result.FromError(err)
+			}
+			fmt.Printf("Broke %d lock(s) on stack %s\n", len(locks), s.Ref())
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+	return cmd
+}
+
+// requireLockableStack loads the given stack and checks that its backend supports inspecting locks.
+func requireLockableStack(
+	ctx context.Context, stackName string, opts display.Options,
+) (backend.Stack, filestate.Backend, error) {
+	s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	lb, ok := s.Backend().(filestate.Backend)
+	if !ok {
+		return nil, nil, fmt.Errorf("the current backend (%s) does not support inspecting stack locks",
+			s.Backend().Name())
+	}
+	return s, lb, nil
+}
+
+// printStackLocks prints a table of the locks held on a stack.
+func printStackLocks(out io.Writer, stackRef backend.StackReference, locks []filestate.LockInfo) {
+	if len(locks) == 0 {
+		fmt.Fprintf(out, "Stack %s is not locked\n", stackRef)
+		return
+	}
+
+	rows := []cmdutil.TableRow{}
+	for _, l := range locks {
+		expires := "never"
+		if !l.Expires.IsZero() {
+			expires = humanize.Time(l.Expires)
+		}
+		status := "held"
+		if l.Expired {
+			status = "expired"
+		}
+		rows = append(rows, cmdutil.TableRow{Columns: []string{
+			l.Username + "@" + l.Hostname,
+			strconv.Itoa(l.Pid),
+			l.Timestamp.Format(time.RFC3339),
+			expires,
+			status,
+			l.URL,
+		}})
+	}
+
+	fmt.Fprint(out, cmdutil.Table{
+		Headers: []string{"OWNER", "PID", "CREATED", "EXPIRES", "STATUS", "URL"},
+		Rows:    rows,
+	}.String())
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/kballard/go-shellquote"
@@ -31,6 +32,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 )
@@ -44,7 +46,11 @@ func newStackOutputCmd() *cobra.Command {
 		Long: "Show a stack's output properties.\n" +
 			"\n" +
 			"By default, this command lists all output properties exported from a stack.\n" +
-			"If a specific property-name is supplied, just that property's value is shown.",
+			"If a specific property-name is supplied, just that property's value is shown.\n" +
+			"\n" +
+			"Use `--version` to show the outputs as they were at the end of an earlier update of the stack\n" +
+			"(see `pulumi stack history`), or `--history` to show the outputs of every update. With `--diff`,\n" +
+			"only the outputs that each update changed are shown.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			return socmd.Run(commandContext(), args)
 		}),
@@ -58,6 +64,14 @@ func newStackOutputCmd() *cobra.Command {
 		&socmd.stackName, "stack", "s", "", "The name of the stack to operate on. Defaults to the current stack")
 	cmd.PersistentFlags().BoolVar(
 		&socmd.showSecrets, "show-secrets", false, "Display outputs which are marked as secret in plaintext")
+	cmd.PersistentFlags().IntVar(
+		&socmd.version, "version", 0, "Show the outputs of the given version of the stack")
+	cmd.PersistentFlags().BoolVar(
+		&socmd.history, "history", false, "Show the outputs of every version of the stack")
+	cmd.PersistentFlags().BoolVar(
+		&socmd.diff, "diff", false,
+		"Show the outputs that changed in each update rather than all of the outputs. Applies to the latest "+
+			"version unless --version or --history is passed")
 
 	return cmd
 }
@@ -67,6 +81,9 @@ type stackOutputCmd struct {
 	showSecrets bool
 	jsonOut     bool
 	shellOut    bool
+	version     int
+	history     bool
+	diff        bool
 
 	OS string // defaults to runtime.GOOS
 
@@ -101,6 +118,10 @@ func (cmd *stackOutputCmd) Run(ctx context.Context, args []string) error {
 	var outw stackOutputWriter
 	if cmd.shellOut && cmd.jsonOut {
 		return errors.New("only one of --json and --shell may be set")
+	} else if cmd.history && cmd.version != 0 {
+		return errors.New("only one of --history and --version may be set")
+	} else if cmd.shellOut && (cmd.history || cmd.diff) {
+		return errors.New("--shell may not be used with --history or --diff")
 	} else if cmd.jsonOut {
 		outw = &jsonStackOutputWriter{W: stdout}
 	} else if cmd.shellOut {
@@ -114,7 +135,23 @@ func (cmd *stackOutputCmd) Run(ctx context.Context, args []string) error {
 	if err != nil {
 		return err
 	}
-	snap, err := s.Snapshot(ctx, stack.DefaultSecretsProvider)
+
+	if cmd.history || cmd.diff {
+		if err := cmd.runHistory(ctx, s, args, stdout, opts); err != nil {
+			return err
+		}
+		if cmd.showSecrets {
+			log3rdPartySecretsProviderDecryptionEvent(ctx, s, "", "pulumi stack output")
+		}
+		return nil
+	}
+
+	var snap *deploy.Snapshot
+	if cmd.version != 0 {
+		snap, err = loadStackDiffSnapshot(ctx, s, strconv.Itoa(cmd.version))
+	} else {
+		snap, err = s.Snapshot(ctx, stack.DefaultSecretsProvider)
+	}
 	if err != nil {
 		return err
 	}
@@ -135,6 +172,8 @@ func (cmd *stackOutputCmd) Run(ctx context.Context, args []string) error {
 			if err := outw.WriteOne(name, v); err != nil {
 				return err
 			}
+		} else if cmd.version != 0 {
+			return fmt.Errorf("version %d of the stack does not have output property '%v'", cmd.version, name)
 		} else {
 			return fmt.Errorf("current stack does not have output property '%v'", name)
 		}
@@ -269,9 +308,15 @@ func getStackOutputs(snap *deploy.Snapshot, showSecrets bool) (map[string]interf
 		return map[string]interface{}{}, nil
 	}
 
+	return serializeStackOutputs(state.Outputs, showSecrets)
+}
+
+// serializeStackOutputs converts the outputs of a stack into plain values for display, replacing secrets with
+// "[secret]" unless showSecrets is true.
+func serializeStackOutputs(outputs resource.PropertyMap, showSecrets bool) (map[string]interface{}, error) {
 	// massageSecrets will remove all the secrets from the property map, so it should be safe to pass a panic
 	// crypter. This also ensure that if for some reason we didn't remove everything, we don't accidentally disclose
 	// secret values!
-	return stack.SerializeProperties(display.MassageSecrets(state.Outputs, showSecrets),
+	return stack.SerializeProperties(display.MassageSecrets(outputs, showSecrets),
 		config.NewPanicCrypter(), showSecrets)
 }
@@ -0,0 +1,218 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// stackOutputVersionJSON is the shape of the --json output of `pulumi stack output` with --history or --diff. While
+// we can add fields to this structure in the future, we should not change existing fields.
+type stackOutputVersionJSON struct {
+	Version   int                    `json:"version"`
+	Kind      string                 `json:"kind"`
+	StartTime string                 `json:"startTime"`
+	Result    string                 `json:"result"`
+	Outputs   map[string]interface{} `json:"outputs"`
+	// Changes are the paths of the outputs that the update changed. They are only set with --diff.
+	Changes *engine.PropertyPathDiff `json:"changes,omitempty"`
+
+	update backend.UpdateInfo
+	diff   *resource.ObjectDiff
+}
+
+// runHistory shows the outputs of the versions of the stack that were selected by --history and --version, or the
+// changes that each of these versions made to the outputs if --diff was passed. If args names an output, only that
+// output is shown.
+func (cmd *stackOutputCmd) runHistory(
+	ctx context.Context, s backend.Stack, args []string, stdout io.Writer, opts display.Options,
+) error {
+	updates, err := s.Backend().GetHistory(ctx, s.Ref(), 0 /*pageSize*/, 0 /*page*/)
+	if err != nil {
+		return fmt.Errorf("getting the history of stack %s: %w", s.Ref(), err)
+	}
+
+	// Updates are ordered from the most recent. Updates that are still in progress don't have a checkpoint yet.
+	var completed []backend.UpdateInfo
+	for _, update := range updates {
+		if update.Result != backend.InProgressResult {
+			completed = append(completed, update)
+		}
+	}
+	if len(completed) == 0 {
+		return fmt.Errorf("stack %s has no history", s.Ref())
+	}
+
+	// selected holds the indices of the updates to show in completed.
+	var selected []int
+	switch {
+	case cmd.history:
+		for i := range completed {
+			selected = append(selected, i)
+		}
+	case cmd.version != 0:
+		for i, update := range completed {
+			if update.Version == cmd.version {
+				selected = append(selected, i)
+			}
+		}
+		if len(selected) == 0 {
+			return fmt.Errorf("version %d of stack %s does not exist", cmd.version, s.Ref())
+		}
+	default:
+		selected = []int{0}
+	}
+
+	var name resource.PropertyKey
+	if len(args) > 0 {
+		name = resource.PropertyKey(args[0])
+	}
+
+	// Diffs load the outputs of the update before each selected update too, so cache the outputs of each version.
+	loaded := make(map[int]resource.PropertyMap)
+	loadOutputs := func(version int) (resource.PropertyMap, error) {
+		if outputs, ok := loaded[version]; ok {
+			return outputs, nil
+		}
+		outputs, err := loadStackOutputsForVersion(ctx, s, version)
+		if err != nil {
+			return nil, err
+		}
+		if name != "" {
+			filtered := resource.PropertyMap{}
+			if v, has := outputs[name]; has {
+				filtered[name] = v
+			}
+			outputs = filtered
+		}
+		loaded[version] = outputs
+		return outputs, nil
+	}
+
+	versions := make([]stackOutputVersionJSON, 0, len(selected))
+	for _, i := range selected {
+		update := completed[i]
+		news, err := loadOutputs(update.Version)
+		if err != nil {
+			return err
+		}
+
+		entry := stackOutputVersionJSON{
+			Version:   update.Version,
+			Kind:      string(update.Kind),
+			StartTime: time.Unix(update.StartTime, 0).UTC().Format(timeFormat),
+			Result:    string(update.Result),
+			update:    update,
+		}
+		if entry.Outputs, err = serializeStackOutputs(news, cmd.showSecrets); err != nil {
+			return fmt.Errorf("getting outputs: %w", err)
+		}
+
+		if cmd.diff {
+			olds := resource.PropertyMap{}
+			if i+1 < len(completed) {
+				if olds, err = loadOutputs(completed[i+1].Version); err != nil {
+					return err
+				}
+			}
+			// Secrets are compared by their plaintext, but are only shown if requested.
+			if cmd.showSecrets {
+				olds, news = display.MassageSecrets(olds, true), display.MassageSecrets(news, true)
+			}
+			entry.Changes = engine.DiffPropertyPaths(olds, news)
+			entry.diff = olds.Diff(news)
+		}
+
+		versions = append(versions, entry)
+	}
+
+	if cmd.jsonOut {
+		return fprintJSON(stdout, versions)
+	}
+	return fprintStackOutputVersions(stdout, versions, cmd.diff, opts)
+}
+
+// loadStackOutputsForVersion returns the outputs of the root stack resource at the end of the given version of the
+// stack.
+func loadStackOutputsForVersion(ctx context.Context, s backend.Stack, version int) (resource.PropertyMap, error) {
+	snap, err := loadStackDiffSnapshot(ctx, s, strconv.Itoa(version))
+	if err != nil {
+		return nil, fmt.Errorf("loading version %d of stack %s: %w", version, s.Ref(), err)
+	}
+	state, err := stack.GetRootStackResource(snap)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return resource.PropertyMap{}, nil
+	}
+	return state.Outputs, nil
+}
+
+// fprintStackOutputVersions prints the outputs of each of the given versions, or the changes to them if diff is true.
+func fprintStackOutputVersions(
+	w io.Writer, versions []stackOutputVersionJSON, diff bool, opts display.Options,
+) error {
+	for i, v := range versions {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+
+		header := fmt.Sprintf("%sVersion %d%s (%s, %s, %s):\n", colors.SpecHeadline, v.Version, colors.Reset,
+			v.Kind, humanize.Time(time.Unix(v.update.StartTime, 0)), v.Result)
+		if _, err := fmt.Fprint(w, opts.Color.Colorize(header)); err != nil {
+			return err
+		}
+
+		switch {
+		case diff && v.diff == nil:
+			_, err := fmt.Fprintf(w, "    No output changes\n")
+			if err != nil {
+				return err
+			}
+		case diff:
+			var b bytes.Buffer
+			display.PrintObjectDiff(&b, *v.diff, nil, false, 2, false, false, false)
+			if _, err := fmt.Fprint(w, opts.Color.Colorize(b.String())); err != nil {
+				return err
+			}
+		case len(v.Outputs) == 0:
+			if _, err := fmt.Fprintf(w, "    No output values\n"); err != nil {
+				return err
+			}
+		default:
+			if err := fprintStackOutputsTable(w, v.Outputs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -24,8 +24,10 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -485,3 +487,73 @@ func TestShellStackOutputWriter_quoting(t *testing.T) {
 		})
 	}
 }
+
+func TestStackOutputCmd_history(t *testing.T) {
+	t.Parallel()
+
+	makeDeployment := func(t *testing.T, outputs map[string]interface{}) *apitype.UntypedDeployment {
+		data, err := json.Marshal(apitype.DeploymentV3{Resources: []apitype.ResourceV3{{
+			URN:     resource.URN("urn:pulumi:dev::proj::pulumi:pulumi:Stack::proj-dev"),
+			Type:    resource.RootStackType,
+			Outputs: outputs,
+		}}})
+		require.NoError(t, err)
+		return &apitype.UntypedDeployment{Version: 3, Deployment: data}
+	}
+
+	be := &historyBackend{versions: map[string]*apitype.UntypedDeployment{
+		"1": makeDeployment(t, map[string]interface{}{"url": "http://a", "port": 80}),
+		"2": makeDeployment(t, map[string]interface{}{"url": "http://b", "port": 80}),
+		"3": makeDeployment(t, map[string]interface{}{"url": "http://b", "port": 80, "name": "c"}),
+	}}
+	be.GetHistoryF = func(context.Context, backend.StackReference, int, int) ([]backend.UpdateInfo, error) {
+		return []backend.UpdateInfo{
+			{Version: 4, Kind: apitype.UpdateUpdate, Result: backend.InProgressResult},
+			{Version: 3, Kind: apitype.UpdateUpdate, Result: backend.SucceededResult},
+			{Version: 2, Kind: apitype.UpdateUpdate, Result: backend.SucceededResult},
+			{Version: 1, Kind: apitype.UpdateUpdate, Result: backend.SucceededResult},
+		}, nil
+	}
+	requireStack := func(context.Context, string, stackLoadOption, display.Options) (backend.Stack, error) {
+		return &backend.MockStack{
+			RefF: func() backend.StackReference {
+				return &backend.MockStackReference{StringV: "dev", NameV: "dev"}
+			},
+			BackendF: func() backend.Backend { return be },
+		}, nil
+	}
+
+	run := func(t *testing.T, cmd stackOutputCmd, args ...string) []stackOutputVersionJSON {
+		var stdout bytes.Buffer
+		cmd.Stdout, cmd.requireStack, cmd.jsonOut = &stdout, requireStack, true
+		require.NoError(t, cmd.Run(context.Background(), args))
+		var versions []stackOutputVersionJSON
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &versions))
+		return versions
+	}
+
+	versions := run(t, stackOutputCmd{history: true}, "url")
+	require.Len(t, versions, 3)
+	assert.Equal(t, []int{3, 2, 1}, []int{versions[0].Version, versions[1].Version, versions[2].Version})
+	assert.Equal(t, map[string]interface{}{"url": "http://a"}, versions[2].Outputs)
+
+	versions = run(t, stackOutputCmd{history: true, diff: true})
+	require.Len(t, versions, 3)
+	assert.Equal(t, &engine.PropertyPathDiff{Added: []string{"name"}}, versions[0].Changes)
+	assert.Equal(t, &engine.PropertyPathDiff{Changed: []string{"url"}}, versions[1].Changes)
+	assert.Equal(t, &engine.PropertyPathDiff{Added: []string{"port", "url"}}, versions[2].Changes)
+
+	// Without --history, --diff applies to the latest version or to the version given by --version.
+	versions = run(t, stackOutputCmd{diff: true, version: 2})
+	require.Len(t, versions, 1)
+	assert.Equal(t, 2, versions[0].Version)
+
+	// --version on its own shows the outputs of that version like the latest outputs are shown.
+	var stdout bytes.Buffer
+	cmd := stackOutputCmd{version: 1, Stdout: &stdout, requireStack: requireStack}
+	require.NoError(t, cmd.Run(context.Background(), []string{"url"}))
+	assert.Equal(t, "http://a\n", stdout.String())
+
+	cmd = stackOutputCmd{version: 1, history: true, Stdout: &stdout, requireStack: requireStack}
+	assert.ErrorContains(t, cmd.Run(context.Background(), nil), "only one of --history and --version may be set")
+}
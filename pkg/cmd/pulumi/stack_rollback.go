@@ -0,0 +1,114 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+func newStackRollbackCmd() *cobra.Command {
+	var stackName string
+	var version int
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Args:  cmdutil.NoArgs,
+		Short: "Restore the state of a stack from its history",
+		Long: "Restore the state of a stack from its history.\n" +
+			"\n" +
+			"This command replaces the current state of the stack with the checkpoint that was saved at the\n" +
+			"end of the given update (see `pulumi stack history`). The checkpoint is validated before it is\n" +
+			"restored. No resources are changed: run `pulumi refresh` or `pulumi up` afterwards to reconcile\n" +
+			"the restored state with your cloud resources.",
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			ctx := commandContext()
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if version <= 0 {
+				return result.Error("a version to roll back to must be specified with --to-version")
+			}
+
+			s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			deployment, err := loadRollbackDeployment(ctx, s, version)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			if !yes && !confirmStateEdit(opts, fmt.Sprintf(
+				"This command will replace the state of stack %s with version %d. Confirm?", s.Ref(), version)) {
+				return result.Bail()
+			}
+
+			if err := backend.ImportStackDeployment(ctx, s, deployment); err != nil {
+				return result.FromError(fmt.Errorf("restoring checkpoint: %w", err))
+			}
+
+			fmt.Printf("Rolled back stack %s to version %d\n", s.Ref(), version)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().IntVar(
+		&version, "to-version", 0, "The version of the stack to restore")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+	return cmd
+}
+
+// loadRollbackDeployment exports the given version of the stack and checks that it can be safely restored.
+func loadRollbackDeployment(
+	ctx context.Context, s backend.Stack, version int,
+) (*apitype.UntypedDeployment, error) {
+	deployment, err := exportStackDeployment(ctx, s, strconv.Itoa(version))
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := stack.DeserializeUntypedDeployment(ctx, deployment, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, checkDeploymentVersionError(err, s.Ref().Name().String())
+	}
+	if err := snap.VerifyIntegrity(); err != nil {
+		return nil, fmt.Errorf("version %d of stack %s can't be restored: %w", version, s.Ref(), err)
+	}
+	if len(snap.PendingOperations) != 0 {
+		return nil, errors.New("the checkpoint has pending operations; " +
+			"restore it with `pulumi stack import` after reviewing them")
+	}
+
+	return deployment, nil
+}
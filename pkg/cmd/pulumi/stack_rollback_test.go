@@ -0,0 +1,91 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// historyBackend stands in for a backend that keeps the history of its stacks' deployments, like the Pulumi
+// Service.
+type historyBackend struct {
+	backend.MockBackend
+
+	versions map[string]*apitype.UntypedDeployment
+}
+
+func (b *historyBackend) ExportDeploymentForVersion(
+	ctx context.Context, s backend.Stack, version string,
+) (*apitype.UntypedDeployment, error) {
+	if dep, ok := b.versions[version]; ok {
+		return dep, nil
+	}
+	return nil, fmt.Errorf("version %s not found", version)
+}
+
+func TestLoadRollbackDeployment(t *testing.T) {
+	t.Parallel()
+
+	makeDeployment := func(t *testing.T, resources ...apitype.ResourceV3) *apitype.UntypedDeployment {
+		data, err := json.Marshal(apitype.DeploymentV3{Resources: resources})
+		require.NoError(t, err)
+		return &apitype.UntypedDeployment{Version: 3, Deployment: data}
+	}
+
+	good := makeDeployment(t, apitype.ResourceV3{
+		URN:  resource.URN("urn:pulumi:dev::proj::pkg:index:Thing::a"),
+		Type: "pkg:index:Thing",
+	})
+	// A checkpoint whose resource refers to a parent that doesn't exist fails the integrity checks.
+	bad := makeDeployment(t, apitype.ResourceV3{
+		URN:    resource.URN("urn:pulumi:dev::proj::pkg:index:Thing::b"),
+		Type:   "pkg:index:Thing",
+		Parent: resource.URN("urn:pulumi:dev::proj::pkg:index:Thing::missing"),
+	})
+
+	be := &historyBackend{versions: map[string]*apitype.UntypedDeployment{"1": good, "2": bad}}
+	s := &backend.MockStack{
+		RefF: func() backend.StackReference {
+			return &backend.MockStackReference{StringV: "dev", NameV: "dev"}
+		},
+		BackendF: func() backend.Backend { return be },
+	}
+
+	dep, err := loadRollbackDeployment(context.Background(), s, 1)
+	require.NoError(t, err)
+	assert.Equal(t, good, dep)
+
+	_, err = loadRollbackDeployment(context.Background(), s, 2)
+	assert.ErrorContains(t, err, "version 2 of stack dev can't be restored")
+
+	_, err = loadRollbackDeployment(context.Background(), s, 3)
+	assert.ErrorContains(t, err, "version 3 not found")
+
+	// Backends that don't keep history can't roll back.
+	s.BackendF = func() backend.Backend {
+		return &backend.MockBackend{NameF: func() string { return "mock" }}
+	}
+	_, err = loadRollbackDeployment(context.Background(), s, 1)
+	assert.ErrorContains(t, err, "does not provide the ability to export previous deployments")
+}
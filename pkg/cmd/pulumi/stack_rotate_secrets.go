@@ -0,0 +1,179 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/httpstate"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/deepcopy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func newStackRotateSecretsCmd() *cobra.Command {
+	var stackName string
+
+	cmd := &cobra.Command{
+		Use:   "rotate-secrets",
+		Args:  cmdutil.NoArgs,
+		Short: "Rotate the key that encrypts a stack's secrets",
+		Long: "Rotate the key that encrypts a stack's secrets.\n" +
+			"\n" +
+			"This command generates a new data key with the stack's current secrets provider, e.g. after the\n" +
+			"old key has leaked, and re-encrypts the stack's configuration and every secret in its state with\n" +
+			"the new key. For the passphrase secrets provider, you will be asked for a new passphrase.\n" +
+			"\n" +
+			"The keys of stacks that use the Pulumi Cloud's default secrets provider are managed by the\n" +
+			"service and cannot be rotated with this command.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := commandContext()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			project, _, err := readProject()
+			if err != nil {
+				return err
+			}
+			s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+			if err != nil {
+				return err
+			}
+			ps, err := loadProjectStack(project, s)
+			if err != nil {
+				return err
+			}
+
+			save := func(ps *workspace.ProjectStack) error { return saveProjectStack(s, ps) }
+			if err := rotateStackSecrets(ctx, s, ps, save); err != nil {
+				return err
+			}
+
+			fmt.Printf("Rotated the secrets key of stack %s\n", s.Ref())
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	return cmd
+}
+
+// rotatedSecretsProvider returns the secrets provider to rotate the data key of for the given stack.
+func rotatedSecretsProvider(s backend.Stack, ps *workspace.ProjectStack) (string, error) {
+	switch {
+	case ps.SecretsProvider != "" && ps.SecretsProvider != "default" && ps.SecretsProvider != passphrase.Type:
+		return ps.SecretsProvider, nil
+	case ps.EncryptionSalt != "":
+		return passphrase.Type, nil
+	}
+
+	if _, isCloud := s.Backend().(httpstate.Backend); isCloud {
+		return "", errors.New("the stack uses the Pulumi Cloud's default secrets provider, whose keys are " +
+			"managed by the service; use `pulumi stack change-secrets-provider` to switch to a provider " +
+			"whose keys you manage")
+	}
+	// The default secrets provider of the other backends is the passphrase provider.
+	return passphrase.Type, nil
+}
+
+// rotateStackSecrets generates a new data key for the stack's secrets provider and re-encrypts the stack's
+// configuration and state with it. Everything is re-encrypted before anything is written: the new state is imported
+// first and, if the configuration can't be saved afterwards, the old state is restored, so that the configuration
+// and the state never end up encrypted with different keys.
+func rotateStackSecrets(ctx context.Context, s backend.Stack, ps *workspace.ProjectStack,
+	save func(*workspace.ProjectStack) error,
+) error {
+	secretsProvider, err := rotatedSecretsProvider(s, ps)
+	if err != nil {
+		return err
+	}
+
+	// Decrypt the configuration and state with the old key.
+	var decrypter config.Decrypter = config.NewPanicCrypter()
+	if ps.Config.HasSecureValue() {
+		if decrypter, _, err = getStackDecrypter(s, ps); err != nil {
+			return err
+		}
+	}
+	oldDeployment, err := s.ExportDeployment(ctx)
+	if err != nil {
+		return err
+	}
+	snap, err := stack.DeserializeUntypedDeployment(ctx, oldDeployment, stack.DefaultSecretsProvider)
+	if err != nil {
+		return checkDeploymentVersionError(err, s.Ref().Name().String())
+	}
+
+	// Generate the new key and re-encrypt everything with it.
+	newPS := deepcopy.Copy(ps).(*workspace.ProjectStack)
+	sm, err := newSecretsManager(s, newPS, secretsProvider, true /*rotateSecretsProvider*/)
+	if err != nil {
+		return fmt.Errorf("generating a new secrets key: %w", err)
+	}
+	encrypter, err := sm.Encrypter()
+	if err != nil {
+		return err
+	}
+	if newPS.Config, err = ps.Config.Copy(decrypter, encrypter); err != nil {
+		return fmt.Errorf("re-encrypting stack config: %w", err)
+	}
+
+	var newDeployment *apitype.UntypedDeployment
+	if snap != nil {
+		deployment, err := stack.SerializeDeployment(snap, sm, false /*showSecrets*/)
+		if err != nil {
+			return fmt.Errorf("re-encrypting stack state: %w", err)
+		}
+		bytes, err := json.Marshal(deployment)
+		if err != nil {
+			return err
+		}
+		newDeployment = &apitype.UntypedDeployment{
+			Version:    apitype.DeploymentSchemaVersionCurrent,
+			Deployment: bytes,
+		}
+	}
+
+	// Write the new state and then the new configuration, restoring the old state if the latter fails.
+	if newDeployment != nil {
+		if err := s.ImportDeployment(ctx, newDeployment); err != nil {
+			return fmt.Errorf("saving stack state: %w", err)
+		}
+	}
+	if err := save(newPS); err != nil {
+		if newDeployment != nil {
+			if restoreErr := s.ImportDeployment(ctx, oldDeployment); restoreErr != nil {
+				return fmt.Errorf("saving stack config: %w; restoring the previous stack state also failed: %v",
+					err, restoreErr)
+			}
+		}
+		return fmt.Errorf("saving stack config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "gocloud.dev/secrets/localsecrets" // support for base64key://
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/age"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// newRotateSecretsTestStack returns a stack that uses the given secrets provider, with a secret in both its config and
+// its state, and a pointer to the stack's current deployment.
+func newRotateSecretsTestStack(t *testing.T, secretsProvider string,
+) (backend.Stack, *workspace.ProjectStack, **apitype.UntypedDeployment) {
+	ps := &workspace.ProjectStack{Config: config.Map{}}
+	sm, err := newSecretsManager(nil, ps, secretsProvider, false /*rotateSecretsProvider*/)
+	require.NoError(t, err)
+
+	enc, err := sm.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue(context.Background(), "hunter2")
+	require.NoError(t, err)
+	ps.Config[config.MustMakeKey("proj", "password")] = config.NewSecureValue(ciphertext)
+
+	snap := deploy.NewSnapshot(deploy.Manifest{}, sm, []*resource.State{{
+		URN:     resource.URN("urn:pulumi:dev::proj::pkg:index:Thing::a"),
+		Type:    "pkg:index:Thing",
+		Custom:  true,
+		Outputs: resource.PropertyMap{"token": resource.MakeSecret(resource.NewStringProperty("s3cret"))},
+	}}, nil)
+	deployment, err := stack.SerializeDeployment(snap, sm, false)
+	require.NoError(t, err)
+	bytes, err := json.Marshal(deployment)
+	require.NoError(t, err)
+	current := &apitype.UntypedDeployment{Version: apitype.DeploymentSchemaVersionCurrent, Deployment: bytes}
+
+	s := &backend.MockStack{
+		RefF: func() backend.StackReference {
+			return &backend.MockStackReference{StringV: "dev", NameV: "dev"}
+		},
+		BackendF: func() backend.Backend { return &backend.MockBackend{} },
+		ExportDeploymentF: func(ctx context.Context) (*apitype.UntypedDeployment, error) {
+			return current, nil
+		},
+		ImportDeploymentF: func(ctx context.Context, deployment *apitype.UntypedDeployment) error {
+			current = deployment
+			return nil
+		},
+	}
+	return s, ps, &current
+}
+
+// newAgeRotateSecretsTestStack returns a test stack (see newRotateSecretsTestStack) that uses an age secrets
+// provider.
+func newAgeRotateSecretsTestStack(t *testing.T) (backend.Stack, *workspace.ProjectStack, **apitype.UntypedDeployment) {
+	dir := t.TempDir()
+	recipients := filepath.Join(dir, "recipients.txt")
+	identity := filepath.Join(dir, "identity.txt")
+	require.NoError(t, os.WriteFile(recipients,
+		[]byte("age1zvkyg2lqzraa2lnjvqej32nkuu0ues2s82hzrye869xeexvn73equnujwj\n"), 0o600))
+	require.NoError(t, os.WriteFile(identity,
+		[]byte("AGE-SECRET-KEY-1GFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPQ4EGAEX\n"), 0o600))
+	t.Setenv(age.IdentityFileEnvVar, identity)
+
+	return newRotateSecretsTestStack(t, age.Scheme+recipients)
+}
+
+// rotateTestStackSecrets rotates the secrets of the given test stack (see newRotateSecretsTestStack) and returns the
+// stack's new settings.
+func rotateTestStackSecrets(t *testing.T, s backend.Stack, ps *workspace.ProjectStack) *workspace.ProjectStack {
+	var saved *workspace.ProjectStack
+	err := rotateStackSecrets(context.Background(), s, ps, func(ps *workspace.ProjectStack) error {
+		saved = ps
+		return nil
+	})
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, ps.SecretsProvider, saved.SecretsProvider)
+	return saved
+}
+
+// assertRotatedTestStack checks that the config and the state of the given test stack (see
+// newRotateSecretsTestStack) were re-encrypted with the key of its new settings.
+func assertRotatedTestStack(t *testing.T, s backend.Stack, saved *workspace.ProjectStack,
+	oldDeployment, current *apitype.UntypedDeployment,
+) {
+	ctx := context.Background()
+
+	sm, _, err := getStackSecretsManager(s, saved)
+	require.NoError(t, err)
+	dec, err := sm.Decrypter()
+	require.NoError(t, err)
+	password, err := saved.Config[config.MustMakeKey("proj", "password")].Value(dec)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", password)
+
+	// The state records the new key.
+	assert.NotEqual(t, oldDeployment, current)
+	var deployment apitype.DeploymentV3
+	require.NoError(t, json.Unmarshal(current.Deployment, &deployment))
+	assert.JSONEq(t, string(mustMarshal(t, sm.State())), string(deployment.SecretsProviders.State))
+
+	snap, err := stack.DeserializeUntypedDeployment(ctx, current, stack.DefaultSecretsProvider)
+	require.NoError(t, err)
+	require.Len(t, snap.Resources, 1)
+	token := snap.Resources[0].Outputs["token"]
+	require.True(t, token.IsSecret())
+	assert.Equal(t, "s3cret", token.SecretValue().Element.StringValue())
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestRotateStackSecrets(t *testing.T) {
+	s, ps, current := newAgeRotateSecretsTestStack(t)
+	oldDeployment := *current
+
+	saved := rotateTestStackSecrets(t, s, ps)
+	assert.NotEqual(t, ps.EncryptedKey, saved.EncryptedKey)
+	assertRotatedTestStack(t, s, saved, oldDeployment, *current)
+}
+
+//nolint:paralleltest // mutates environment variables and stdin
+func TestRotateStackSecretsPassphrase(t *testing.T) {
+	t.Setenv("PULUMI_CONFIG_PASSPHRASE", "old passphrase")
+	s, ps, current := newRotateSecretsTestStack(t, passphrase.Type)
+	oldDeployment := *current
+
+	// Outside of a terminal, the new passphrase is read from stdin.
+	stdin, err := os.CreateTemp(t.TempDir(), "stdin")
+	require.NoError(t, err)
+	_, err = stdin.WriteString("new passphrase\n")
+	require.NoError(t, err)
+	_, err = stdin.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	oldStdin := os.Stdin
+	os.Stdin = stdin
+	defer func() { os.Stdin = oldStdin }()
+
+	saved := rotateTestStackSecrets(t, s, ps)
+	assert.NotEqual(t, ps.EncryptionSalt, saved.EncryptionSalt)
+
+	// The stack can only be decrypted with the new passphrase.
+	t.Setenv("PULUMI_CONFIG_PASSPHRASE", "new passphrase")
+	assertRotatedTestStack(t, s, saved, oldDeployment, *current)
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestRotateStackSecretsCloud(t *testing.T) {
+	url := "base64key://" + base64.URLEncoding.EncodeToString(make([]byte, 32))
+	s, ps, current := newRotateSecretsTestStack(t, url)
+	oldDeployment := *current
+
+	// A new data key is generated and wrapped with the same master key.
+	saved := rotateTestStackSecrets(t, s, ps)
+	assert.Equal(t, url, saved.SecretsProvider)
+	assert.NotEqual(t, ps.EncryptedKey, saved.EncryptedKey)
+	assertRotatedTestStack(t, s, saved, oldDeployment, *current)
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestRotateStackSecretsRestoresStateOnFailure(t *testing.T) {
+	s, ps, current := newAgeRotateSecretsTestStack(t)
+	oldDeployment := *current
+
+	err := rotateStackSecrets(context.Background(), s, ps, func(*workspace.ProjectStack) error {
+		return errors.New("disk full")
+	})
+	assert.ErrorContains(t, err, "saving stack config: disk full")
+	assert.Equal(t, oldDeployment, *current)
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	bytes, err := json.Marshal(v)
+	require.NoError(t, err)
+	return bytes
+}
@@ -0,0 +1,206 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/secretscan"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func newStackScanSecretsCmd() *cobra.Command {
+	var stackName string
+	var scanOpts secretscan.Options
+	var mark bool
+
+	cmd := &cobra.Command{
+		Use:   "scan-secrets",
+		Args:  cmdutil.NoArgs,
+		Short: "Scan a stack's state for plaintext values that look like secrets",
+		Long: "Scan a stack's state for plaintext values that look like secrets.\n" +
+			"\n" +
+			"This command checks the inputs and outputs of every resource in the stack's state for values that\n" +
+			"are not marked as secret but look like they should be, and reports the URN of the resource and the\n" +
+			"path of each value. The following detectors are run by default:\n" +
+			"\n" +
+			"  entropy: long strings whose characters look random, e.g. generated passwords and keys\n" +
+			"  tokens:  strings in the formats of well-known credentials, e.g. AWS access keys\n" +
+			"  config:  strings that are equal to one of the stack's secret config values\n" +
+			"\n" +
+			"The command fails if any values are found. With `--mark`, the values are marked as secret in the\n" +
+			"stack's state instead. This is a one-off rewrite of the state: the next update records the outputs\n" +
+			"of each resource as its program declares them, so add the outputs that were found to the\n" +
+			"`additionalSecretOutputs` resource option in the program to keep them secret.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := commandContext()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			project, _, err := readProject()
+			if err != nil {
+				return err
+			}
+			s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+			if err != nil {
+				return err
+			}
+			ps, err := loadProjectStack(project, s)
+			if err != nil {
+				return err
+			}
+			configSecrets, err := stackConfigSecrets(s, ps)
+			if err != nil {
+				return err
+			}
+			detectors, err := secretscan.NewDetectors(scanOpts, configSecrets)
+			if err != nil {
+				return err
+			}
+
+			findings, err := scanStackSecrets(ctx, s, ps, detectors, mark)
+			if err != nil {
+				return err
+			}
+			if len(findings) == 0 {
+				fmt.Printf("No plaintext secrets found in stack %s\n", s.Ref())
+				return nil
+			}
+
+			fmt.Printf("Found %d plaintext values that look like secrets in stack %s:\n", len(findings), s.Ref())
+			for _, f := range findings {
+				fmt.Printf("    %s: %s\n", f.URN, f)
+			}
+			if mark {
+				fmt.Printf("Marked the values as secret in the stack's state. Add the outputs to the " +
+					"`additionalSecretOutputs` option of their resources to keep them secret after the next update\n")
+				return nil
+			}
+			return fmt.Errorf("found %d plaintext values that look like secrets; "+
+				"rerun with `--mark` to mark them as secret", len(findings))
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	addScanSecretsFlags(cmd.PersistentFlags(), &scanOpts, "")
+	cmd.PersistentFlags().BoolVar(
+		&mark, "mark", false,
+		"Mark the values that are found as secret in the stack's state")
+	return cmd
+}
+
+// stackConfigSecrets returns the plaintext values of the stack's secret config.
+func stackConfigSecrets(s backend.Stack, ps *workspace.ProjectStack) ([]string, error) {
+	if !ps.Config.HasSecureValue() {
+		return nil, nil
+	}
+	decrypter, _, err := getStackDecrypter(s, ps)
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []string
+	for k, v := range ps.Config {
+		if !v.Secure() {
+			continue
+		}
+		values, err := v.SecureValues(decrypter)
+		if err != nil {
+			return nil, fmt.Errorf("decrypting config value %v: %w", k, err)
+		}
+		secrets = append(secrets, values...)
+	}
+	return secrets, nil
+}
+
+// scanStackSecrets scans the stack's state with the given detectors and, if mark is true, rewrites the state so that
+// the values that were found are secret.
+func scanStackSecrets(ctx context.Context, s backend.Stack, ps *workspace.ProjectStack,
+	detectors []secretscan.Detector, mark bool,
+) ([]secretscan.Finding, error) {
+	deployment, err := s.ExportDeployment(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := stack.DeserializeUntypedDeployment(ctx, deployment, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, checkDeploymentVersionError(err, s.Ref().Name().String())
+	}
+
+	findings := secretscan.NewScanner(detectors...).ScanSnapshot(snap)
+	if !mark || len(findings) == 0 {
+		return findings, nil
+	}
+
+	secretscan.MarkSecrets(snap, findings)
+	if snap.SecretsManager == nil {
+		// The state has no secrets yet, so use the stack's secrets manager to encrypt the new ones.
+		if snap.SecretsManager, _, err = getStackSecretsManager(s, ps); err != nil {
+			return nil, err
+		}
+	}
+	marked, err := stack.SerializeDeployment(snap, snap.SecretsManager, false /*showSecrets*/)
+	if err != nil {
+		return nil, fmt.Errorf("serializing stack state: %w", err)
+	}
+	bytes, err := json.Marshal(marked)
+	if err != nil {
+		return nil, err
+	}
+	err = s.ImportDeployment(ctx, &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("saving stack state: %w", err)
+	}
+	return findings, nil
+}
+
+// addScanSecretsFlags adds the flags that configure the detectors of the secret scanner to flags. The names of the
+// flags are prefixed with prefix.
+func addScanSecretsFlags(flags *pflag.FlagSet, opts *secretscan.Options, prefix string) {
+	flags.StringSliceVar(
+		&opts.Detectors, prefix+"detectors", nil,
+		"The detectors to run: entropy, tokens or config. Defaults to all detectors")
+	flags.IntVar(
+		&opts.MinLength, prefix+"min-length", secretscan.DefaultMinLength,
+		"The length below which strings are not checked by the entropy detector")
+	flags.Float64Var(
+		&opts.MinEntropy, prefix+"min-entropy", secretscan.DefaultMinEntropy,
+		"The entropy, in bits per character, above which the entropy detector flags strings that are long enough to have it")
+}
+
+// scanSecretsOptions returns the engine options that check resource outputs for plaintext secrets with the given
+// detector settings, or nil if the check is disabled.
+func scanSecretsOptions(enabled bool, opts secretscan.Options) *secretscan.Options {
+	if !enabled {
+		return nil
+	}
+	return &opts
+}
@@ -0,0 +1,118 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/secretscan"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+//nolint:paralleltest // mutates environment variables
+func TestScanStackSecrets(t *testing.T) {
+	ctx := context.Background()
+	s, ps, current := newAgeRotateSecretsTestStack(t)
+
+	// Leak the config secret into a plaintext output.
+	snap, err := stack.DeserializeUntypedDeployment(ctx, *current, stack.DefaultSecretsProvider)
+	require.NoError(t, err)
+	snap.Resources[0].Outputs["password"] = resource.NewStringProperty("hunter2")
+	deployment, err := stack.SerializeDeployment(snap, snap.SecretsManager, false)
+	require.NoError(t, err)
+	*current = &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: mustMarshal(t, deployment),
+	}
+	leaked := *current
+
+	configSecrets, err := stackConfigSecrets(s, ps)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hunter2"}, configSecrets)
+	detectors, err := secretscan.NewDetectors(secretscan.Options{}, configSecrets)
+	require.NoError(t, err)
+
+	expected := []secretscan.Finding{{
+		URN:      snap.Resources[0].URN,
+		Path:     resource.PropertyPath{"password"},
+		Detector: secretscan.ConfigDetector,
+	}}
+	// The findings refer to the states that were deserialized by the scan.
+	withoutStates := func(findings []secretscan.Finding) []secretscan.Finding {
+		for i := range findings {
+			findings[i].State = nil
+		}
+		return findings
+	}
+
+	// Scanning doesn't change the state.
+	findings, err := scanStackSecrets(ctx, s, ps, detectors, false)
+	require.NoError(t, err)
+	assert.Equal(t, expected, withoutStates(findings))
+	assert.Equal(t, leaked, *current)
+
+	// Marking makes the output secret.
+	findings, err = scanStackSecrets(ctx, s, ps, detectors, true)
+	require.NoError(t, err)
+	assert.Equal(t, expected, withoutStates(findings))
+
+	var marked apitype.DeploymentV3
+	require.NoError(t, json.Unmarshal((*current).Deployment, &marked))
+	require.Len(t, marked.Resources, 1)
+	assert.Equal(t, []resource.PropertyKey{"password"}, marked.Resources[0].AdditionalSecretOutputs)
+
+	findings, err = scanStackSecrets(ctx, s, ps, detectors, false)
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestScanSecretsOptions(t *testing.T) {
+	t.Parallel()
+
+	// up and preview configure the detectors with the same flags as `stack scan-secrets`, under a prefix.
+	var opts secretscan.Options
+	flags := pflag.NewFlagSet("up", pflag.ContinueOnError)
+	addScanSecretsFlags(flags, &opts, "scan-secrets-")
+	require.NoError(t, flags.Parse([]string{
+		"--scan-secrets-detectors", "entropy,tokens",
+		"--scan-secrets-min-length", "32",
+		"--scan-secrets-min-entropy", "5.5",
+	}))
+
+	assert.Nil(t, scanSecretsOptions(false, opts))
+	assert.Equal(t, &secretscan.Options{
+		Detectors:  []string{"entropy", "tokens"},
+		MinLength:  32,
+		MinEntropy: 5.5,
+	}, scanSecretsOptions(true, opts))
+
+	// The defaults are those of the scanner.
+	opts = secretscan.Options{}
+	flags = pflag.NewFlagSet("up", pflag.ContinueOnError)
+	addScanSecretsFlags(flags, &opts, "scan-secrets-")
+	require.NoError(t, flags.Parse(nil))
+	assert.Equal(t, &secretscan.Options{
+		MinLength:  secretscan.DefaultMinLength,
+		MinEntropy: secretscan.DefaultMinEntropy,
+	}, scanSecretsOptions(true, opts))
+}
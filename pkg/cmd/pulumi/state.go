@@ -50,7 +50,9 @@ troubleshooting a stack or when performing specific edits that otherwise would r
 	cmd.AddCommand(newStateDeleteCommand())
 	cmd.AddCommand(newStateUnprotectCommand())
 	cmd.AddCommand(newStateRenameCommand())
+	cmd.AddCommand(newStateMoveCommand())
 	cmd.AddCommand(newStateUpgradeCommand())
+	cmd.AddCommand(newStateQueryCommand())
 	return cmd
 }
 
@@ -151,17 +153,8 @@ func totalStateEdit(ctx context.Context, s backend.Stack, showPrompt bool, opts
 		return nil
 	}
 
-	if showPrompt && cmdutil.Interactive() {
-		confirm := false
-		surveycore.DisableColor = true
-		prompt := opts.Color.Colorize(colors.Yellow + "warning" + colors.Reset + ": ")
-		prompt += "This command will edit your stack's state directly. Confirm?"
-		if err = survey.AskOne(&survey.Confirm{
-			Message: prompt,
-		}, &confirm, surveyIcons(opts.Color)); err != nil || !confirm {
-			fmt.Println("confirmation declined")
-			return result.Bail()
-		}
+	if showPrompt && !confirmStateEdit(opts, "This command will edit your stack's state directly. Confirm?") {
+		return result.Bail()
 	}
 
 	// The `operation` callback will mutate `snap` in-place. In order to validate the correctness of the transformation
@@ -177,19 +170,44 @@ func totalStateEdit(ctx context.Context, s backend.Stack, showPrompt bool, opts
 		contract.AssertNoErrorf(snap.VerifyIntegrity(), "state edit produced an invalid snapshot")
 	}
 
+	return result.WrapIfNonNil(saveSnapshot(ctx, s, snap))
+}
+
+// confirmStateEdit asks the user to confirm a state edit with the given message if the current session is
+// interactive. It returns false if the user declined.
+func confirmStateEdit(opts display.Options, message string) bool {
+	if !cmdutil.Interactive() {
+		return true
+	}
+
+	confirm := false
+	surveycore.DisableColor = true
+	prompt := opts.Color.Colorize(colors.Yellow + "warning" + colors.Reset + ": ")
+	prompt += message
+	if err := survey.AskOne(&survey.Confirm{
+		Message: prompt,
+	}, &confirm, surveyIcons(opts.Color)); err != nil || !confirm {
+		fmt.Println("confirmation declined")
+		return false
+	}
+	return true
+}
+
+// saveSnapshot serializes the given snapshot using its secrets manager and imports it into the given stack.
+func saveSnapshot(ctx context.Context, s backend.Stack, snap *deploy.Snapshot) error {
 	sdep, err := stack.SerializeDeployment(snap, snap.SecretsManager, false /* showSecrets */)
 	if err != nil {
-		return result.FromError(fmt.Errorf("serializing deployment: %w", err))
+		return fmt.Errorf("serializing deployment: %w", err)
 	}
 
 	// Once we've mutated the snapshot, import it back into the backend so that it can be persisted.
 	bytes, err := json.Marshal(sdep)
 	if err != nil {
-		return result.FromError(err)
+		return err
 	}
 	dep := apitype.UntypedDeployment{
 		Version:    apitype.DeploymentSchemaVersionCurrent,
 		Deployment: bytes,
 	}
-	return result.WrapIfNonNil(s.ImportDeployment(ctx, &dep))
+	return s.ImportDeployment(ctx, &dep)
 }
@@ -0,0 +1,232 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/version"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+
+	"github.com/spf13/cobra"
+)
+
+func newStateMoveCommand() *cobra.Command {
+	var source string
+	var dest string
+	var force bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "move <resource URN>...",
+		Short: "Moves resources from one stack's state to another",
+		Long: `Moves resources from one stack's state to another
+
+This command moves resources, along with all of their children, from the state of a source stack into the
+state of a destination stack. Resources are specified by their Pulumi URN (use ` + "`pulumi stack --show-urns`" + `
+to get it) or by a glob, e.g. 'urn:pulumi:dev::proj::aws:s3/bucket:Bucket::*'.
+
+The URNs of the moved resources are rewritten to belong to the destination stack, and their secrets are
+re-encrypted using the destination stack's secrets provider. Providers used by the moved resources are copied
+into the destination stack, and removed from the source stack if no other resource there uses them.
+
+Resources can't be moved if doing so would leave a resource in either stack depending on a resource in the
+other. Pass --force to move them anyway and drop the dangling dependencies.
+
+Make sure that URNs are single-quoted to avoid having characters unexpectedly interpreted by the shell.
+
+Example:
+pulumi state move --source dev --dest network 'urn:pulumi:dev::demo::aws:ec2/vpc:Vpc::main'
+`,
+		Args: cmdutil.MinimumNArgs(1),
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			ctx := commandContext()
+			yes = yes || skipConfirmations()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if dest == "" {
+				return result.Error("a destination stack must be specified with --dest")
+			}
+
+			sourceStack, err := requireStack(ctx, source, stackLoadOnly, opts)
+			if err != nil {
+				return result.FromError(err)
+			}
+			destStack, err := requireStack(ctx, dest, stackLoadOnly, opts)
+			if err != nil {
+				return result.FromError(err)
+			}
+			if sourceStack.Ref().FullyQualifiedName() == destStack.Ref().FullyQualifiedName() {
+				return result.Error("the source and destination stacks must be different")
+			}
+
+			sourceSnap, err := sourceStack.Snapshot(ctx, stack.DefaultSecretsProvider)
+			if err != nil {
+				return result.FromError(err)
+			} else if sourceSnap == nil {
+				return result.Errorf("stack %s has no resources", sourceStack.Ref())
+			}
+			destSnap, err := loadMoveDestination(ctx, destStack)
+			if err != nil {
+				return result.FromError(err)
+			}
+			destProject, err := moveDestinationProject(destStack, destSnap)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			if !yes && !confirmStateEdit(opts, fmt.Sprintf(
+				"This command will edit the state of stacks %s and %s directly. Confirm?",
+				sourceStack.Ref(), destStack.Ref())) {
+				return result.Bail()
+			}
+
+			// As with other state edits, only check that moving the resources didn't break the snapshots if they
+			// were valid to begin with.
+			sourceIsAlreadyHosed := sourceSnap.VerifyIntegrity() != nil
+			destIsAlreadyHosed := destSnap.VerifyIntegrity() != nil
+
+			moved, err := edit.MoveResources(sourceSnap, destSnap, deploy.NewUrnTargets(args),
+				destStack.Ref().Name(), destProject, force)
+			if err != nil {
+				var danglingErr edit.MoveHasDanglingDependenciesError
+				if errors.As(err, &danglingErr) {
+					message := "The resources can't be safely moved because of the following dependencies:\n"
+					for _, dep := range danglingErr.Dependencies {
+						message += fmt.Sprintf(" * %s depends on %s\n", dep.From.URN, dep.To)
+					}
+					message += "\nMove those resources as well or pass --force."
+					return result.Error(message)
+				}
+				return result.FromError(err)
+			}
+
+			if !sourceIsAlreadyHosed {
+				if err := sourceSnap.VerifyIntegrity(); err != nil {
+					return result.FromError(fmt.Errorf("moving the resources produced an invalid snapshot of %s: %w",
+						sourceStack.Ref(), err))
+				}
+			}
+			if !destIsAlreadyHosed {
+				if err := destSnap.VerifyIntegrity(); err != nil {
+					return result.FromError(fmt.Errorf("moving the resources produced an invalid snapshot of %s: %w",
+						destStack.Ref(), err))
+				}
+			}
+
+			// Write the destination first: if writing the source fails afterwards, the resources are duplicated
+			// rather than lost.
+			if err := saveSnapshot(ctx, destStack, destSnap); err != nil {
+				return result.FromError(fmt.Errorf("saving destination stack: %w", err))
+			}
+			if err := saveSnapshot(ctx, sourceStack, sourceSnap); err != nil {
+				return result.FromError(fmt.Errorf("saving source stack: %w", err))
+			}
+
+			fmt.Printf("Moved %d resources to %s\n", len(moved), destStack.Ref())
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&source, "source", "s", "",
+		"The name of the stack to move resources from. Defaults to the current stack")
+	cmd.PersistentFlags().StringVar(
+		&dest, "dest", "",
+		"The name of the stack to move resources to")
+	cmd.Flags().BoolVar(&force, "force", false, "Move resources even if this leaves dangling dependencies")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+	return cmd
+}
+
+// loadMoveDestination loads the snapshot of the stack that resources are being moved into. If the stack has never
+// been deployed, an empty snapshot using the stack's configured secrets manager is returned. The stack's settings
+// are read from the current project, which must be the stack's own project.
+func loadMoveDestination(ctx context.Context, s backend.Stack) (*deploy.Snapshot, error) {
+	snap, err := s.Snapshot(ctx, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, err
+	}
+	if snap != nil {
+		return snap, nil
+	}
+
+	project, _, err := readProject()
+	if err != nil {
+		if errors.Is(err, workspace.ErrProjectNotFound) {
+			return nil, fmt.Errorf("stack %s has never been deployed, so this command must be run from the "+
+				"directory of its project to set up its secrets provider", s.Ref())
+		}
+		return nil, err
+	}
+	if name, ok := s.Ref().Project(); ok && name != tokens.Name(project.Name) {
+		return nil, fmt.Errorf("stack %s has never been deployed, so this command must be run from the "+
+			"directory of its project %s rather than %s to set up its secrets provider", s.Ref(), name, project.Name)
+	}
+	ps, err := loadProjectStack(project, s)
+	if err != nil {
+		return nil, err
+	}
+	sm, needsSave, err := getStackSecretsManager(s, ps)
+	if err != nil {
+		return nil, err
+	}
+	if needsSave {
+		if err = saveProjectStack(s, ps); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest := deploy.Manifest{
+		Time:    time.Now(),
+		Version: version.Version,
+	}
+	manifest.Magic = manifest.NewMagic()
+	return deploy.NewSnapshot(manifest, sm, nil, nil), nil
+}
+
+// moveDestinationProject determines the project that resources moved into the given stack should belong to.
+func moveDestinationProject(s backend.Stack, snap *deploy.Snapshot) (tokens.PackageName, error) {
+	if project, ok := s.Ref().Project(); ok {
+		return tokens.PackageName(project), nil
+	}
+	for _, res := range snap.Resources {
+		if res.Type == resource.RootStackType {
+			return res.URN.Project(), nil
+		}
+	}
+	project, _, err := readProject()
+	if err != nil {
+		if errors.Is(err, workspace.ErrProjectNotFound) {
+			return "", fmt.Errorf("could not determine the project of stack %s", s.Ref())
+		}
+		return "", err
+	}
+	return project.Name, nil
+}
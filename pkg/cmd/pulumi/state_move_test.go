@@ -0,0 +1,49 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+)
+
+//nolint:paralleltest // changes directory for process
+func TestLoadMoveDestinationOfOtherProject(t *testing.T) {
+	tempdir := t.TempDir()
+	chdir(t, tempdir)
+	require.NoError(t, os.WriteFile(filepath.Join(tempdir, "Pulumi.yaml"),
+		[]byte("name: current\nruntime: go\n"), 0o600))
+
+	// A stack that has never been deployed can't use the settings of the current project if it belongs to another.
+	s := &backend.MockStack{
+		RefF: func() backend.StackReference {
+			return &backend.MockStackReference{StringV: "org/other/dev", NameV: "dev", ProjectV: "other"}
+		},
+		SnapshotF: func(ctx context.Context, secretsProvider secrets.Provider) (*deploy.Snapshot, error) {
+			return nil, nil
+		},
+	}
+	_, err := loadMoveDestination(context.Background(), s)
+	assert.ErrorContains(t, err, "must be run from the directory of its project other rather than current")
+}
@@ -0,0 +1,190 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/resource/statequery"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newStateQueryCommand() *cobra.Command {
+	var stackName string
+	var file string
+	var showSecrets bool
+	var jsonOut bool
+
+	cmd := &cobra.Command{
+		Use:   "query <query>",
+		Short: "Query the resources in a stack's state",
+		Long: "Query the resources in a stack's state\n" +
+			"\n" +
+			"This command answers questions about a stack from its state alone, without running its program or\n" +
+			"contacting its providers. The state is exposed as two tables:\n" +
+			"\n" +
+			"  resources:    urn, type, name, id, custom, provider, parent, protect, external, delete, inputs,\n" +
+			"                outputs and dependencies, with a row for each resource\n" +
+			"  dependencies: urn, dependency and properties, with a row for each resource that a resource\n" +
+			"                depends on, and the properties whose values depend on it\n" +
+			"\n" +
+			"Queries have the form:\n" +
+			"\n" +
+			"  SELECT <expr> [AS <name>], ... FROM <table> [<alias>] [JOIN <table> [<alias>] ON <expr>]...\n" +
+			"    [WHERE <expr>] [ORDER BY <expr> [ASC|DESC], ...] [LIMIT <n>]\n" +
+			"\n" +
+			"Expressions can refer to properties by paths such as `outputs.tags.team` or `r.inputs[\"acl\"]`,\n" +
+			"and support =, !=, <, <=, >, >=, AND, OR, NOT, IN, LIKE and IS [NOT] NULL. A query that does not\n" +
+			"start with SELECT is a filter over the resources. For example, to find the public buckets:\n" +
+			"\n" +
+			"  pulumi state query \"type = 'aws:s3/bucket:Bucket' AND outputs.acl LIKE 'public%'\"\n" +
+			"\n" +
+			"Or to find the resources that depend on a database:\n" +
+			"\n" +
+			"  pulumi state query \"SELECT r.urn FROM resources r JOIN resources db ON db.urn IN r.dependencies\n" +
+			"    WHERE db.type = 'aws:rds/instance:Instance'\"\n" +
+			"\n" +
+			"The state is read from the stack, or from a file written by `pulumi stack export` with --file.",
+		Args: cmdutil.ExactArgs(1),
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := commandContext()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			query, err := statequery.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("parsing query: %w", err)
+			}
+
+			var snap *deploy.Snapshot
+			if file != "" {
+				if snap, err = readStateQueryFile(ctx, file); err != nil {
+					return err
+				}
+			} else {
+				s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+				if err != nil {
+					return err
+				}
+				if snap, err = s.Snapshot(ctx, stack.DefaultSecretsProvider); err != nil {
+					return err
+				}
+				if showSecrets {
+					log3rdPartySecretsProviderDecryptionEvent(ctx, s, "", "pulumi state query")
+				}
+			}
+
+			result, err := query.Run(statequery.SnapshotTables(snap, showSecrets))
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				objects := result.Objects()
+				if objects == nil {
+					objects = []map[string]interface{}{}
+				}
+				return printJSON(objects)
+			}
+			printStateQueryResult(result)
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.PersistentFlags().StringVar(
+		&file, "file", "",
+		"Query the state in the given file, written by `pulumi stack export`, instead of the stack's state")
+	cmd.PersistentFlags().BoolVar(
+		&showSecrets, "show-secrets", false,
+		"Show the values of secrets instead of [secret]")
+	cmd.PersistentFlags().BoolVarP(
+		&jsonOut, "json", "j", false,
+		"Emit the result as JSON")
+	return cmd
+}
+
+// readStateQueryFile reads the state that `pulumi stack export` wrote to the given file.
+func readStateQueryFile(ctx context.Context, file string) (*deploy.Snapshot, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not open file: %w", err)
+	}
+	defer f.Close()
+
+	var deployment apitype.UntypedDeployment
+	if err := json.NewDecoder(f).Decode(&deployment); err != nil {
+		return nil, fmt.Errorf("could not read deployment from %s: %w", file, err)
+	}
+	snap, err := stack.DeserializeUntypedDeployment(ctx, &deployment, stack.DefaultSecretsProvider)
+	if err != nil {
+		return nil, checkDeploymentVersionError(err, file)
+	}
+	return snap, nil
+}
+
+func printStateQueryResult(result *statequery.Result) {
+	headers := make([]string, len(result.Columns))
+	for i, column := range result.Columns {
+		headers[i] = strings.ToUpper(column)
+	}
+	rows := make([]cmdutil.TableRow, len(result.Rows))
+	for i, row := range result.Rows {
+		columns := make([]string, len(row))
+		for j, v := range row {
+			columns[j] = formatStateQueryValue(v)
+		}
+		rows[i] = cmdutil.TableRow{Columns: columns}
+	}
+	cmdutil.PrintTable(cmdutil.Table{
+		Headers: headers,
+		Rows:    rows,
+	})
+}
+
+// formatStateQueryValue formats a value of a query's result for a table cell. Strings and numbers are shown as they
+// are, and arrays and objects as compact JSON.
+func formatStateQueryValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(b)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
 	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/secretscan"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
@@ -81,6 +82,9 @@ func newUpCmd() *cobra.Command {
 	var targetReplaces []string
 	var targetDependents bool
 	var planFilePath string
+	var continueOnError bool
+	var scanSecrets bool
+	var scanSecretsOpts secretscan.Options
 
 	// up implementation used when the source of the Pulumi program is in the current working directory.
 	upWorkingDirectory := func(ctx context.Context, opts backend.UpdateOptions, cmd *cobra.Command) result.Result {
@@ -117,7 +121,7 @@ func newUpCmd() *cobra.Command {
 		stackName := s.Ref().Name().String()
 		configErr := workspace.ValidateStackConfigAndApplyProjectConfig(stackName, proj, cfg.Config, decrypter)
 		if configErr != nil {
-			return result.FromError(fmt.Errorf("validating stack config: %w", configErr))
+			return result.FromError(fmt.Errorf("validating stack config: %w", locateConfigError(proj, s, configErr)))
 		}
 
 		targetURNs, replaceURNs := []string{}, []string{}
@@ -148,8 +152,10 @@ func newUpCmd() *cobra.Command {
 			TargetDependents:          targetDependents,
 			// Trigger a plan to be generated during the preview phase which can be constrained to during the
 			// update phase.
-			GeneratePlan: true,
-			Experimental: hasExperimentalCommands(),
+			GeneratePlan:    true,
+			Experimental:    hasExperimentalCommands(),
+			ContinueOnError: continueOnError,
+			ScanSecrets:     scanSecretsOptions(scanSecrets, scanSecretsOpts),
 		}
 
 		if planFilePath != "" {
@@ -339,7 +345,7 @@ func newUpCmd() *cobra.Command {
 		stackName := s.Ref().String()
 		configErr := workspace.ValidateStackConfigAndApplyProjectConfig(stackName, proj, cfg.Config, decrypter)
 		if configErr != nil {
-			return result.FromError(fmt.Errorf("validating stack config: %w", configErr))
+			return result.FromError(fmt.Errorf("validating stack config: %w", locateConfigError(proj, s, configErr)))
 		}
 
 		refreshOption, err := getRefreshOption(proj, refresh)
@@ -354,8 +360,10 @@ func newUpCmd() *cobra.Command {
 			Refresh:          refreshOption,
 			// If we're in experimental mode then we trigger a plan to be generated during the preview phase
 			// which will be constrained to during the update phase.
-			GeneratePlan: hasExperimentalCommands(),
-			Experimental: hasExperimentalCommands(),
+			GeneratePlan:    hasExperimentalCommands(),
+			Experimental:    hasExperimentalCommands(),
+			ContinueOnError: continueOnError,
+			ScanSecrets:     scanSecretsOptions(scanSecrets, scanSecretsOpts),
 		}
 
 		// TODO for the URL case:
@@ -467,6 +475,9 @@ func newUpCmd() *cobra.Command {
 				if err != nil {
 					return result.FromError(err)
 				}
+				if continueOnError {
+					return result.FromError(errors.New("--continue-on-error is not supported with --remote"))
+				}
 
 				return runDeployment(ctx, opts.Display, apitype.Update, stackName, args[0], remoteArgs)
 			}
@@ -510,7 +521,7 @@ func newUpCmd() *cobra.Command {
 		"Config keys contain a path to a property in a map or list to set")
 	cmd.PersistentFlags().StringVar(
 		&secretsProvider, "secrets-provider", "default", "The type of the provider that should be used to encrypt and "+
-			"decrypt secrets (possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault). Only "+
+			"decrypt secrets (possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault, plugin, age). Only "+
 			"used when creating a new stack from an existing template")
 
 	cmd.PersistentFlags().StringVar(
@@ -537,6 +548,14 @@ func newUpCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&targetDependents, "target-dependents", false,
 		"Allows updating of dependent targets discovered but not specified in --target list")
+	cmd.PersistentFlags().BoolVar(
+		&continueOnError, "continue-on-error", false,
+		"Continue updating the resources that don't depend on a failed resource, and report all failures at the end")
+	cmd.PersistentFlags().BoolVar(
+		&scanSecrets, "scan-secrets", false,
+		"Warn about resource outputs that look like secrets but are not marked as secret. The detectors are "+
+			"configured with the --scan-secrets-* flags, as for `pulumi stack scan-secrets`")
+	addScanSecretsFlags(cmd.PersistentFlags(), &scanSecretsOpts, "scan-secrets-")
 
 	// Flags for engine.UpdateOptions.
 	cmd.PersistentFlags().StringSliceVar(
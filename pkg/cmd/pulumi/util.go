@@ -46,8 +46,11 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/age"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/cloud"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/plugin"
 	"github.com/pulumi/pulumi/pkg/v3/util/cancel"
 	"github.com/pulumi/pulumi/pkg/v3/util/tracing"
 	"github.com/pulumi/pulumi/pkg/v3/version"
@@ -203,16 +206,7 @@ func createSecretsManager(
 	}
 
 	oldConfig := deepcopy.Copy(ps).(*workspace.ProjectStack)
-	if isDefaultSecretsProvider {
-		_, err = stack.DefaultSecretManager(ps)
-	} else if secretsProvider == passphrase.Type {
-		_, err = passphrase.NewPromptingPassphraseSecretsManager(ps, rotateSecretsProvider)
-	} else {
-		// All other non-default secrets providers are handled by the cloud secrets provider which
-		// uses a URL schema to identify the provider
-		_, err = cloud.NewCloudSecretsManager(ps, secretsProvider, rotateSecretsProvider)
-	}
-	if err != nil {
+	if _, err = newSecretsManager(stack, ps, secretsProvider, rotateSecretsProvider); err != nil {
 		return err
 	}
 
@@ -226,6 +220,27 @@ func createSecretsManager(
 	return nil
 }
 
+// newSecretsManager returns the secrets manager for the given secrets provider, recording the provider's settings
+// (e.g. its encrypted data key) in ps.
+func newSecretsManager(stack backend.Stack, ps *workspace.ProjectStack,
+	secretsProvider string, rotateSecretsProvider bool,
+) (secrets.Manager, error) {
+	switch {
+	case secretsProvider == "" || secretsProvider == "default":
+		return stack.DefaultSecretManager(ps)
+	case secretsProvider == passphrase.Type:
+		return passphrase.NewPromptingPassphraseSecretsManager(ps, rotateSecretsProvider)
+	case age.IsAgeURL(secretsProvider):
+		return age.NewAgeSecretsManager(ps, secretsProvider, rotateSecretsProvider)
+	case plugin.IsPluginURL(secretsProvider):
+		return plugin.NewPluginSecretsManager(ps, secretsProvider, rotateSecretsProvider)
+	default:
+		// All other non-default secrets providers are handled by the cloud secrets provider which
+		// uses a URL schema to identify the provider
+		return cloud.NewCloudSecretsManager(ps, secretsProvider, rotateSecretsProvider)
+	}
+}
+
 // createStack creates a stack with the given name, and optionally selects it as the current.
 func createStack(ctx context.Context,
 	b backend.Backend, stackRef backend.StackReference,
@@ -122,7 +122,7 @@ func newWatchCmd() *cobra.Command {
 			stackName := s.Ref().Name().String()
 			configErr := workspace.ValidateStackConfigAndApplyProjectConfig(stackName, proj, cfg.Config, decrypter)
 			if configErr != nil {
-				return result.FromError(fmt.Errorf("validating stack config: %w", configErr))
+				return result.FromError(fmt.Errorf("validating stack config: %w", locateConfigError(proj, s, configErr)))
 			}
 
 			opts.Engine = engine.UpdateOptions{
@@ -180,7 +180,7 @@ func newWatchCmd() *cobra.Command {
 		"Config keys contain a path to a property in a map or list to set")
 	cmd.PersistentFlags().StringVar(
 		&secretsProvider, "secrets-provider", "default", "The type of the provider that should be used to encrypt and "+
-			"decrypt secrets (possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault). Only "+
+			"decrypt secrets (possible choices: default, passphrase, awskms, azurekeyvault, gcpkms, hashivault, plugin, age). Only "+
 			"used when creating a new stack from an existing template")
 
 	cmd.PersistentFlags().StringVarP(
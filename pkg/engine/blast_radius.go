@@ -0,0 +1,144 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/graph"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+// AffectedResource describes a resource that directly or indirectly depends on a resource that is replaced or deleted
+// by a preview. Op is the operation that the preview plans for the resource itself: one of deploy.OpReplace,
+// deploy.OpDelete, deploy.OpUpdate or deploy.OpSame.
+type AffectedResource struct {
+	URN       resource.URN   `json:"urn"`
+	Type      tokens.Type    `json:"type"`
+	Op        display.StepOp `json:"op"`
+	Protected bool           `json:"protected,omitempty"`
+	// Via lists the resources in the blast radius that this resource depends on, in the order of the stack's state.
+	Via []resource.URN `json:"via,omitempty"`
+}
+
+// BlastRadius describes the resources that are affected by replacing or deleting a single resource.
+type BlastRadius struct {
+	URN       resource.URN       `json:"urn"`
+	Type      tokens.Type        `json:"type"`
+	Op        display.StepOp     `json:"op"`
+	Protected bool               `json:"protected,omitempty"`
+	Affected  []AffectedResource `json:"affected"`
+	// ProtectedChain lists the protected resources among the resource and the resources it affects.
+	ProtectedChain []resource.URN `json:"protectedChain,omitempty"`
+}
+
+// BlastRadiusReport is the machine-readable summary of the blast radius of a preview.
+type BlastRadiusReport struct {
+	Changes []BlastRadius `json:"changes"`
+}
+
+// plannedOp summarizes the operations that a plan contains for a resource as one of deploy.OpReplace,
+// deploy.OpDelete, deploy.OpUpdate or deploy.OpSame.
+func plannedOp(plan *deploy.Plan, urn resource.URN) display.StepOp {
+	rp, ok := plan.ResourcePlans[urn]
+	if !ok {
+		return deploy.OpSame
+	}
+
+	op := deploy.OpSame
+	for _, o := range rp.Ops {
+		switch o {
+		case deploy.OpReplace, deploy.OpCreateReplacement, deploy.OpDeleteReplaced:
+			return deploy.OpReplace
+		case deploy.OpDelete:
+			op = deploy.OpDelete
+		case deploy.OpUpdate:
+			if op == deploy.OpSame {
+				op = deploy.OpUpdate
+			}
+		}
+	}
+	return op
+}
+
+// NewBlastRadiusReport computes the blast radius of each replace and delete that is planned for the resources in the
+// given snapshot. The blast radius of a resource is the set of resources that transitively depend on it, including
+// its descendants and, for providers, the resources that it manages. Changes are listed in the order of the snapshot.
+func NewBlastRadiusReport(snap *deploy.Snapshot, plan *deploy.Plan) BlastRadiusReport {
+	report := BlastRadiusReport{Changes: []BlastRadius{}}
+	if snap == nil || plan == nil {
+		return report
+	}
+
+	// Resources that are pending deletion are already on their way out, so they are ignored.
+	var resources []*resource.State
+	for _, res := range snap.Resources {
+		if !res.Delete {
+			resources = append(resources, res)
+		}
+	}
+	dg := graph.NewDependencyGraph(resources)
+
+	for _, res := range resources {
+		op := plannedOp(plan, res.URN)
+		if op != deploy.OpReplace && op != deploy.OpDelete {
+			continue
+		}
+
+		change := BlastRadius{
+			URN:       res.URN,
+			Type:      res.Type,
+			Op:        op,
+			Protected: res.Protect,
+			Affected:  []AffectedResource{},
+		}
+		if res.Protect {
+			change.ProtectedChain = append(change.ProtectedChain, res.URN)
+		}
+
+		dependents := dg.DependingOn(res, nil, true)
+		chain := map[resource.URN]bool{res.URN: true}
+		for _, dep := range dependents {
+			chain[dep.URN] = true
+		}
+
+		for _, dep := range dependents {
+			// The resources through which a dependent is affected are those of its transitive dependencies that are
+			// part of the chain.
+			deps := dg.TransitiveDependenciesOf(dep)
+			var via []resource.URN
+			for _, r := range resources {
+				if deps[r] && chain[r.URN] {
+					via = append(via, r.URN)
+				}
+			}
+
+			change.Affected = append(change.Affected, AffectedResource{
+				URN:       dep.URN,
+				Type:      dep.Type,
+				Op:        plannedOp(plan, dep.URN),
+				Protected: dep.Protect,
+				Via:       via,
+			})
+			if dep.Protect {
+				change.ProtectedChain = append(change.ProtectedChain, dep.URN)
+			}
+		}
+
+		report.Changes = append(report.Changes, change)
+	}
+	return report
+}
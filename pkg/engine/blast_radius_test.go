@@ -0,0 +1,145 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBlastRadiusReport(t *testing.T) {
+	t.Parallel()
+
+	newState := func(typ tokens.Type, name string, deps ...*resource.State) *resource.State {
+		res := &resource.State{
+			URN:    resource.NewURN("stack", "proj", "", typ, tokens.QName(name)),
+			Type:   typ,
+			Custom: true,
+		}
+		for _, dep := range deps {
+			res.Dependencies = append(res.Dependencies, dep.URN)
+		}
+		return res
+	}
+
+	prov := newState("pulumi:providers:aws", "default")
+	prov.ID = "prov-id"
+	provRef, err := providers.NewReference(prov.URN, prov.ID)
+	require.NoError(t, err)
+
+	vpc := newState("aws:ec2/vpc:Vpc", "vpc")
+	vpc.Provider = provRef.String()
+	pendingDelete := newState("aws:ec2/vpc:Vpc", "vpc")
+	pendingDelete.Delete = true
+	subnet := newState("aws:ec2/subnet:Subnet", "subnet", vpc)
+	subnet.Protect = true
+	instance := newState("aws:ec2/instance:Instance", "instance", subnet)
+	alarm := newState("aws:cloudwatch/metricAlarm:MetricAlarm", "alarm", instance)
+	bucket := newState("aws:s3/bucket:Bucket", "bucket")
+
+	snap := &deploy.Snapshot{
+		Resources: []*resource.State{prov, pendingDelete, vpc, subnet, instance, alarm, bucket},
+	}
+	plan := &deploy.Plan{
+		ResourcePlans: map[resource.URN]*deploy.ResourcePlan{
+			prov.URN:     {Ops: []display.StepOp{deploy.OpSame}},
+			vpc.URN:      {Ops: []display.StepOp{deploy.OpCreateReplacement, deploy.OpReplace}},
+			subnet.URN:   {Ops: []display.StepOp{deploy.OpCreateReplacement, deploy.OpReplace}},
+			instance.URN: {Ops: []display.StepOp{deploy.OpUpdate}},
+			bucket.URN:   {Ops: []display.StepOp{deploy.OpDelete}},
+		},
+	}
+
+	report := NewBlastRadiusReport(snap, plan)
+	assert.Equal(t, BlastRadiusReport{
+		Changes: []BlastRadius{
+			{
+				URN:  vpc.URN,
+				Type: vpc.Type,
+				Op:   deploy.OpReplace,
+				Affected: []AffectedResource{
+					{
+						URN:       subnet.URN,
+						Type:      subnet.Type,
+						Op:        deploy.OpReplace,
+						Protected: true,
+						Via:       []resource.URN{vpc.URN},
+					},
+					{
+						URN:  instance.URN,
+						Type: instance.Type,
+						Op:   deploy.OpUpdate,
+						Via:  []resource.URN{vpc.URN, subnet.URN},
+					},
+					{
+						URN:  alarm.URN,
+						Type: alarm.Type,
+						Op:   deploy.OpSame,
+						Via:  []resource.URN{vpc.URN, subnet.URN, instance.URN},
+					},
+				},
+				ProtectedChain: []resource.URN{subnet.URN},
+			},
+			{
+				URN:       subnet.URN,
+				Type:      subnet.Type,
+				Op:        deploy.OpReplace,
+				Protected: true,
+				Affected: []AffectedResource{
+					{
+						URN:  instance.URN,
+						Type: instance.Type,
+						Op:   deploy.OpUpdate,
+						Via:  []resource.URN{subnet.URN},
+					},
+					{
+						URN:  alarm.URN,
+						Type: alarm.Type,
+						Op:   deploy.OpSame,
+						Via:  []resource.URN{subnet.URN, instance.URN},
+					},
+				},
+				ProtectedChain: []resource.URN{subnet.URN},
+			},
+			{
+				URN:      bucket.URN,
+				Type:     bucket.Type,
+				Op:       deploy.OpDelete,
+				Affected: []AffectedResource{},
+			},
+		},
+	}, report)
+
+	// Replacing the provider affects every resource that it manages.
+	plan.ResourcePlans[prov.URN].Ops = []display.StepOp{deploy.OpReplace}
+	report = NewBlastRadiusReport(snap, plan)
+	require.Len(t, report.Changes, 4)
+	assert.Equal(t, prov.URN, report.Changes[0].URN)
+	var affected []resource.URN
+	for _, res := range report.Changes[0].Affected {
+		affected = append(affected, res.URN)
+	}
+	assert.Equal(t, []resource.URN{vpc.URN, subnet.URN, instance.URN, alarm.URN}, affected)
+
+	assert.Equal(t, BlastRadiusReport{Changes: []BlastRadius{}}, NewBlastRadiusReport(snap, nil))
+}
@@ -148,6 +148,10 @@ type deploymentOptions struct {
 	// true if we should trust the dependency graph reported by the language host. Not all Pulumi-supported languages
 	// correctly report their dependencies, in which case this will be false.
 	trustDependencies bool
+
+	// the maximum number of concurrent resource operations of the providers of each package, from the project's
+	// options.
+	providerParallelism map[tokens.Package]int
 }
 
 // deploymentSourceFunc is a callback that will be used to prepare for, and evaluate, the "new" state for a stack.
@@ -184,6 +188,12 @@ func newDeployment(ctx *Context, info *deploymentContext, opts deploymentOptions
 	plugctx = plugctx.WithCancelChannel(ctx.Cancel.Canceled())
 
 	opts.trustDependencies = proj.TrustResourceDependencies()
+	if proj.Options != nil && len(proj.Options.ProviderParallelism) > 0 {
+		opts.providerParallelism = make(map[tokens.Package]int)
+		for pkg, limit := range proj.Options.ProviderParallelism {
+			opts.providerParallelism[tokens.Package(pkg)] = limit
+		}
+	}
 	// Now create the state source.  This may issue an error if it can't create the source.  This entails,
 	// for example, loading any plugins which will be required to execute a program, among other things.
 	source, err := opts.SourceFunc(ctx.BackendClient, opts, proj, pwd, main, target, plugctx, dryRun)
@@ -250,6 +260,7 @@ type runActions interface {
 
 	Changes() display.ResourceChanges
 	MaybeCorrupt() bool
+	Failures() []ResourceFailure
 }
 
 // run executes the deployment. It is primarily responsible for handling cancellation.
@@ -296,6 +307,8 @@ func (deployment *deployment) run(cancelCtx *Context, actions runActions, policy
 			DisableResourceReferences: deployment.Options.DisableResourceReferences,
 			DisableOutputValues:       deployment.Options.DisableOutputValues,
 			GeneratePlan:              deployment.Options.UpdateOptions.GeneratePlan,
+			ContinueOnError:           deployment.Options.ContinueOnError,
+			ProviderParallelism:       deployment.Options.providerParallelism,
 		}
 		newPlan, walkResult = deployment.Deployment.Execute(ctx, opts, preview)
 		close(done)
@@ -326,7 +339,8 @@ func (deployment *deployment) run(cancelCtx *Context, actions runActions, policy
 	changes := actions.Changes()
 
 	// Emit a summary event.
-	deployment.Options.Events.summaryEvent(preview, actions.MaybeCorrupt(), duration, changes, policyPacks)
+	deployment.Options.Events.summaryEvent(preview, actions.MaybeCorrupt(), duration, changes, policyPacks,
+		actions.Failures())
 
 	return newPlan, changes, res
 }
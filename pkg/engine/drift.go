@@ -0,0 +1,158 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+// ResourceDrift describes how the live state of a single resource, as read from its provider during a refresh,
+// differs from the state recorded in the stack's checkpoint. Property paths are formatted as by
+// resource.PropertyPath.
+type ResourceDrift struct {
+	URN     resource.URN `json:"urn"`
+	Type    tokens.Type  `json:"type"`
+	Deleted bool         `json:"deleted,omitempty"` // true if the resource no longer exists.
+	Added   []string     `json:"added,omitempty"`   // paths of properties that only exist in the live state.
+	Removed []string     `json:"removed,omitempty"` // paths of properties that only exist in the recorded state.
+	Changed []string     `json:"changed,omitempty"` // paths of properties whose values differ.
+}
+
+// DriftReport is the machine-readable summary of a drift detection run.
+type DriftReport struct {
+	Resources []ResourceDrift `json:"resources"`
+}
+
+// HasDrift returns true if any resource in the report has drifted.
+func (r *DriftReport) HasDrift() bool {
+	return len(r.Resources) != 0
+}
+
+// RecordRefresh adds the result of a refresh to the report. It is intended to be called with the payload of each
+// ResourceOutputsEvent that is emitted for a refresh step.
+func (r *DriftReport) RecordRefresh(payload ResourceOutputsEventPayload) {
+	if payload.Drift != nil {
+		r.Resources = append(r.Resources, *payload.Drift)
+	}
+}
+
+// Sort orders the resources in the report by URN so that reports are stable across runs.
+func (r *DriftReport) Sort() {
+	sort.Slice(r.Resources, func(i, j int) bool {
+		return r.Resources[i].URN < r.Resources[j].URN
+	})
+}
+
+// newRefreshDrift computes the drift detected by a refresh step whose result is op. The drift is computed from the
+// step's states rather than from the event metadata, whose secrets are masked and whose strings are filtered, so that
+// changes to secret outputs are detected. If the step is not a refresh or the resource has not drifted, nil is
+// returned.
+func newRefreshDrift(op display.StepOp, step deploy.Step) *ResourceDrift {
+	if step.Op() != deploy.OpRefresh || step.Old() == nil {
+		return nil
+	}
+
+	var live resource.PropertyMap
+	if step.New() != nil {
+		live = step.New().Outputs
+	}
+	return NewResourceDrift(step.URN(), step.Type(), step.Old().Outputs, live, op == deploy.OpDelete)
+}
+
+// NewResourceDrift computes the drift between the recorded and live outputs of a resource. If the resource has not
+// drifted, nil is returned.
+func NewResourceDrift(urn resource.URN, typ tokens.Type, recorded, live resource.PropertyMap,
+	deleted bool,
+) *ResourceDrift {
+	drift := &ResourceDrift{URN: urn, Type: typ, Deleted: deleted}
+	if deleted {
+		return drift
+	}
+
+	paths := DiffPropertyPaths(recorded, live)
+	if paths == nil {
+		return nil
+	}
+	drift.Added, drift.Removed, drift.Changed = paths.Added, paths.Removed, paths.Changed
+	return drift
+}
+
+// PropertyPathDiff lists the paths of the properties that differ between two property maps. Paths are formatted as by
+// resource.PropertyPath and sorted.
+type PropertyPathDiff struct {
+	Added   []string `json:"added,omitempty"`   // paths of properties that only exist in the new map.
+	Removed []string `json:"removed,omitempty"` // paths of properties that only exist in the old map.
+	Changed []string `json:"changed,omitempty"` // paths of properties whose values differ.
+}
+
+// DiffPropertyPaths computes the paths of the properties that differ between olds and news. If the maps are equal,
+// nil is returned.
+func DiffPropertyPaths(olds, news resource.PropertyMap) *PropertyPathDiff {
+	diff := olds.Diff(news)
+	if diff == nil {
+		return nil
+	}
+
+	var paths PropertyPathDiff
+	paths.addObjectDiff(nil, diff)
+
+	sort.Strings(paths.Added)
+	sort.Strings(paths.Removed)
+	sort.Strings(paths.Changed)
+	return &paths
+}
+
+func (d *PropertyPathDiff) addObjectDiff(path resource.PropertyPath, diff *resource.ObjectDiff) {
+	for k := range diff.Adds {
+		d.Added = append(d.Added, appendPath(path, string(k)).String())
+	}
+	for k := range diff.Deletes {
+		d.Removed = append(d.Removed, appendPath(path, string(k)).String())
+	}
+	for k, update := range diff.Updates {
+		d.addValueDiff(appendPath(path, string(k)), update)
+	}
+}
+
+func (d *PropertyPathDiff) addValueDiff(path resource.PropertyPath, diff resource.ValueDiff) {
+	switch {
+	case diff.Object != nil:
+		d.addObjectDiff(path, diff.Object)
+	case diff.Array != nil:
+		for i := range diff.Array.Adds {
+			d.Added = append(d.Added, appendPath(path, i).String())
+		}
+		for i := range diff.Array.Deletes {
+			d.Removed = append(d.Removed, appendPath(path, i).String())
+		}
+		for i, update := range diff.Array.Updates {
+			d.addValueDiff(appendPath(path, i), update)
+		}
+	default:
+		d.Changed = append(d.Changed, path.String())
+	}
+}
+
+// appendPath returns a copy of path with the given key or index appended.
+func appendPath(path resource.PropertyPath, key interface{}) resource.PropertyPath {
+	result := make(resource.PropertyPath, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, key)
+}
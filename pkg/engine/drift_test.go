@@ -0,0 +1,89 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResourceDrift(t *testing.T) {
+	t.Parallel()
+
+	urn := resource.URN("urn:pulumi:stack::proj::pkg:mod:Res::res")
+	recorded := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"name": "foo",
+		"tags": map[string]interface{}{
+			"env":   "dev",
+			"owner": "me",
+		},
+		"ports": []interface{}{80, 443},
+		"gone":  true,
+	})
+
+	// No drift.
+	assert.Nil(t, NewResourceDrift(urn, "pkg:mod:Res", recorded, recorded.Copy(), false))
+
+	live := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"name": "foo",
+		"tags": map[string]interface{}{
+			"env":  "prod",
+			"team": "ops",
+		},
+		"ports": []interface{}{80, 8443},
+		"extra": "value",
+	})
+	drift := NewResourceDrift(urn, "pkg:mod:Res", recorded, live, false)
+	require.NotNil(t, drift)
+	assert.Equal(t, []string{"extra", "tags.team"}, drift.Added)
+	assert.Equal(t, []string{"gone", "tags.owner"}, drift.Removed)
+	assert.Equal(t, []string{"ports[1]", "tags.env"}, drift.Changed)
+	assert.False(t, drift.Deleted)
+
+	// Deleted resources are always reported as drifted.
+	drift = NewResourceDrift(urn, "pkg:mod:Res", recorded, nil, true)
+	require.NotNil(t, drift)
+	assert.True(t, drift.Deleted)
+	assert.Empty(t, drift.Changed)
+}
+
+func TestDriftReportRecordRefresh(t *testing.T) {
+	t.Parallel()
+
+	recorded := resource.NewPropertyMapFromMap(map[string]interface{}{"name": "foo"})
+	live := resource.NewPropertyMapFromMap(map[string]interface{}{"name": "bar"})
+
+	var report DriftReport
+	report.RecordRefresh(ResourceOutputsEventPayload{})
+	assert.False(t, report.HasDrift())
+
+	report.RecordRefresh(ResourceOutputsEventPayload{
+		Drift: NewResourceDrift("urn:pulumi:stack::proj::pkg:mod:Res::b", "pkg:mod:Res", recorded, live, false),
+	})
+	report.RecordRefresh(ResourceOutputsEventPayload{
+		Drift: NewResourceDrift("urn:pulumi:stack::proj::pkg:mod:Res::a", "pkg:mod:Res", recorded, nil, true),
+	})
+	report.Sort()
+
+	require.True(t, report.HasDrift())
+	require.Len(t, report.Resources, 2)
+	assert.Equal(t, resource.URN("urn:pulumi:stack::proj::pkg:mod:Res::a"), report.Resources[0].URN)
+	assert.True(t, report.Resources[0].Deleted)
+	assert.Equal(t, []string{"name"}, report.Resources[1].Changed)
+}
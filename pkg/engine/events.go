@@ -16,9 +16,11 @@ package engine
 
 import (
 	"bytes"
+	"fmt"
 	"time"
 
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/secretscan"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
@@ -132,6 +134,14 @@ type SummaryEventPayload struct {
 	Duration        time.Duration           // the duration of the entire update operation (zero values for previews)
 	ResourceChanges display.ResourceChanges // count of changed resources, useful for reporting
 	PolicyPacks     map[string]string       // {policy-pack: version} for each policy pack applied
+	Failures        []ResourceFailure       // the resource operations that failed, when continuing on errors
+}
+
+// ResourceFailure describes a resource operation that failed during a deployment that continued on errors.
+type ResourceFailure struct {
+	URN     resource.URN   // the URN of the resource.
+	Op      display.StepOp // the operation that failed.
+	Message string         // the error message of the failure.
 }
 
 type ResourceOperationFailedPayload struct {
@@ -144,6 +154,7 @@ type ResourceOutputsEventPayload struct {
 	Metadata StepEventMetadata
 	Planning bool
 	Debug    bool
+	Drift    *ResourceDrift // the drift detected by a refresh step, if any.
 }
 
 type ResourcePreEventPayload struct {
@@ -228,8 +239,9 @@ func makeEventEmitter(events chan<- Event, update UpdateInfo) (eventEmitter, err
 	go queueEvents(events, buffer, done)
 
 	return eventEmitter{
-		done: done,
-		ch:   buffer,
+		done:          done,
+		ch:            buffer,
+		configSecrets: secrets,
 	}, nil
 }
 
@@ -247,6 +259,11 @@ func makeQueryEventEmitter(events chan<- Event) (eventEmitter, error) {
 type eventEmitter struct {
 	done <-chan bool
 	ch   chan<- Event
+
+	// the plaintext values of the stack's secret config.
+	configSecrets []string
+	// the scanner used to check resource outputs for plaintext secrets, if secret scanning is enabled.
+	scanner *secretscan.Scanner
 }
 
 func queueEvents(events chan<- Event, buffer chan Event, done chan bool) {
@@ -372,7 +389,18 @@ func (e *eventEmitter) resourceOutputsEvent(op display.StepOp, step deploy.Step,
 		Metadata: makeStepEventMetadata(op, step, debug),
 		Planning: planning,
 		Debug:    debug,
+		Drift:    newRefreshDrift(op, step),
 	}))
+
+	// The payload masks secrets, so scan the raw outputs of the step for plaintext values that look like secrets.
+	if e.scanner != nil && step.New() != nil {
+		sink := newEventSink(*e, false)
+		for _, f := range e.scanner.ScanProperties(step.URN(), false, step.New().Outputs) {
+			sink.Warningf(diag.RawMessage(step.URN(), fmt.Sprintf(
+				"output %v looks like a secret (%s detector) but is not marked as secret; "+
+					"consider adding it to additionalSecretOutputs", f.Path, f.Detector)))
+		}
+	}
 }
 
 func (e *eventEmitter) resourcePreEvent(
@@ -405,7 +433,7 @@ func (e *eventEmitter) preludeEvent(isPreview bool, cfg config.Map) {
 }
 
 func (e *eventEmitter) summaryEvent(preview, maybeCorrupt bool, duration time.Duration,
-	resourceChanges display.ResourceChanges, policyPacks map[string]string,
+	resourceChanges display.ResourceChanges, policyPacks map[string]string, failures []ResourceFailure,
 ) {
 	contract.Requiref(e != nil, "e", "!= nil")
 
@@ -415,6 +443,7 @@ func (e *eventEmitter) summaryEvent(preview, maybeCorrupt bool, duration time.Du
 		Duration:        duration,
 		ResourceChanges: resourceChanges,
 		PolicyPacks:     policyPacks,
+		Failures:        failures,
 	}))
 }
 
@@ -15,8 +15,13 @@
 package engine
 
 import (
+	"fmt"
+
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
 )
@@ -51,3 +56,40 @@ func Import(u UpdateInfo, ctx *Context, opts UpdateOptions, imports []deploy.Imp
 		imports:       imports,
 	}, dryRun)
 }
+
+// ListResources lists the existing resources of the given type that match the given filters, so that they can be
+// chosen for import. The resources are listed by the default provider for the type's package, using the same plugin
+// version and configuration that an import of the resources would use.
+func ListResources(u UpdateInfo, opts UpdateOptions, d diag.Sink, typ tokens.Type,
+	filters map[string]string,
+) ([]plugin.ListedResource, error) {
+	contract.Requiref(u != nil, "u", "cannot be nil")
+
+	proj, target := u.GetProject(), u.GetTarget()
+	contract.Assertf(proj != nil, "update project cannot be nil")
+	contract.Assertf(target != nil, "update target cannot be nil")
+
+	if _, err := tokens.ParseTypeToken(typ.String()); err != nil {
+		return nil, fmt.Errorf("%q is not a valid resource type token. "+
+			"Type tokens must be of the format <package>:<module>:<type>", typ)
+	}
+
+	config, err := target.Config.Decrypt(target.Decrypter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt config: %w", err)
+	}
+
+	projinfo := &Projinfo{Proj: proj, Root: u.GetRoot()}
+	pwd, main, plugctx, err := ProjectInfoContext(projinfo, opts.Host, d, d, opts.DisableProviderPreview, nil, config)
+	if err != nil {
+		return nil, err
+	}
+	defer contract.IgnoreClose(plugctx)
+
+	_, defaultProviderInfo, err := installPlugins(proj, pwd, main, target, plugctx, false /*returnInstallErrors*/)
+	if err != nil {
+		return nil, err
+	}
+	info := defaultProviderInfo[typ.Package()]
+	return deploy.ListResources(plugctx, target, proj.Name, typ, info.Version, info.PluginDownloadURL, filters)
+}
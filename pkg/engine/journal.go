@@ -19,7 +19,10 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/display"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
@@ -42,6 +45,51 @@ type JournalEntry struct {
 
 type JournalEntries []JournalEntry
 
+// JournalStep stands in for a step that was recorded in a persisted journal. It carries enough information to replay
+// the journal with JournalEntries.Snap, but it can't be applied.
+type JournalStep struct {
+	op  display.StepOp
+	urn resource.URN
+	old *resource.State
+	new *resource.State
+}
+
+var _ = deploy.Step((*JournalStep)(nil))
+
+// NewJournalStep creates a step that replays an operation on the given states. Either old or new may be nil,
+// depending on the operation.
+func NewJournalStep(op display.StepOp, urn resource.URN, old, new *resource.State) *JournalStep {
+	return &JournalStep{op: op, urn: urn, old: old, new: new}
+}
+
+func (s *JournalStep) Apply(preview bool) (resource.Status, deploy.StepCompleteFunc, error) {
+	contract.Failf("journal steps can't be applied")
+	return resource.StatusOK, nil, nil
+}
+
+func (s *JournalStep) Op() display.StepOp             { return s.op }
+func (s *JournalStep) URN() resource.URN              { return s.urn }
+func (s *JournalStep) Type() tokens.Type              { return s.urn.Type() }
+func (s *JournalStep) Old() *resource.State           { return s.old }
+func (s *JournalStep) New() *resource.State           { return s.new }
+func (s *JournalStep) Logical() bool                  { return true }
+func (s *JournalStep) Deployment() *deploy.Deployment { return nil }
+func (s *JournalStep) Fail()                          {}
+
+func (s *JournalStep) Provider() string {
+	if res := s.Res(); res != nil {
+		return res.Provider
+	}
+	return ""
+}
+
+func (s *JournalStep) Res() *resource.State {
+	if s.new != nil {
+		return s.new
+	}
+	return s.old
+}
+
 func (entries JournalEntries) Snap(base *deploy.Snapshot) (*deploy.Snapshot, error) {
 	// Build up a list of current resources by replaying the journal.
 	resources, dones := []*resource.State{}, make(map[*resource.State]bool)
@@ -2,6 +2,7 @@ package lifecycletest
 
 import (
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/blang/semver"
@@ -11,8 +12,12 @@ import (
 	. "github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
@@ -884,3 +889,82 @@ func TestImportPlanSpecificProperties(t *testing.T) {
 	assert.Equal(t, resource.NewNumberProperty(2), snap.Resources[2].Outputs["baz"])
 	assert.NotContains(t, snap.Resources[2].Inputs, "baz")
 }
+
+func TestListResources(t *testing.T) {
+	t.Parallel()
+
+	var configured resource.PropertyMap
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				ConfigureF: func(news resource.PropertyMap) error {
+					configured = news
+					return nil
+				},
+				ListResourcesF: func(typ tokens.Type, filters map[string]string) ([]plugin.ListedResource, error) {
+					assert.Equal(t, tokens.Type("pkgA:m:typA"), typ)
+
+					var listed []plugin.ListedResource
+					for _, id := range []string{"id-1", "id-2"} {
+						if filters["id"] != "" && filters["id"] != id {
+							continue
+						}
+						listed = append(listed, plugin.ListedResource{
+							ID:   resource.ID(id),
+							Name: "res-" + id,
+							Properties: resource.PropertyMap{
+								"foo": resource.NewStringProperty("bar"),
+							},
+						})
+					}
+					return listed, nil
+				},
+			}, nil
+		}, deploytest.WithGrpc),
+		deploytest.NewProviderLoader("pkgB", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}, deploytest.WithGrpc),
+		deploytest.NewProviderLoader("pkgC", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			// Embedding the provider as a plugin.Provider hides its implementation of plugin.ResourceLister.
+			return struct{ plugin.Provider }{&deploytest.Provider{}}, nil
+		}),
+	}
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		return nil
+	})
+	host := deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	p := &TestPlan{
+		Options: UpdateOptions{Host: host},
+		Config: config.Map{
+			config.MustMakeKey("pkgA", "foo"): config.NewValue("baz"),
+		},
+	}
+	u := &updateInfo{project: p.GetProject(), target: p.GetTarget(t, nil)}
+	sink := diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never})
+
+	listed, err := ListResources(u, p.Options, sink, "pkgA:m:typA", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []plugin.ListedResource{
+		{ID: "id-1", Name: "res-id-1", Properties: resource.PropertyMap{"foo": resource.NewStringProperty("bar")}},
+		{ID: "id-2", Name: "res-id-2", Properties: resource.PropertyMap{"foo": resource.NewStringProperty("bar")}},
+	}, listed)
+	// The provider is configured from the stack's configuration.
+	assert.Equal(t, resource.NewStringProperty("baz"), configured["foo"])
+
+	listed, err = ListResources(u, p.Options, sink, "pkgA:m:typA", map[string]string{"id": "id-2"})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, resource.ID("id-2"), listed[0].ID)
+
+	// Providers that don't serve the discovery service can't list resources.
+	_, err = ListResources(u, p.Options, sink, "pkgB:m:typB", nil)
+	assert.ErrorContains(t, err, "the pkgB provider does not support listing resources")
+
+	// Neither can providers that don't implement plugin.ResourceLister.
+	_, err = ListResources(u, p.Options, sink, "pkgC:m:typC", nil)
+	assert.ErrorContains(t, err, "the pkgC provider does not support listing resources")
+
+	_, err = ListResources(u, p.Options, sink, "typA", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,308 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycletest
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	. "github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// wideGraph is a program that registers a wide graph of resources: a number of independent chains, each of which has
+// a single resource that is slow to delete, at a different depth in each chain.
+//
+// Scheduling the deletes of the graph level by level, where each level waits on the slowest delete of the previous
+// one, takes about the depth of the chains times the slow delay. Since the step executor begins each delete as soon
+// as the deletes of its own dependents complete, the chains proceed independently and deleting the graph takes about
+// the slow delay.
+type wideGraph struct {
+	width, depth int
+	p            *TestPlan
+	create       bool
+
+	// onDelete, if set, is called by the delete of each resource in the graph.
+	onDelete func(urn resource.URN)
+
+	eventsLock sync.Mutex
+	// events records the beginning and the end of each delete, in order.
+	events []wideGraphDelete
+}
+
+// wideGraphDelete records the beginning or the end of the delete of a resource in a wideGraph.
+type wideGraphDelete struct {
+	urn resource.URN
+	end bool
+}
+
+func newWideGraph(width, depth int, slowDelay time.Duration) *wideGraph {
+	g := &wideGraph{width: width, depth: depth}
+
+	slow := make(map[resource.URN]bool)
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					return resource.ID(urn.Name()), news, resource.StatusOK, nil
+				},
+				DeleteF: func(urn resource.URN, id resource.ID, olds resource.PropertyMap,
+					timeout float64,
+				) (resource.Status, error) {
+					g.record(urn, false)
+					if slow[urn] {
+						time.Sleep(slowDelay)
+					}
+					if g.onDelete != nil {
+						g.onDelete(urn)
+					}
+					g.record(urn, true)
+					return resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		if !g.create {
+			return nil
+		}
+		for i := 0; i < width; i++ {
+			var deps []resource.URN
+			for j := 0; j < depth; j++ {
+				urn, _, _, err := monitor.RegisterResource("pkgA:m:typA", fmt.Sprintf("res-%d-%d", i, j), true,
+					deploytest.ResourceOptions{Dependencies: deps})
+				if err != nil {
+					return err
+				}
+				if j == i%depth {
+					slow[urn] = true
+				}
+				deps = []resource.URN{urn}
+			}
+		}
+		return nil
+	})
+
+	g.p = &TestPlan{
+		Options: UpdateOptions{
+			Host:     deploytest.NewPluginHost(nil, nil, program, loaders...),
+			Parallel: math.MaxInt32,
+		},
+	}
+	return g
+}
+
+// urn returns the URN of the resource at the given depth of the given chain.
+func (g *wideGraph) urn(chain, depth int) resource.URN {
+	return g.p.NewURN("pkgA:m:typA", fmt.Sprintf("res-%d-%d", chain, depth), "")
+}
+
+func (g *wideGraph) record(urn resource.URN, end bool) {
+	g.eventsLock.Lock()
+	defer g.eventsLock.Unlock()
+	g.events = append(g.events, wideGraphDelete{urn: urn, end: end})
+}
+
+// update runs an update that creates the graph if create is true, and deletes it otherwise.
+func (g *wideGraph) update(tb testing.TB, snap *deploy.Snapshot, create bool) *deploy.Snapshot {
+	g.create = create
+	g.events = nil
+	project := g.p.GetProject()
+	stack, _, _ := g.p.getNames()
+	snap, res := TestOp(Update).Run(project, deploy.Target{Name: stack, Config: config.Map{}, Snapshot: snap},
+		g.p.Options, false, g.p.BackendClient, nil)
+	require.Nil(tb, res)
+	if create {
+		require.Len(tb, snap.Resources, g.width*g.depth+1)
+	} else {
+		require.Empty(tb, snap.Resources)
+	}
+	return snap
+}
+
+// BenchmarkDeleteWideGraph measures an update that deletes a wide graph of resources (see wideGraph).
+func BenchmarkDeleteWideGraph(b *testing.B) {
+	g := newWideGraph(16, 8, 20*time.Millisecond)
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		snap := g.update(b, nil, true)
+		b.StartTimer()
+
+		g.update(b, snap, false)
+	}
+}
+
+func TestDeleteWideGraph(t *testing.T) {
+	t.Parallel()
+
+	const width, depth = 4, 4
+	g := newWideGraph(width, depth, 0)
+	snap := g.update(t, nil, true)
+
+	// The delete of the leaf of the first chain waits for the delete of the root of the second chain to begin.
+	// Deleting the graph level by level would never begin to delete the root before the leaf was deleted.
+	rootDeleting := make(chan struct{})
+	g.onDelete = func(urn resource.URN) {
+		switch urn {
+		case g.urn(1, 0):
+			close(rootDeleting)
+		case g.urn(0, depth-1):
+			select {
+			case <-rootDeleting:
+			case <-time.After(30 * time.Second):
+				t.Errorf("%v was not deleted while %v was being deleted", g.urn(1, 0), urn)
+			}
+		}
+	}
+	g.update(t, snap, false)
+
+	began, ended := map[resource.URN]int{}, map[resource.URN]int{}
+	for i, e := range g.events {
+		if e.end {
+			ended[e.urn] = i
+		} else {
+			began[e.urn] = i
+		}
+	}
+	require.Len(t, began, width*depth)
+	require.Len(t, ended, width*depth)
+
+	// Independent deletes overlap...
+	assert.Less(t, began[g.urn(1, 0)], ended[g.urn(0, depth-1)])
+	// ...but each delete waits for the deletes of its dependents.
+	for i := 0; i < width; i++ {
+		for j := 0; j < depth-1; j++ {
+			assert.Less(t, ended[g.urn(i, j+1)], began[g.urn(i, j)],
+				"%v was deleted before its dependent %v", g.urn(i, j), g.urn(i, j+1))
+		}
+	}
+}
+
+func TestProviderParallelism(t *testing.T) {
+	t.Parallel()
+
+	// newLoader returns a provider loader that records the maximum number of its concurrent creates.
+	newLoader := func(maxConcurrent *int32) *deploytest.ProviderLoader {
+		var concurrent int32
+		return deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					n := atomic.AddInt32(&concurrent, 1)
+					defer atomic.AddInt32(&concurrent, -1)
+					for {
+						max := atomic.LoadInt32(maxConcurrent)
+						if n <= max || atomic.CompareAndSwapInt32(maxConcurrent, max, n) {
+							break
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+					return resource.ID(urn.Name()), news, resource.StatusOK, nil
+				},
+			}, nil
+		})
+	}
+
+	// registerResources concurrently registers a number of resources that use the given provider.
+	registerResources := func(monitor *deploytest.ResourceMonitor, provider string) error {
+		var g errgroup.Group
+		for i := 0; i < 8; i++ {
+			name := fmt.Sprintf("res%d", i)
+			g.Go(func() error {
+				_, _, _, err := monitor.RegisterResource("pkgA:m:typA", name, true,
+					deploytest.ResourceOptions{Provider: provider})
+				return err
+			})
+		}
+		return g.Wait()
+	}
+
+	t.Run("project options", func(t *testing.T) {
+		t.Parallel()
+
+		var maxConcurrent int32
+		program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+			return registerResources(monitor, "")
+		})
+
+		p := &TestPlan{
+			Options: UpdateOptions{
+				Host:     deploytest.NewPluginHost(nil, nil, program, newLoader(&maxConcurrent)),
+				Parallel: math.MaxInt32,
+			},
+		}
+		project := p.GetProject()
+		project.Options = &workspace.ProjectOptions{ProviderParallelism: map[string]int{"pkgA": 2}}
+
+		snap, res := TestOp(Update).Run(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil)
+		assert.Nil(t, res)
+		assert.Len(t, snap.Resources, 9)
+		assert.LessOrEqual(t, maxConcurrent, int32(2))
+	})
+
+	t.Run("provider resource", func(t *testing.T) {
+		t.Parallel()
+
+		var maxConcurrent int32
+		program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+			inputs := resource.PropertyMap{}
+			providers.SetProviderParallelism(inputs, 1)
+			provURN, provID, _, err := monitor.RegisterResource(providers.MakeProviderType("pkgA"), "provA", true,
+				deploytest.ResourceOptions{Inputs: inputs})
+			if err != nil {
+				return err
+			}
+			if provID == "" {
+				provID = providers.UnknownID
+			}
+			provRef, err := providers.NewReference(provURN, provID)
+			if err != nil {
+				return err
+			}
+			return registerResources(monitor, provRef.String())
+		})
+
+		p := &TestPlan{
+			Options: UpdateOptions{
+				Host:     deploytest.NewPluginHost(nil, nil, program, newLoader(&maxConcurrent)),
+				Parallel: math.MaxInt32,
+			},
+		}
+
+		snap, res := TestOp(Update).Run(p.GetProject(), p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil)
+		assert.Nil(t, res)
+		assert.Len(t, snap.Resources, 9)
+		assert.Equal(t, int32(1), maxConcurrent)
+	})
+}
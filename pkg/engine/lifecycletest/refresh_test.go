@@ -927,3 +927,74 @@ func TestRefreshUpdateWithDeletedResource(t *testing.T) {
 	snap := p.Run(t, old)
 	assert.Equal(t, 0, len(snap.Resources))
 }
+
+// TestRefreshDetectsSecretDrift validates that the drift reported by a refresh includes changes to secret outputs,
+// which are masked in the event metadata.
+func TestRefreshDetectsSecretDrift(t *testing.T) {
+	t.Parallel()
+
+	p := &TestPlan{}
+
+	resURN := p.NewURN("pkgA:m:typA", "resA", "")
+	recorded := resource.PropertyMap{
+		"password": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+		"username": resource.MakeSecret(resource.NewStringProperty("admin")),
+	}
+	live := resource.PropertyMap{
+		"password": resource.MakeSecret(resource.NewStringProperty("correct horse")),
+		"username": resource.MakeSecret(resource.NewStringProperty("admin")),
+	}
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				ReadF: func(
+					urn resource.URN, id resource.ID, inputs, state resource.PropertyMap,
+				) (plugin.ReadResult, resource.Status, error) {
+					return plugin.ReadResult{Outputs: live, Inputs: inputs}, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	program := deploytest.NewLanguageRuntime(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		return nil
+	})
+	p.Options.Host = deploytest.NewPluginHost(nil, nil, program, loaders...)
+
+	old := &deploy.Snapshot{
+		Resources: []*resource.State{
+			{
+				Type:    resURN.Type(),
+				URN:     resURN,
+				Custom:  true,
+				ID:      "myid",
+				Inputs:  resource.PropertyMap{},
+				Outputs: recorded,
+			},
+		},
+	}
+
+	p.Steps = []TestStep{{
+		Op: Refresh,
+		Validate: func(project workspace.Project, target deploy.Target, entries JournalEntries,
+			evts []Event, res result.Result,
+		) result.Result {
+			var report DriftReport
+			for _, e := range evts {
+				if e.Type == ResourceOutputsEvent {
+					report.RecordRefresh(e.Payload().(ResourceOutputsEventPayload))
+				}
+			}
+
+			if assert.Len(t, report.Resources, 1) {
+				assert.Equal(t, resURN, report.Resources[0].URN)
+				assert.Equal(t, []string{"password"}, report.Resources[0].Changed)
+				assert.Empty(t, report.Resources[0].Added)
+				assert.Empty(t, report.Resources[0].Removed)
+			}
+			return res
+		},
+	}}
+	p.Run(t, old)
+}
@@ -26,6 +26,7 @@ import (
 
 	resourceanalyzer "github.com/pulumi/pulumi/pkg/v3/resource/analyzer"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/secretscan"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/display"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -156,6 +157,14 @@ type UpdateOptions struct {
 
 	// Experimental is true if the engine is in experimental mode (i.e. PULUMI_EXPERIMENTAL was set)
 	Experimental bool
+
+	// ContinueOnError is true if the engine should keep executing the steps that don't depend on a failed step,
+	// rather than stopping at the first failure.
+	ContinueOnError bool
+
+	// ScanSecrets configures the checks of resource outputs for plaintext values that look like secrets. Outputs are
+	// not scanned if this is nil.
+	ScanSecrets *secretscan.Options
 }
 
 // HasChanges returns true if there are any non-same changes in the resulting summary.
@@ -446,6 +455,15 @@ func update(ctx *Context, info *deploymentContext, opts deploymentOptions,
 		}
 	}
 
+	// Check resource outputs for plaintext secrets if requested.
+	if opts.ScanSecrets != nil {
+		detectors, err := secretscan.NewDetectors(*opts.ScanSecrets, opts.Events.configSecrets)
+		if err != nil {
+			return nil, nil, result.FromError(err)
+		}
+		opts.Events.scanner = secretscan.NewScanner(detectors...)
+	}
+
 	// Create an appropriate set of event listeners.
 	var actions runActions
 	if preview {
@@ -500,6 +518,7 @@ type updateActions struct {
 	Opts    deploymentOptions
 
 	maybeCorrupt bool
+	failures     []ResourceFailure
 }
 
 func newUpdateActions(context *Context, u UpdateInfo, opts deploymentOptions) *updateActions {
@@ -559,6 +578,17 @@ func (acts *updateActions) OnResourceStepPost(
 		if reportStep {
 			acts.Opts.Events.resourceOperationFailedEvent(step, status, acts.Steps, acts.Opts.Debug)
 		}
+
+		// If the deployment goes on after this failure, remember it so that all failures are reported at the end.
+		if acts.Opts.ContinueOnError {
+			acts.MapLock.Lock()
+			acts.failures = append(acts.failures, ResourceFailure{
+				URN:     step.URN(),
+				Op:      step.Op(),
+				Message: logging.FilterString(err.Error()),
+			})
+			acts.MapLock.Unlock()
+		}
 	} else if reportStep {
 		op, record := step.Op(), step.Logical()
 		if acts.Opts.isRefresh && op == deploy.OpRefresh {
@@ -640,6 +670,12 @@ func (acts *updateActions) MaybeCorrupt() bool {
 	return acts.maybeCorrupt
 }
 
+func (acts *updateActions) Failures() []ResourceFailure {
+	acts.MapLock.Lock()
+	defer acts.MapLock.Unlock()
+	return acts.failures
+}
+
 func (acts *updateActions) Changes() display.ResourceChanges {
 	return display.ResourceChanges(acts.Ops)
 }
@@ -758,6 +794,10 @@ func (acts *previewActions) MaybeCorrupt() bool {
 	return false
 }
 
+func (acts *previewActions) Failures() []ResourceFailure {
+	return nil
+}
+
 func (acts *previewActions) Changes() display.ResourceChanges {
 	return display.ResourceChanges(acts.Ops)
 }
@@ -0,0 +1,71 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cytoscapeconv converts a resource graph into the JSON elements format of Cytoscape.js, which can also be
+// imported by the Cytoscape desktop application.  Please see https://js.cytoscape.org/#notation/elements-json for a
+// specification of the format.
+package cytoscapeconv
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/pulumi/pulumi/pkg/v3/graph"
+)
+
+type elements struct {
+	Nodes []element `json:"nodes"`
+	Edges []element `json:"edges"`
+}
+
+type element struct {
+	Data data `json:"data"`
+}
+
+// data holds the attributes of a node or an edge. URN is the URN of the resource that a node represents, if any.
+type data struct {
+	ID     string `json:"id"`
+	URN    string `json:"urn,omitempty"`
+	Source string `json:"source,omitempty"`
+	Target string `json:"target,omitempty"`
+	Label  string `json:"label,omitempty"`
+	Color  string `json:"color,omitempty"`
+}
+
+// Print prints a resource graph.
+func Print(g graph.Graph, w io.Writer) error {
+	vertices := graph.Vertices(g)
+	ids := graph.VertexIDs(vertices)
+
+	els := elements{Nodes: make([]element, len(vertices)), Edges: []element{}}
+	for i, v := range vertices {
+		els.Nodes[i] = element{Data: data{ID: ids[v], URN: string(graph.VertexURN(v)), Label: v.Label()}}
+		for _, out := range v.Outs() {
+			els.Edges = append(els.Edges, element{Data: data{
+				ID:     "Edge" + strconv.Itoa(len(els.Edges)),
+				Source: ids[v],
+				Target: ids[out.To()],
+				Label:  out.Label(),
+				Color:  out.Color(),
+			}})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(struct {
+		Elements elements `json:"elements"`
+	}{els})
+}
@@ -0,0 +1,91 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cytoscapeconv
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/graph/graphtest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestPrint(t *testing.T) {
+	t.Parallel()
+
+	g := &graphtest.Graph{}
+	a := g.AddVertex("a", &resource.State{URN: "urn:pulumi:stack::proj::test:index:Res::a"}, true)
+	b := g.AddVertex(`b "quoted"`, nil, false)
+	c := g.AddVertex("", nil, false)
+	g.AddEdge(a, b, "parent", "red")
+	g.AddEdge(a, c, "", "")
+	g.AddEdge(b, c, "", "")
+
+	var buf bytes.Buffer
+	require.NoError(t, Print(g, &buf))
+	assert.Equal(t, `{
+    "elements": {
+        "nodes": [
+            {
+                "data": {
+                    "id": "Resource0",
+                    "urn": "urn:pulumi:stack::proj::test:index:Res::a",
+                    "label": "a"
+                }
+            },
+            {
+                "data": {
+                    "id": "Resource1",
+                    "label": "b \"quoted\""
+                }
+            },
+            {
+                "data": {
+                    "id": "Resource2"
+                }
+            }
+        ],
+        "edges": [
+            {
+                "data": {
+                    "id": "Edge0",
+                    "source": "Resource0",
+                    "target": "Resource1",
+                    "label": "parent",
+                    "color": "red"
+                }
+            },
+            {
+                "data": {
+                    "id": "Edge1",
+                    "source": "Resource0",
+                    "target": "Resource2"
+                }
+            },
+            {
+                "data": {
+                    "id": "Edge2",
+                    "source": "Resource1",
+                    "target": "Resource2"
+                }
+            }
+        ]
+    }
+}
+`, buf.String())
+}
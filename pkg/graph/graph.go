@@ -24,6 +24,12 @@
 // and/or carry out deployment plans.  This package therefore also exposes operations necessary for diffing graphs.
 package graph
 
+import (
+	"strconv"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
 // Graph is an instance of a resource digraph.  Each is associated with a single program input, along
 // with a set of optional arguments used to evaluate it, along with the output DAG with node types and properties.
 type Graph interface {
@@ -46,3 +52,44 @@ type Edge interface {
 	From() Vertex      // the vertex this edge connects from.
 	Color() string     // an optional color for this edge, for when this graph is displayed.
 }
+
+// Vertices returns the vertices that are reachable from the roots of the given graph, in breadth-first order. Each
+// vertex is returned once, even if it is reachable from several roots.
+func Vertices(g Graph) []Vertex {
+	var vertices []Vertex
+	queued := make(map[Vertex]bool)
+	enqueue := func(v Vertex) {
+		if v != nil && !queued[v] {
+			queued[v] = true
+			vertices = append(vertices, v)
+		}
+	}
+	for _, root := range g.Roots() {
+		enqueue(root.To())
+	}
+	for i := 0; i < len(vertices); i++ {
+		for _, out := range vertices[i].Outs() {
+			enqueue(out.To())
+		}
+	}
+	return vertices
+}
+
+// VertexIDs assigns each of the given vertices a unique ID of the form "Resource<i>", where i is the index of the
+// vertex. These IDs are valid identifiers in every format that we print graphs in, unlike the URNs of resources.
+func VertexIDs(vertices []Vertex) map[Vertex]string {
+	ids := make(map[Vertex]string, len(vertices))
+	for i, v := range vertices {
+		ids[v] = "Resource" + strconv.Itoa(i)
+	}
+	return ids
+}
+
+// VertexURN returns the URN of the resource that the given vertex represents, or "" if the vertex's data isn't a
+// resource.
+func VertexURN(v Vertex) resource.URN {
+	if state, ok := v.Data().(*resource.State); ok && state != nil {
+		return state.URN
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graphmlconv converts a resource graph into its GraphML equivalent, which is supported by graph tools like
+// yEd and Gephi.  Please see http://graphml.graphdrawing.org/specification.html for a specification of the format.
+package graphmlconv
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+
+	"github.com/pulumi/pulumi/pkg/v3/graph"
+)
+
+type graphml struct {
+	XMLName xml.Name `xml:"graphml"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Keys    []key    `xml:"key"`
+	Graph   graphEl  `xml:"graph"`
+}
+
+type key struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphEl struct {
+	ID          string `xml:"id,attr"`
+	EdgeDefault string `xml:"edgedefault,attr"`
+	Nodes       []node `xml:"node"`
+	Edges       []edge `xml:"edge"`
+}
+
+type node struct {
+	ID   string  `xml:"id,attr"`
+	Data []datum `xml:"data"`
+}
+
+type edge struct {
+	ID     string  `xml:"id,attr"`
+	Source string  `xml:"source,attr"`
+	Target string  `xml:"target,attr"`
+	Data   []datum `xml:"data"`
+}
+
+type datum struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// The keys of the attributes of vertices and edges.
+const (
+	labelKey     = "label"
+	edgeLabelKey = "edgeLabel"
+	colorKey     = "color"
+)
+
+// Print prints a resource graph.
+func Print(g graph.Graph, w io.Writer) error {
+	vertices := graph.Vertices(g)
+	ids := graph.VertexIDs(vertices)
+
+	doc := graphml{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []key{
+			{ID: labelKey, For: "node", AttrName: "label", AttrType: "string"},
+			{ID: edgeLabelKey, For: "edge", AttrName: "label", AttrType: "string"},
+			{ID: colorKey, For: "edge", AttrName: "color", AttrType: "string"},
+		},
+		Graph: graphEl{ID: "G", EdgeDefault: "directed"},
+	}
+	for _, v := range vertices {
+		n := node{ID: ids[v]}
+		if label := v.Label(); label != "" {
+			n.Data = append(n.Data, datum{Key: labelKey, Value: label})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+
+		for _, out := range v.Outs() {
+			e := edge{
+				ID:     "Edge" + strconv.Itoa(len(doc.Graph.Edges)),
+				Source: ids[v],
+				Target: ids[out.To()],
+			}
+			if label := out.Label(); label != "" {
+				e.Data = append(e.Data, datum{Key: edgeLabelKey, Value: label})
+			}
+			if color := out.Color(); color != "" {
+				e.Data = append(e.Data, datum{Key: colorKey, Value: color})
+			}
+			doc.Graph.Edges = append(doc.Graph.Edges, e)
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "    ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
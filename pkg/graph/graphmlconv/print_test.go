@@ -0,0 +1,63 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphmlconv
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/graph/graphtest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestPrint(t *testing.T) {
+	t.Parallel()
+
+	g := &graphtest.Graph{}
+	a := g.AddVertex("a", &resource.State{URN: "urn:pulumi:stack::proj::test:index:Res::a"}, true)
+	b := g.AddVertex(`b "quoted"`, nil, false)
+	c := g.AddVertex("", nil, false)
+	g.AddEdge(a, b, "parent", "red")
+	g.AddEdge(a, c, "", "")
+	g.AddEdge(b, c, "", "")
+
+	var buf bytes.Buffer
+	require.NoError(t, Print(g, &buf))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+    <key id="label" for="node" attr.name="label" attr.type="string"></key>
+    <key id="edgeLabel" for="edge" attr.name="label" attr.type="string"></key>
+    <key id="color" for="edge" attr.name="color" attr.type="string"></key>
+    <graph id="G" edgedefault="directed">
+        <node id="Resource0">
+            <data key="label">a</data>
+        </node>
+        <node id="Resource1">
+            <data key="label">b &#34;quoted&#34;</data>
+        </node>
+        <node id="Resource2"></node>
+        <edge id="Edge0" source="Resource0" target="Resource1">
+            <data key="edgeLabel">parent</data>
+            <data key="color">red</data>
+        </edge>
+        <edge id="Edge1" source="Resource0" target="Resource2"></edge>
+        <edge id="Edge2" source="Resource1" target="Resource2"></edge>
+    </graph>
+</graphml>
+`, buf.String())
+}
@@ -0,0 +1,86 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graphtest provides a simple in-memory graph for testing code that consumes graphs.
+package graphtest
+
+import (
+	"github.com/pulumi/pulumi/pkg/v3/graph"
+)
+
+// Graph is a graph that is built by adding vertices and the edges between them.
+type Graph struct {
+	roots []graph.Edge
+}
+
+var _ graph.Graph = (*Graph)(nil)
+
+func (g *Graph) Roots() []graph.Edge {
+	return g.roots
+}
+
+// AddVertex adds a vertex with the given label and data to the graph. Root vertices are reachable from the roots of
+// the graph; all other vertices must be reachable from a root vertex through the graph's edges.
+func (g *Graph) AddVertex(label string, data interface{}, root bool) *Vertex {
+	v := &Vertex{label: label, data: data}
+	if root {
+		g.roots = append(g.roots, &Edge{to: v})
+	}
+	return v
+}
+
+// AddEdge adds an edge with the given label and color from one vertex to another.
+func (g *Graph) AddEdge(from, to *Vertex, label, color string) *Edge {
+	e := &Edge{from: from, to: to, label: label, color: color}
+	from.outs = append(from.outs, e)
+	to.ins = append(to.ins, e)
+	return e
+}
+
+// Vertex is a vertex of a Graph.
+type Vertex struct {
+	label string
+	data  interface{}
+	ins   []graph.Edge
+	outs  []graph.Edge
+}
+
+var _ graph.Vertex = (*Vertex)(nil)
+
+func (v *Vertex) Data() interface{}  { return v.data }
+func (v *Vertex) Label() string      { return v.label }
+func (v *Vertex) Ins() []graph.Edge  { return v.ins }
+func (v *Vertex) Outs() []graph.Edge { return v.outs }
+
+// Edge is an edge of a Graph. The edges from the roots of the graph have no source vertex.
+type Edge struct {
+	from  *Vertex
+	to    *Vertex
+	label string
+	color string
+}
+
+var _ graph.Edge = (*Edge)(nil)
+
+func (e *Edge) Data() interface{} { return nil }
+func (e *Edge) Label() string     { return e.label }
+func (e *Edge) To() graph.Vertex  { return e.to }
+func (e *Edge) Color() string     { return e.color }
+
+func (e *Edge) From() graph.Vertex {
+	if e.from == nil {
+		return nil
+	}
+	return e.from
+}
@@ -0,0 +1,67 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonconv converts a resource graph into a JSON adjacency list, in which each vertex lists the vertices that
+// its outgoing edges connect to.
+package jsonconv
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pulumi/pulumi/pkg/v3/graph"
+)
+
+type graphJSON struct {
+	Vertices []vertexJSON `json:"vertices"`
+}
+
+// vertexJSON is a vertex and its outgoing edges. URN is the URN of the resource that the vertex represents, if any.
+type vertexJSON struct {
+	ID    string     `json:"id"`
+	URN   string     `json:"urn,omitempty"`
+	Label string     `json:"label,omitempty"`
+	Outs  []edgeJSON `json:"outs"`
+}
+
+// edgeJSON is an outgoing edge of a vertex.
+type edgeJSON struct {
+	To    string `json:"to"`
+	Label string `json:"label,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+// Print prints a resource graph.
+func Print(g graph.Graph, w io.Writer) error {
+	vertices := graph.Vertices(g)
+	ids := graph.VertexIDs(vertices)
+
+	result := graphJSON{Vertices: make([]vertexJSON, len(vertices))}
+	for i, v := range vertices {
+		outs := make([]edgeJSON, len(v.Outs()))
+		for j, out := range v.Outs() {
+			outs[j] = edgeJSON{To: ids[out.To()], Label: out.Label(), Color: out.Color()}
+		}
+		result.Vertices[i] = vertexJSON{
+			ID:    ids[v],
+			URN:   string(graph.VertexURN(v)),
+			Label: v.Label(),
+			Outs:  outs,
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(result)
+}
@@ -0,0 +1,74 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mermaidconv converts a resource graph into a Mermaid flowchart, which can be rendered by Markdown viewers
+// that support Mermaid diagrams.  Please see https://mermaid.js.org/syntax/flowchart.html for a specification of the
+// flowchart syntax.
+package mermaidconv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/v3/graph"
+)
+
+// Print prints a resource graph.
+func Print(g graph.Graph, w io.Writer) error {
+	// As with DOT, write errors are latched by the buffered writer and returned when it is flushed.
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "flowchart TD")
+
+	vertices := graph.Vertices(g)
+	ids := graph.VertexIDs(vertices)
+
+	indent := "    "
+	for _, v := range vertices {
+		fmt.Fprintf(b, "%s%s", indent, ids[v])
+		if label := v.Label(); label != "" {
+			fmt.Fprintf(b, "[%s]", quote(label))
+		}
+		fmt.Fprintln(b)
+	}
+
+	// Mermaid styles edges by their index in the order in which they are declared.
+	var styles []string
+	edge := 0
+	for _, v := range vertices {
+		for _, out := range v.Outs() {
+			arrow := "-->"
+			if label := out.Label(); label != "" {
+				arrow += "|" + quote(label) + "|"
+			}
+			fmt.Fprintf(b, "%s%s %s %s\n", indent, ids[v], arrow, ids[out.To()])
+			if color := out.Color(); color != "" {
+				styles = append(styles, fmt.Sprintf("%slinkStyle %d stroke:%s", indent, edge, color))
+			}
+			edge++
+		}
+	}
+	for _, style := range styles {
+		fmt.Fprintln(b, style)
+	}
+
+	return b.Flush()
+}
+
+// quote returns the given text as a quoted Mermaid string. Mermaid strings can't contain double quotes, which are
+// written as entity codes instead.
+func quote(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, "#quot;") + `"`
+}
@@ -38,6 +38,18 @@ var Null = &model.Variable{
 
 // GenerateHCL2Definition generates a Pulumi HCL2 definition for a given resource.
 func GenerateHCL2Definition(loader schema.Loader, state *resource.State, names NameTable) (*model.Block, error) {
+	return generateHCL2Definition(loader, state, names, nil)
+}
+
+// referenceExprs maps the paths of input properties, as strings, to the expressions that refer to the values of other
+// resources that they hold.
+type referenceExprs map[string]model.Expression
+
+// generateHCL2Definition generates a Pulumi HCL2 definition for a given resource. Input properties with references
+// to other resources are generated as references to these resources instead of their values.
+func generateHCL2Definition(loader schema.Loader, state *resource.State, names NameTable,
+	refs []Reference,
+) (*model.Block, error) {
 	// TODO: pull the package version from the resource's provider
 	pkg, err := schema.LoadPackageReference(loader, string(state.Type.Package()), nil)
 	if err != nil {
@@ -52,9 +64,15 @@ func GenerateHCL2Definition(loader schema.Loader, state *resource.State, names N
 		return nil, fmt.Errorf("unknown resource type '%v'", r)
 	}
 
+	exprs := referenceExprs{}
+	for _, ref := range refs {
+		exprs[ref.Path.String()] = newReferenceExpression(ref.URN, ref.Property)
+	}
+
 	var items []model.BodyItem
 	for _, p := range r.InputProperties {
-		x, err := generatePropertyValue(p, state.Inputs[resource.PropertyKey(p.Name)])
+		x, err := generatePropertyValue(p, state.Inputs[resource.PropertyKey(p.Name)],
+			resource.PropertyPath{p.Name}, exprs)
 		if err != nil {
 			return nil, err
 		}
@@ -331,15 +349,15 @@ func zeroValue(t schema.Type) model.Expression {
 	}
 	switch t {
 	case schema.BoolType:
-		x, err := generateValue(t, resource.NewBoolProperty(false))
+		x, err := generateValue(t, resource.NewBoolProperty(false), nil, nil)
 		contract.IgnoreError(err)
 		return x
 	case schema.IntType, schema.NumberType:
-		x, err := generateValue(t, resource.NewNumberProperty(0))
+		x, err := generateValue(t, resource.NewNumberProperty(0), nil, nil)
 		contract.IgnoreError(err)
 		return x
 	case schema.StringType:
-		x, err := generateValue(t, resource.NewStringProperty(""))
+		x, err := generateValue(t, resource.NewStringProperty(""), nil, nil)
 		contract.IgnoreError(err)
 		return x
 	case schema.ArchiveType, schema.AssetType:
@@ -355,7 +373,9 @@ func zeroValue(t schema.Type) model.Expression {
 // generatePropertyValue generates the value for the given property. If the value is absent and the property is
 // required, a zero value for the property's type is generated. If the value is absent and the property is not
 // required, no value is generated (i.e. this function returns nil).
-func generatePropertyValue(property *schema.Property, value resource.PropertyValue) (model.Expression, error) {
+func generatePropertyValue(property *schema.Property, value resource.PropertyValue,
+	path resource.PropertyPath, refs referenceExprs,
+) (model.Expression, error) {
 	if !value.HasValue() {
 		if !property.IsRequired() {
 			return nil, nil
@@ -363,7 +383,7 @@ func generatePropertyValue(property *schema.Property, value resource.PropertyVal
 		return zeroValue(property.Type), nil
 	}
 
-	return generateValue(property.Type, value)
+	return generateValue(property.Type, value, path, refs)
 }
 
 // valueStructurallyTypedAs returns true if the given value is structurally typed as the given schema type.
@@ -574,8 +594,16 @@ func reduceUnionType(schemaUnion *schema.UnionType, value resource.PropertyValue
 }
 
 // generateValue generates a value from the given property value. The given type may or may not match the shape of the
-// given value.
-func generateValue(typ schema.Type, value resource.PropertyValue) (model.Expression, error) {
+// given value. If refs holds a reference for the value's path, the reference is generated instead.
+func generateValue(typ schema.Type, value resource.PropertyValue, path resource.PropertyPath,
+	refs referenceExprs,
+) (model.Expression, error) {
+	if len(refs) != 0 {
+		if x, ok := refs[path.String()]; ok {
+			return x, nil
+		}
+	}
+
 	typ = codegen.UnwrapType(typ)
 
 	if unionType, ok := typ.(*schema.UnionType); ok {
@@ -594,7 +622,7 @@ func generateValue(typ schema.Type, value resource.PropertyValue) (model.Express
 		arr := value.ArrayValue()
 		exprs := make([]model.Expression, len(arr))
 		for i, v := range arr {
-			x, err := generateValue(elementType, v)
+			x, err := generateValue(elementType, v, appendPath(path, i), refs)
 			if err != nil {
 				return nil, err
 			}
@@ -625,7 +653,7 @@ func generateValue(typ schema.Type, value resource.PropertyValue) (model.Express
 		switch arg := typ.(type) {
 		case *schema.ObjectType:
 			for _, p := range arg.Properties {
-				x, err := generatePropertyValue(p, obj[resource.PropertyKey(p.Name)])
+				x, err := generatePropertyValue(p, obj[resource.PropertyKey(p.Name)], appendPath(path, p.Name), refs)
 				if err != nil {
 					return nil, err
 				}
@@ -651,7 +679,7 @@ func generateValue(typ schema.Type, value resource.PropertyValue) (model.Express
 					continue
 				}
 
-				x, err := generateValue(elementType, obj[k])
+				x, err := generateValue(elementType, obj[k], appendPath(path, string(k)), refs)
 				if err != nil {
 					return nil, err
 				}
@@ -672,7 +700,7 @@ func generateValue(typ schema.Type, value resource.PropertyValue) (model.Express
 			Items:  items,
 		}, nil
 	case value.IsSecret():
-		arg, err := generateValue(typ, value.SecretValue().Element)
+		arg, err := generateValue(typ, value.SecretValue().Element, path, refs)
 		if err != nil {
 			return nil, err
 		}
@@ -60,13 +60,18 @@ func (e *DiagnosticsError) String() string {
 	return e.Error()
 }
 
-// GenerateLanguageDefintions generates a list of resource definitions from the given resource states.
+// GenerateLanguageDefintions generates a list of resource definitions from the given resource states. Input properties
+// whose values are the IDs or ARNs of other resources in the list are generated as references to these resources (see
+// InferReferences), and the definitions are ordered such that resources come after the resources they refer to.
 func GenerateLanguageDefinitions(w io.Writer, loader schema.Loader, gen LanguageGenerator, states []*resource.State,
 	names NameTable,
 ) error {
+	refs := InferReferences(states)
+	states = SortStates(states, refs)
+
 	var hcl2Text bytes.Buffer
 	for i, state := range states {
-		hcl2Def, err := GenerateHCL2Definition(loader, state, names)
+		hcl2Def, err := generateHCL2Definition(loader, state, names, refs[state.URN])
 		if err != nil {
 			return err
 		}
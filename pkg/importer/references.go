@@ -0,0 +1,238 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// A Reference is an input property of a resource whose value is the ID or an identifying output property of another
+// resource, e.g. a subnet's VPC ID.
+type Reference struct {
+	// Path is the path of the input property.
+	Path resource.PropertyPath
+	// URN is the URN of the resource that the input property refers to.
+	URN resource.URN
+	// Property is the name of the output property of the resource that the input property refers to. "id" refers to
+	// the resource's ID.
+	Property string
+}
+
+// A ReferenceTable maps URNs to the references of their resources' input properties to other resources.
+type ReferenceTable map[resource.URN][]Reference
+
+// identifyingOutputs are the output properties that, like IDs, conventionally identify the resources that they
+// belong to, and whose values other resources use to refer to them.
+var identifyingOutputs = []resource.PropertyKey{"arn", "selfLink"}
+
+// referenceTarget is an output property of a resource that input properties may refer to.
+type referenceTarget struct {
+	urn      resource.URN
+	property string
+}
+
+// InferReferences finds the input properties of the given resources whose values are the IDs or identifying output
+// properties (e.g. ARNs) of other resources in the list. Values that identify more than one resource are ignored, as
+// are references that would make resources depend on each other. References are only inferred to resources whose
+// names are valid identifiers, since the references to them are generated as variable references.
+func InferReferences(states []*resource.State) ReferenceTable {
+	targets := map[string]referenceTarget{}
+	ambiguous := map[string]bool{}
+	for _, state := range states {
+		if state.Delete || !hclsyntax.ValidIdentifier(string(state.URN.Name())) {
+			continue
+		}
+
+		values := map[string]string{}
+		if state.ID != "" {
+			values[string(state.ID)] = "id"
+		}
+		for _, key := range identifyingOutputs {
+			if v, ok := state.Outputs[key]; ok && v.IsString() && v.StringValue() != "" {
+				if _, has := values[v.StringValue()]; !has {
+					values[v.StringValue()] = string(key)
+				}
+			}
+		}
+		for value, property := range values {
+			if _, has := targets[value]; has {
+				ambiguous[value] = true
+				continue
+			}
+			targets[value] = referenceTarget{urn: state.URN, property: property}
+		}
+	}
+	for value := range ambiguous {
+		delete(targets, value)
+	}
+
+	graph := newDependencyGraph(states)
+	refs := ReferenceTable{}
+	for _, state := range states {
+		walkReferenceCandidates(nil, resource.NewObjectProperty(state.Inputs), func(path resource.PropertyPath, v string) {
+			target, ok := targets[v]
+			if !ok || target.urn == state.URN || graph.dependsOn(target.urn, state.URN) {
+				return
+			}
+			graph.addEdge(state.URN, target.urn)
+			refs[state.URN] = append(refs[state.URN], Reference{
+				Path:     path,
+				URN:      target.urn,
+				Property: target.property,
+			})
+		})
+	}
+	return refs
+}
+
+// walkReferenceCandidates calls visit for each string within the given value that may refer to another resource.
+// Secrets are not considered, so that their values don't end up in the generated code in plain text.
+func walkReferenceCandidates(path resource.PropertyPath, v resource.PropertyValue,
+	visit func(path resource.PropertyPath, v string),
+) {
+	switch {
+	case v.IsString():
+		if len(path) != 0 {
+			visit(path, v.StringValue())
+		}
+	case v.IsArray():
+		for i, item := range v.ArrayValue() {
+			walkReferenceCandidates(appendPath(path, i), item, visit)
+		}
+	case v.IsObject():
+		obj := v.ObjectValue()
+		for _, k := range obj.StableKeys() {
+			// Ignore internal properties.
+			if strings.HasPrefix(string(k), "__") {
+				continue
+			}
+			walkReferenceCandidates(appendPath(path, string(k)), obj[k], visit)
+		}
+	}
+}
+
+// appendPath returns a new path with the given key or index appended to the given path.
+func appendPath(path resource.PropertyPath, key interface{}) resource.PropertyPath {
+	return append(path[:len(path):len(path)], key)
+}
+
+// dependencyGraph tracks the dependencies of resources on their parents, providers, explicit dependencies and
+// inferred references.
+type dependencyGraph map[resource.URN][]resource.URN
+
+func newDependencyGraph(states []*resource.State) dependencyGraph {
+	g := dependencyGraph{}
+	for _, state := range states {
+		if state.Parent != "" {
+			g.addEdge(state.URN, state.Parent)
+		}
+		if state.Provider != "" {
+			if ref, err := providers.ParseReference(state.Provider); err == nil {
+				g.addEdge(state.URN, ref.URN())
+			}
+		}
+		for _, dep := range state.Dependencies {
+			g.addEdge(state.URN, dep)
+		}
+	}
+	return g
+}
+
+func (g dependencyGraph) addEdge(from, to resource.URN) {
+	g[from] = append(g[from], to)
+}
+
+// dependsOn returns true if the resource with the URN from transitively depends on the resource with the URN to.
+func (g dependencyGraph) dependsOn(from, to resource.URN) bool {
+	visited := map[resource.URN]bool{}
+	var visit func(urn resource.URN) bool
+	visit = func(urn resource.URN) bool {
+		if urn == to {
+			return true
+		}
+		if visited[urn] {
+			return false
+		}
+		visited[urn] = true
+		for _, dep := range g[urn] {
+			if visit(dep) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(from)
+}
+
+// SortStates orders the given resources such that each resource comes after its parent, its provider, its explicit
+// dependencies and the resources that it refers to, if they are in the list. Otherwise, resources keep their order.
+func SortStates(states []*resource.State, refs ReferenceTable) []*resource.State {
+	graph := newDependencyGraph(states)
+	for urn, urnRefs := range refs {
+		for _, ref := range urnRefs {
+			graph.addEdge(urn, ref.URN)
+		}
+	}
+
+	sorted := make([]*resource.State, 0, len(states))
+	byURN := make(map[resource.URN]*resource.State, len(states))
+	for _, state := range states {
+		byURN[state.URN] = state
+	}
+	done := map[resource.URN]bool{}
+	var visit func(state *resource.State)
+	visit = func(state *resource.State) {
+		if done[state.URN] {
+			return
+		}
+		// Mark the resource before visiting its dependencies so that cycles, which the engine doesn't allow, don't
+		// recurse forever.
+		done[state.URN] = true
+		for _, dep := range graph[state.URN] {
+			if s, ok := byURN[dep]; ok {
+				visit(s)
+			}
+		}
+		sorted = append(sorted, state)
+	}
+	for _, state := range states {
+		visit(state)
+	}
+	return sorted
+}
+
+// newReferenceExpression returns a reference to the given property of the resource with the given URN, e.g.
+// `vpc.id`.
+func newReferenceExpression(urn resource.URN, property string) model.Expression {
+	name := string(urn.Name())
+	return &model.ScopeTraversalExpression{
+		RootName: name,
+		Traversal: hcl.Traversal{
+			hcl.TraverseRoot{Name: name},
+			hcl.TraverseAttr{Name: property},
+		},
+		Parts: []model.Traversable{
+			&model.Variable{Name: name, VariableType: model.DynamicType},
+			model.DynamicType,
+		},
+	}
+}
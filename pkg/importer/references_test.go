@@ -0,0 +1,181 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"io"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/pcl"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/testing/utils"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func newImportedState(typ tokens.Type, name string, id resource.ID,
+	inputs, outputs resource.PropertyMap,
+) *resource.State {
+	urn := resource.NewURN("stack", "project", "", typ, tokens.QName(name))
+	return &resource.State{
+		Type:    typ,
+		URN:     urn,
+		Custom:  true,
+		ID:      id,
+		Inputs:  inputs,
+		Outputs: outputs,
+	}
+}
+
+// importedStates returns a subnet that is listed before its VPC, and a function that uses an IAM role.
+func importedStates() []*resource.State {
+	subnet := newImportedState("aws:ec2/subnet:Subnet", "subnet", "subnet-5678", resource.PropertyMap{
+		"vpcId":     resource.NewStringProperty("vpc-1234"),
+		"cidrBlock": resource.NewStringProperty("10.0.0.0/24"),
+		"tags": resource.NewObjectProperty(resource.PropertyMap{
+			"Name": resource.NewStringProperty("subnet"),
+		}),
+	}, nil)
+	vpc := newImportedState("aws:ec2/vpc:Vpc", "vpc", "vpc-1234", resource.PropertyMap{
+		"cidrBlock": resource.NewStringProperty("10.0.0.0/16"),
+		// References to the subnet would make the VPC and the subnet depend on each other.
+		"tags": resource.NewObjectProperty(resource.PropertyMap{
+			"Subnet": resource.NewStringProperty("subnet-5678"),
+		}),
+	}, nil)
+	role := newImportedState("aws:iam/role:Role", "role", "role", resource.PropertyMap{
+		"assumeRolePolicy": resource.NewStringProperty("{}"),
+	}, resource.PropertyMap{
+		"arn": resource.NewStringProperty("arn:aws:iam::123456789012:role/role"),
+	})
+	function := newImportedState("aws:lambda/function:Function", "function", "function", resource.PropertyMap{
+		"role":    resource.NewStringProperty("arn:aws:iam::123456789012:role/role"),
+		"runtime": resource.NewStringProperty("nodejs18.x"),
+		// Secrets are never replaced by references.
+		"environment": resource.NewObjectProperty(resource.PropertyMap{
+			"variables": resource.NewObjectProperty(resource.PropertyMap{
+				"VPC":    resource.NewStringProperty("vpc-1234"),
+				"SUBNET": resource.MakeSecret(resource.NewStringProperty("subnet-5678")),
+			}),
+		}),
+	}, nil)
+	return []*resource.State{subnet, vpc, role, function}
+}
+
+func TestInferReferences(t *testing.T) {
+	t.Parallel()
+
+	states := importedStates()
+	subnet, vpc, role, function := states[0], states[1], states[2], states[3]
+
+	refs := InferReferences(states)
+	assert.Equal(t, ReferenceTable{
+		subnet.URN: {
+			{Path: resource.PropertyPath{"vpcId"}, URN: vpc.URN, Property: "id"},
+		},
+		function.URN: {
+			{Path: resource.PropertyPath{"environment", "variables", "VPC"}, URN: vpc.URN, Property: "id"},
+			{Path: resource.PropertyPath{"role"}, URN: role.URN, Property: "arn"},
+		},
+	}, refs)
+
+	sorted := SortStates(states, refs)
+	assert.Equal(t, []*resource.State{vpc, subnet, role, function}, sorted)
+
+	// Values that identify more than one resource are ambiguous. Without the subnet's reference to the VPC, the VPC
+	// may refer to the subnet.
+	other := newImportedState("aws:ec2/vpc:Vpc", "other", "vpc-1234", nil, nil)
+	refs = InferReferences(append(states, other))
+	assert.Equal(t, ReferenceTable{
+		vpc.URN: {
+			{Path: resource.PropertyPath{"tags", "Subnet"}, URN: subnet.URN, Property: "id"},
+		},
+		function.URN: {
+			{Path: resource.PropertyPath{"role"}, URN: role.URN, Property: "arn"},
+		},
+	}, refs)
+}
+
+func TestGenerateLanguageDefinitionsWithReferences(t *testing.T) {
+	t.Parallel()
+	loader := schema.NewPluginLoader(utils.NewHost(testdataPath))
+
+	// A container that is listed before its image and network.
+	container := newImportedState("docker:index/container:Container", "container", "container-1234",
+		resource.PropertyMap{
+			"image": resource.NewStringProperty("sha256:5678"),
+			"networksAdvanced": resource.NewArrayProperty([]resource.PropertyValue{
+				resource.NewObjectProperty(resource.PropertyMap{"name": resource.NewStringProperty("network-1234")}),
+			}),
+			"hostname": resource.NewStringProperty("web"),
+		}, nil)
+	image := newImportedState("docker:index/remoteImage:RemoteImage", "image", "sha256:5678",
+		resource.PropertyMap{"name": resource.NewStringProperty("nginx")}, nil)
+	network := newImportedState("docker:index/network:Network", "network", "network-1234",
+		resource.PropertyMap{"name": resource.NewStringProperty("web")}, nil)
+
+	var program *pcl.Program
+	err := GenerateLanguageDefinitions(io.Discard, loader, func(_ io.Writer, p *pcl.Program) error {
+		program = p
+		return nil
+	}, []*resource.State{container, image, network}, NameTable{})
+	require.NoError(t, err)
+
+	resources := map[string]*pcl.Resource{}
+	var order []string
+	for _, n := range program.Nodes {
+		res, ok := n.(*pcl.Resource)
+		require.True(t, ok)
+		resources[res.Name()] = res
+		order = append(order, res.Name())
+	}
+	assert.Equal(t, []string{"image", "network", "container"}, order)
+
+	attribute := func(res *pcl.Resource, name string) model.Expression {
+		for _, item := range res.Inputs {
+			if item.Name == name {
+				return item.Value
+			}
+		}
+		return nil
+	}
+	assertReference := func(x model.Expression, root, property string) {
+		traversal, ok := x.(*model.ScopeTraversalExpression)
+		if !assert.True(t, ok, "expected a reference, got %v", x) {
+			return
+		}
+		assert.Equal(t, root, traversal.RootName)
+		require.Len(t, traversal.Traversal, 2)
+		attr, ok := traversal.Traversal[1].(hcl.TraverseAttr)
+		require.True(t, ok)
+		assert.Equal(t, property, attr.Name)
+	}
+	assertReference(attribute(resources["container"], "image"), "image", "id")
+	networks, ok := attribute(resources["container"], "networksAdvanced").(*model.TupleConsExpression)
+	require.True(t, ok)
+	require.Len(t, networks.Expressions, 1)
+	networkItem, ok := networks.Expressions[0].(*model.ObjectConsExpression)
+	require.True(t, ok)
+	require.Len(t, networkItem.Items, 1)
+	assertReference(networkItem.Items[0].Value, "network", "id")
+
+	_, isTemplate := attribute(resources["container"], "hostname").(*model.TemplateExpression)
+	assert.True(t, isTemplate)
+}
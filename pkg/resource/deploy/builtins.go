@@ -80,7 +80,7 @@ func (p *builtinProvider) Check(urn resource.URN, state, inputs resource.Propert
 
 	var name resource.PropertyValue
 	for k := range inputs {
-		if k != "name" {
+		if k != "name" && k != "validateOutputs" {
 			return nil, []plugin.CheckFailure{{Property: k, Reason: fmt.Sprintf("unknown property \"%v\"", k)}}, nil
 		}
 	}
@@ -92,6 +92,12 @@ func (p *builtinProvider) Check(urn resource.URN, state, inputs resource.Propert
 	if !name.IsString() && !name.IsComputed() {
 		return nil, []plugin.CheckFailure{{Property: "name", Reason: `property "name" must be a string`}}, nil
 	}
+	if validate, ok := inputs["validateOutputs"]; ok && !validate.IsBool() && !validate.IsComputed() {
+		return nil, []plugin.CheckFailure{{
+			Property: "validateOutputs",
+			Reason:   `property "validateOutputs" must be a boolean`,
+		}}, nil
+	}
 	return inputs, nil, nil
 }
 
@@ -256,11 +262,35 @@ func (p *builtinProvider) readStackReference(inputs resource.PropertyMap) (resou
 		return secretOutputs[i].String() < secretOutputs[j].String()
 	})
 
-	return resource.PropertyMap{
+	result := resource.PropertyMap{
 		"name":              name,
 		"outputs":           resource.NewObjectProperty(outputs),
 		"secretOutputNames": resource.NewArrayProperty(secretOutputs),
-	}, nil
+	}
+
+	// If requested, check the outputs against the types that the referenced stack declares for them, and return
+	// these types so that the program can check the outputs that it uses.
+	if validate, ok := inputs["validateOutputs"]; ok && validate.IsBool() && validate.BoolValue() {
+		schema, err := p.backendClient.GetStackOutputsSchema(p.context, name.StringValue())
+		if err != nil {
+			return nil, err
+		}
+		if schema == nil {
+			return nil, fmt.Errorf("stack %q does not publish an outputs schema to validate its outputs against",
+				name.StringValue())
+		}
+		if err := workspace.ValidateStackOutputs(schema, outputs); err != nil {
+			return nil, fmt.Errorf("the outputs of stack %q do not match its outputs schema: %w", name.StringValue(), err)
+		}
+		schemaValue, err := workspace.MarshalOutputTypes(schema)
+		if err != nil {
+			return nil, err
+		}
+		result["validateOutputs"] = validate
+		result["outputsSchema"] = schemaValue
+	}
+
+	return result, nil
 }
 
 func (p *builtinProvider) readStackResourceOutputs(inputs resource.PropertyMap) (resource.PropertyMap, error) {
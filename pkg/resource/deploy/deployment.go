@@ -48,6 +48,11 @@ type BackendClient interface {
 	// `Propertymap` with members `type` (containing the Pulumi type ID for the resource) and
 	// `outputs` (containing the resource outputs themselves).
 	GetStackResourceOutputs(ctx context.Context, stackName string) (resource.PropertyMap, error)
+
+	// GetStackOutputsSchema returns the types that the project of the named stack declared for the stack's outputs
+	// when it was last updated, or nil if it didn't declare any. The types are recorded in the manifest of the
+	// stack's checkpoint.
+	GetStackOutputsSchema(ctx context.Context, name string) (map[string]workspace.ProjectOutputType, error)
 }
 
 // Options controls the deployment process.
@@ -66,6 +71,11 @@ type Options struct {
 	DisableResourceReferences bool       // true to disable resource reference support.
 	DisableOutputValues       bool       // true to disable output value support.
 	GeneratePlan              bool       // true to enable plan generation.
+	ContinueOnError           bool       // true to keep executing steps that don't depend on a failed step.
+
+	// ProviderParallelism limits the number of concurrent resource operations performed by the providers of each
+	// package. Packages that are not present are only limited by Parallel.
+	ProviderParallelism map[tokens.Package]int
 }
 
 // DegreeOfParallelism returns the degree of parallelism that should be used during the
@@ -486,8 +496,10 @@ func (d *Deployment) Prev() *Snapshot                        { return d.prev }
 func (d *Deployment) Olds() map[resource.URN]*resource.State { return d.olds }
 func (d *Deployment) Source() Source                         { return d.source }
 
-func (d *Deployment) SameProvider(ref providers.Reference) {
+func (d *Deployment) SameProvider(ref providers.Reference, inputs resource.PropertyMap) error {
 	d.providers.Same(ref)
+	// The provider's concurrency limit is excluded from its diff, so it may have changed.
+	return d.providers.SetParallelism(ref, inputs)
 }
 
 func (d *Deployment) GetProvider(ref providers.Reference) (plugin.Provider, bool) {
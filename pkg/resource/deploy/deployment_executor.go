@@ -191,7 +191,7 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context, opts Options, p
 	ctx, cancel := context.WithCancel(callerCtx)
 
 	// Set up a step generator and executor for this deployment.
-	ex.stepExec = newStepExecutor(ctx, cancel, ex.deployment, opts, preview, false)
+	ex.stepExec = newStepExecutor(ctx, cancel, ex.deployment, opts, preview, opts.ContinueOnError)
 
 	// We iterate the source in its own goroutine because iteration is blocking and we want the main loop to be able to
 	// respond to cancellation requests promptly.
@@ -376,21 +376,14 @@ func (ex *deploymentExecutor) performDeletes(
 		return res
 	}
 
-	deletes := ex.stepGen.ScheduleDeletes(deleteSteps)
-
-	// ScheduleDeletes gives us a list of lists of steps. Each list of steps can safely be executed
-	// in parallel, but each list must execute completes before the next list can safely begin
-	// executing.
-	//
-	// This is not "true" delete parallelism, since there may be resources that could safely begin
-	// deleting but we won't until the previous set of deletes fully completes. This approximation
-	// is conservative, but correct.
-	for _, antichain := range deletes {
-		logging.V(4).Infof("deploymentExecutor.Execute(...): beginning delete antichain")
-		tok := ex.stepExec.ExecuteParallel(antichain)
-		tok.Wait(ctx)
-		logging.V(4).Infof("deploymentExecutor.Execute(...): antichain complete")
-	}
+	// ScheduleDeletes gives us a DAG of steps, in which the delete of each resource waits on the deletes of the
+	// resources that depend on it. The step executor begins each delete as soon as the deletes it waits on have
+	// completed, and skips the deletes that wait on a failed delete: the resources that a resource which could not be
+	// deleted depends on must outlive it.
+	logging.V(4).Infof("deploymentExecutor.Execute(...): beginning deletes")
+	tok := ex.stepExec.ExecuteDAG(ex.stepGen.ScheduleDeletes(deleteSteps))
+	tok.Wait(ctx)
+	logging.V(4).Infof("deploymentExecutor.Execute(...): deletes complete")
 
 	// After executing targeted deletes, we may now have resources that depend on the resource that
 	// were deleted.  Go through and clean things up accordingly for them.
@@ -406,8 +399,8 @@ func (ex *deploymentExecutor) performDeletes(
 	return nil
 }
 
-// handleSingleEvent handles a single source event. For all incoming events, it produces a chain that needs
-// to be executed and schedules the chain for execution.
+// handleSingleEvent handles a single source event. For all incoming events, it produces the steps that need
+// to be executed and schedules them for execution as a step DAG.
 func (ex *deploymentExecutor) handleSingleEvent(event SourceEvent) result.Result {
 	contract.Requiref(event != nil, "event", "must not be nil")
 
@@ -429,7 +422,7 @@ func (ex *deploymentExecutor) handleSingleEvent(event SourceEvent) result.Result
 		return res
 	}
 
-	ex.stepExec.ExecuteSerial(steps)
+	ex.stepExec.ExecuteDAG(ex.stepGen.ScheduleSteps(steps))
 	return nil
 }
 
@@ -18,12 +18,14 @@ import (
 	"context"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
 // BackendClient provides a simple implementation of deploy.BackendClient that defers to a function value.
 type BackendClient struct {
 	GetStackOutputsF         func(ctx context.Context, name string) (resource.PropertyMap, error)
 	GetStackResourceOutputsF func(ctx context.Context, name string) (resource.PropertyMap, error)
+	GetStackOutputsSchemaF   func(ctx context.Context, name string) (map[string]workspace.ProjectOutputType, error)
 }
 
 // GetStackOutputs returns the outputs (if any) for the named stack or an error if the stack cannot be found.
@@ -41,3 +43,13 @@ func (b *BackendClient) GetStackResourceOutputs(
 ) (resource.PropertyMap, error) {
 	return b.GetStackResourceOutputsF(ctx, name)
 }
+
+// GetStackOutputsSchema returns the declared types of the outputs of the named stack, if any.
+func (b *BackendClient) GetStackOutputsSchema(
+	ctx context.Context, name string,
+) (map[string]workspace.ProjectOutputType, error) {
+	if b.GetStackOutputsSchemaF == nil {
+		return nil, nil
+	}
+	return b.GetStackOutputsSchemaF(ctx, name)
+}
@@ -169,6 +169,9 @@ func wrapProviderWithGrpc(provider plugin.Provider) (plugin.Provider, io.Closer,
 		Cancel: wrapper.stop,
 		Init: func(srv *grpc.Server) error {
 			pulumirpc.RegisterResourceProviderServer(srv, plugin.NewProviderServer(provider))
+			if lister, ok := provider.(plugin.ResourceLister); ok {
+				pulumirpc.RegisterResourceDiscoveryServer(srv, plugin.NewResourceDiscoveryServer(lister))
+			}
 			return nil
 		},
 		Options: rpcutil.OpenTracingServerInterceptorOptions(nil),
@@ -187,7 +190,8 @@ func wrapProviderWithGrpc(provider plugin.Provider) (plugin.Provider, io.Closer,
 		contract.IgnoreClose(wrapper)
 		return nil, nil, fmt.Errorf("could not connect to resource provider service: %v", err)
 	}
-	wrapped := plugin.NewProviderWithClient(nil, provider.Pkg(), pulumirpc.NewResourceProviderClient(conn), false)
+	wrapped := plugin.NewProviderWithClients(nil, provider.Pkg(), pulumirpc.NewResourceProviderClient(conn),
+		pulumirpc.NewResourceDiscoveryClient(conn), false)
 	return wrapped, wrapper, nil
 }
 
@@ -68,6 +68,8 @@ type Provider struct {
 	CancelF func() error
 
 	GetMappingF func(key string) ([]byte, string, error)
+
+	ListResourcesF func(typ tokens.Type, filters map[string]string) ([]plugin.ListedResource, error)
 }
 
 func (prov *Provider) SignalCancellation() error {
@@ -241,3 +243,10 @@ func (prov *Provider) GetMapping(key string) ([]byte, string, error) {
 	}
 	return prov.GetMappingF(key)
 }
+
+func (prov *Provider) ListResources(typ tokens.Type, filters map[string]string) ([]plugin.ListedResource, error) {
+	if prov.ListResourcesF == nil {
+		return nil, plugin.ErrNotYetImplemented
+	}
+	return prov.ListResourcesF(typ, filters)
+}
@@ -102,6 +102,7 @@ type ResourceOptions struct {
 	Aliases                 []resource.Alias
 	ImportID                resource.ID
 	CustomTimeouts          *resource.CustomTimeouts
+	RetryPolicy             *resource.RetryPolicy
 	RetainOnDelete          bool
 	DeletedWith             resource.URN
 	SupportsPartialValues   *bool
@@ -188,6 +189,16 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 		}
 	}
 
+	var retry *pulumirpc.RegisterResourceRequest_RetryPolicy
+	if opts.RetryPolicy != nil {
+		retry = &pulumirpc.RegisterResourceRequest_RetryPolicy{
+			MaxAttempts:     int32(opts.RetryPolicy.MaxAttempts),
+			Backoff:         prepareTestTimeout(opts.RetryPolicy.Backoff),
+			MaxBackoff:      prepareTestTimeout(opts.RetryPolicy.MaxBackoff),
+			RetryableErrors: opts.RetryPolicy.RetryableErrors,
+		}
+	}
+
 	deleteBeforeReplace := false
 	if opts.DeleteBeforeReplace != nil {
 		deleteBeforeReplace = *opts.DeleteBeforeReplace
@@ -228,6 +239,7 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 		AdditionalSecretOutputs:    additionalSecretOutputs,
 		Aliases:                    aliasObjects,
 		DeletedWith:                string(opts.DeletedWith),
+		Retry:                      retry,
 	}
 
 	// submit request
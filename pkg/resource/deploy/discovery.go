@@ -0,0 +1,100 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// ListResources lists the existing resources of the given type that match the given filters, so that they can be
+// imported. The resources are listed by the default provider for the type's package, which is configured from the
+// target's configuration like the default providers of imports.
+func ListResources(plugctx *plugin.Context, target *Target, project tokens.PackageName, typ tokens.Type,
+	version *semver.Version, pluginDownloadURL string, filters map[string]string,
+) ([]plugin.ListedResource, error) {
+	contract.Requiref(plugctx != nil, "plugctx", "must not be nil")
+	contract.Requiref(target != nil, "target", "must not be nil")
+
+	if typ.Package() == "" {
+		return nil, errors.New("incorrect package type specified")
+	}
+
+	reg, err := providers.NewRegistry(plugctx.Host, nil, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch, prepare, and check the configuration for the default provider.
+	req := providers.NewProviderRequest(version, typ.Package(), pluginDownloadURL)
+	urn := resource.NewURN(target.Name.Q(), project, "", providers.MakeProviderType(req.Package()), req.Name())
+	inputs, err := target.GetPackageConfig(req.Package())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch provider config: %w", err)
+	}
+	if v := req.Version(); v != nil {
+		providers.SetProviderVersion(inputs, v)
+	}
+	if url := req.PluginDownloadURL(); url != "" {
+		providers.SetProviderURL(inputs, url)
+	}
+	inputs, failures, err := reg.Check(urn, nil, inputs, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate provider config: %w", err)
+	}
+	if len(failures) != 0 {
+		reasons := make([]string, len(failures))
+		for i, failure := range failures {
+			reasons[i] = failure.Reason
+			if failure.Property != "" {
+				reasons[i] = fmt.Sprintf("%v: %v", failure.Property, failure.Reason)
+			}
+		}
+		return nil, fmt.Errorf("invalid provider config: %v", strings.Join(reasons, "; "))
+	}
+
+	// Configure the provider and list the resources.
+	id, _, _, err := reg.Create(urn, inputs, 0, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure provider: %w", err)
+	}
+	ref, err := providers.NewReference(urn, id)
+	contract.AssertNoErrorf(err, "could not create provider reference with URN %q and ID %q", urn, id)
+	provider, ok := reg.GetProvider(ref)
+	contract.Assertf(ok, "provider %v was not registered", ref)
+
+	notSupported := fmt.Errorf("the %v provider does not support listing resources", req.Package())
+	lister, ok := provider.(plugin.ResourceLister)
+	if !ok {
+		return nil, notSupported
+	}
+	resources, err := lister.ListResources(typ, filters)
+	if err != nil {
+		if errors.Is(err, plugin.ErrNotYetImplemented) {
+			return nil, notSupported
+		}
+		return nil, fmt.Errorf("failed to list resources of type %v: %w", typ, err)
+	}
+	return resources, nil
+}
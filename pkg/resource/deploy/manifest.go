@@ -30,14 +30,18 @@ type Manifest struct {
 	Magic   string                 // a magic cookie.
 	Version string                 // the pulumi command version.
 	Plugins []workspace.PluginInfo // the plugin versions also loaded.
+
+	// OutputsSchema holds the types that the project declared for the stack's outputs, if any.
+	OutputsSchema map[string]workspace.ProjectOutputType
 }
 
 // Serialize turns a manifest into a data structure suitable for serialization.
 func (m Manifest) Serialize() apitype.ManifestV1 {
 	manifest := apitype.ManifestV1{
-		Time:    m.Time,
-		Magic:   m.Magic,
-		Version: m.Version,
+		Time:          m.Time,
+		Magic:         m.Magic,
+		Version:       m.Version,
+		OutputsSchema: m.OutputsSchema,
 	}
 	for _, plug := range m.Plugins {
 		var version string
@@ -57,9 +61,10 @@ func (m Manifest) Serialize() apitype.ManifestV1 {
 // DeserializeManifest deserializes a typed ManifestV1 into a `deploy.Manifest`.
 func DeserializeManifest(m apitype.ManifestV1) (*Manifest, error) {
 	manifest := Manifest{
-		Time:    m.Time,
-		Magic:   m.Magic,
-		Version: m.Version,
+		Time:          m.Time,
+		Magic:         m.Magic,
+		Version:       m.Version,
+		OutputsSchema: m.OutputsSchema,
 	}
 	for _, plug := range m.Plugins {
 		var version *semver.Version
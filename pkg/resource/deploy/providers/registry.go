@@ -36,6 +36,7 @@ import (
 const (
 	versionKey        resource.PropertyKey = "version"
 	pluginDownloadKey resource.PropertyKey = "pluginDownloadURL"
+	parallelismKey    resource.PropertyKey = "pluginParallelism"
 )
 
 // SetProviderURL sets the provider plugin download server URL in the given property map.
@@ -80,6 +81,41 @@ func GetProviderVersion(inputs resource.PropertyMap) (*semver.Version, error) {
 	return &sv, nil
 }
 
+// SetProviderParallelism sets the maximum number of concurrent resource operations for a provider in the given
+// property map.
+func SetProviderParallelism(inputs resource.PropertyMap, value int) {
+	inputs[parallelismKey] = resource.NewNumberProperty(float64(value))
+}
+
+// GetProviderParallelism fetches the maximum number of concurrent resource operations for a provider from the given
+// property map. If the limit is not set, this function returns 0.
+func GetProviderParallelism(inputs resource.PropertyMap) (int, error) {
+	parallelism, ok := inputs[parallelismKey]
+	if !ok {
+		return 0, nil
+	}
+	if !parallelism.IsNumber() {
+		return 0, fmt.Errorf("'%s' must be a number", parallelismKey)
+	}
+	n := parallelism.NumberValue()
+	if n < 1 || n != float64(int(n)) {
+		return 0, fmt.Errorf("'%s' must be a positive integer, got %v", parallelismKey, n)
+	}
+	return int(n), nil
+}
+
+// providerConfig returns the given provider inputs without the keys that are reserved for the engine. These keys are
+// never sent to the provider itself, so providers that reject unknown keys accept them and changing them never
+// produces a provider diff.
+func providerConfig(inputs resource.PropertyMap) resource.PropertyMap {
+	if _, ok := inputs[parallelismKey]; !ok {
+		return inputs
+	}
+	config := inputs.Copy()
+	delete(config, parallelismKey)
+	return config
+}
+
 // Registry manages the lifecylce of provider resources and their plugins and handles the resolution of provider
 // references to loaded plugins.
 //
@@ -95,9 +131,11 @@ type Registry struct {
 	host      plugin.Host
 	isPreview bool
 	providers map[Reference]plugin.Provider
-	builtins  plugin.Provider
-	aliases   map[resource.URN]resource.URN
-	m         sync.RWMutex
+	// parallelism records the maximum number of concurrent resource operations for the providers that are limited.
+	parallelism map[Reference]int
+	builtins    plugin.Provider
+	aliases     map[resource.URN]resource.URN
+	m           sync.RWMutex
 }
 
 var _ plugin.Provider = (*Registry)(nil)
@@ -218,11 +256,12 @@ func NewRegistry(host plugin.Host, prev []*resource.State, isPreview bool,
 	builtins plugin.Provider,
 ) (*Registry, error) {
 	r := &Registry{
-		host:      host,
-		isPreview: isPreview,
-		providers: make(map[Reference]plugin.Provider),
-		builtins:  builtins,
-		aliases:   make(map[resource.URN]resource.URN),
+		host:        host,
+		isPreview:   isPreview,
+		providers:   make(map[Reference]plugin.Provider),
+		parallelism: make(map[Reference]int),
+		builtins:    builtins,
+		aliases:     make(map[resource.URN]resource.URN),
 	}
 
 	for _, res := range prev {
@@ -254,6 +293,10 @@ func NewRegistry(host plugin.Host, prev []*resource.State, isPreview bool,
 		if err != nil {
 			return nil, fmt.Errorf("could not parse download URL for %v provider '%v': %v", providerPkg, urn, err)
 		}
+		parallelism, err := GetProviderParallelism(res.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse parallelism for %v provider '%v': %v", providerPkg, urn, err)
+		}
 		// TODO: We should thread checksums through here.
 		provider, err := loadProvider(providerPkg, version, downloadURL, nil, host, builtins)
 		if err != nil {
@@ -262,7 +305,7 @@ func NewRegistry(host plugin.Host, prev []*resource.State, isPreview bool,
 		if provider == nil {
 			return nil, fmt.Errorf("could not find plugin for %v provider '%v' at version %v", providerPkg, urn, version)
 		}
-		if err := provider.Configure(res.Inputs); err != nil {
+		if err := provider.Configure(providerConfig(res.Inputs)); err != nil {
 			closeErr := host.CloseProvider(provider)
 			contract.IgnoreError(closeErr)
 			return nil, fmt.Errorf("could not configure provider '%v': %v", urn, err)
@@ -270,6 +313,9 @@ func NewRegistry(host plugin.Host, prev []*resource.State, isPreview bool,
 
 		logging.V(7).Infof("loaded provider %v", ref)
 		r.providers[ref] = provider
+		if parallelism > 0 {
+			r.parallelism[ref] = parallelism
+		}
 	}
 
 	return r, nil
@@ -286,6 +332,40 @@ func (r *Registry) GetProvider(ref Reference) (plugin.Provider, bool) {
 	return provider, ok
 }
 
+// Parallelism returns the maximum number of concurrent resource operations for the provider that is currently
+// registered under the given reference, or 0 if its operations are not limited.
+func (r *Registry) Parallelism(ref Reference) int {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	return r.parallelism[ref]
+}
+
+// SetParallelism records the maximum number of concurrent resource operations for the provider registered under the
+// given reference, as configured by the given provider inputs.
+func (r *Registry) SetParallelism(ref Reference, inputs resource.PropertyMap) error {
+	parallelism, err := GetProviderParallelism(inputs)
+	if err != nil {
+		return err
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	refs := []Reference{ref}
+	if alias, ok := r.aliases[ref.URN()]; ok {
+		refs = append(refs, mustNewReference(alias, ref.ID()))
+	}
+	for _, ref := range refs {
+		if parallelism > 0 {
+			r.parallelism[ref] = parallelism
+		} else {
+			delete(r.parallelism, ref)
+		}
+	}
+	return nil
+}
+
 func (r *Registry) setProvider(ref Reference, provider plugin.Provider) {
 	r.m.Lock()
 	defer r.m.Unlock()
@@ -308,6 +388,7 @@ func (r *Registry) deleteProvider(ref Reference) (plugin.Provider, bool) {
 		return nil, false
 	}
 	delete(r.providers, ref)
+	delete(r.parallelism, ref)
 	return provider, true
 }
 
@@ -384,6 +465,9 @@ func (r *Registry) Check(urn resource.URN, olds, news resource.PropertyMap,
 	if err != nil {
 		return nil, []plugin.CheckFailure{{Property: "pluginDownloadURL", Reason: err.Error()}}, nil
 	}
+	if _, err := GetProviderParallelism(news); err != nil {
+		return nil, []plugin.CheckFailure{{Property: "pluginParallelism", Reason: err.Error()}}, nil
+	}
 	// TODO: We should thread checksums through here.
 	provider, err := loadProvider(GetProviderPackage(urn.Type()), version, downloadURL, nil, r.host, r.builtins)
 	if err != nil {
@@ -394,13 +478,19 @@ func (r *Registry) Check(urn resource.URN, olds, news resource.PropertyMap,
 	}
 
 	// Check the provider's config. If the check fails, unload the provider.
-	inputs, failures, err := provider.CheckConfig(urn, olds, news, allowUnknowns)
+	inputs, failures, err := provider.CheckConfig(urn, providerConfig(olds), providerConfig(news), allowUnknowns)
 	if len(failures) != 0 || err != nil {
 		closeErr := r.host.CloseProvider(provider)
 		contract.IgnoreError(closeErr)
 		return nil, failures, err
 	}
 
+	// Restore the reserved keys, which are recorded in the provider's inputs.
+	if parallelism, ok := news[parallelismKey]; ok {
+		inputs = inputs.Copy()
+		inputs[parallelismKey] = parallelism
+	}
+
 	// Create a provider reference using the URN and the unknown ID and register the provider.
 	r.setProvider(mustNewReference(urn, UnknownID), provider)
 
@@ -435,14 +525,15 @@ func (r *Registry) Diff(urn resource.URN, id resource.ID, olds, news resource.Pr
 		provider, ok = r.GetProvider(mustNewReference(urn, id))
 		contract.Assertf(ok, "Provider must have been registered by NewRegistry for DBR Diff (%v::%v)", urn, id)
 
-		diff, err := provider.DiffConfig(urn, olds, news, allowUnknowns, ignoreChanges)
+		diff, err := provider.DiffConfig(urn, providerConfig(olds), providerConfig(news), allowUnknowns, ignoreChanges)
 		if err != nil {
 			return plugin.DiffResult{Changes: plugin.DiffUnknown}, err
 		}
 		return diff, nil
 	}
 
-	// Diff the properties.
+	// Diff the properties. The reserved keys are excluded, as they only affect the engine.
+	olds, news = providerConfig(olds), providerConfig(news)
 	diff, err := provider.DiffConfig(urn, olds, news, allowUnknowns, ignoreChanges)
 	if err != nil {
 		return plugin.DiffResult{Changes: plugin.DiffUnknown}, err
@@ -479,6 +570,9 @@ func (r *Registry) Same(ref Reference) {
 	if alias, ok := r.aliases[ref.URN()]; ok {
 		aliasRef := mustNewReference(alias, ref.ID())
 		r.providers[ref] = r.providers[aliasRef]
+		if parallelism, ok := r.parallelism[aliasRef]; ok {
+			r.parallelism[ref] = parallelism
+		}
 	}
 }
 
@@ -496,7 +590,7 @@ func (r *Registry) Create(urn resource.URN, news resource.PropertyMap, timeout f
 	provider, ok := r.GetProvider(mustNewReference(urn, UnknownID))
 	contract.Assertf(ok, "'Check' must be called before 'Create' (%v)", urn)
 
-	if err := provider.Configure(news); err != nil {
+	if err := provider.Configure(providerConfig(news)); err != nil {
 		return "", nil, resource.StatusOK, err
 	}
 
@@ -511,7 +605,11 @@ func (r *Registry) Create(urn resource.URN, news resource.PropertyMap, timeout f
 		contract.Assertf(id != UnknownID, "resource ID must not be unknown")
 	}
 
-	r.setProvider(mustNewReference(urn, id), provider)
+	ref := mustNewReference(urn, id)
+	if err := r.SetParallelism(ref, news); err != nil {
+		return "", nil, resource.StatusOK, err
+	}
+	r.setProvider(ref, provider)
 	return id, news, resource.StatusOK, nil
 }
 
@@ -529,12 +627,16 @@ func (r *Registry) Update(urn resource.URN, id resource.ID, olds, news resource.
 	provider, ok := r.GetProvider(mustNewReference(urn, UnknownID))
 	contract.Assertf(ok, "'Check' and 'Diff' must be called before 'Update' (%v)", urn)
 
-	if err := provider.Configure(news); err != nil {
+	if err := provider.Configure(providerConfig(news)); err != nil {
 		return nil, resource.StatusUnknown, err
 	}
 
 	// Publish the configured provider.
-	r.setProvider(mustNewReference(urn, id), provider)
+	ref := mustNewReference(urn, id)
+	if err := r.SetParallelism(ref, news); err != nil {
+		return nil, resource.StatusUnknown, err
+	}
+	r.setProvider(ref, provider)
 	return news, resource.StatusOK, nil
 }
 
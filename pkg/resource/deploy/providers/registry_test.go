@@ -612,6 +612,120 @@ func TestCRUD(t *testing.T) {
 	}
 }
 
+func TestProviderParallelism(t *testing.T) {
+	t.Parallel()
+
+	oldInputs := resource.PropertyMap{}
+	SetProviderParallelism(oldInputs, 2)
+	olds := []*resource.State{
+		newProviderState("pkgA", "a", "id1", false, oldInputs),
+		newProviderState("pkgB", "a", "id1", false, nil),
+	}
+	loaders := []*providerLoader{
+		newSimpleLoader(t, "pkgA", "", nil),
+		newSimpleLoader(t, "pkgB", "", nil),
+	}
+	host := newPluginHost(t, loaders)
+
+	r, err := NewRegistry(host, olds, false, nil)
+	assert.NoError(t, err)
+
+	// The limits of old providers are loaded from their inputs.
+	assert.Equal(t, 2, r.Parallelism(Reference{urn: olds[0].URN, id: olds[0].ID}))
+	assert.Equal(t, 0, r.Parallelism(Reference{urn: olds[1].URN, id: olds[1].ID}))
+
+	// The limits of new providers are recorded when they are created.
+	urn := resource.NewURN("test", "test", "", MakeProviderType("pkgB"), "b")
+	news := resource.PropertyMap{}
+	SetProviderParallelism(news, 4)
+	inputs, failures, err := r.Check(urn, resource.PropertyMap{}, news, false, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+	id, _, _, err := r.Create(urn, inputs, 120, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, r.Parallelism(Reference{urn: urn, id: id}))
+
+	// An update can lift the limit.
+	_, _, err = r.Check(urn, inputs, resource.PropertyMap{}, false, nil)
+	assert.NoError(t, err)
+	_, _, err = r.Update(urn, id, inputs, resource.PropertyMap{}, 120, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, r.Parallelism(Reference{urn: urn, id: id}))
+
+	// Invalid limits are reported as check failures.
+	for _, invalid := range []resource.PropertyValue{
+		resource.NewNumberProperty(0),
+		resource.NewNumberProperty(1.5),
+		resource.NewStringProperty("2"),
+	} {
+		_, failures, err = r.Check(urn, resource.PropertyMap{}, resource.PropertyMap{"pluginParallelism": invalid},
+			false, nil)
+		assert.NoError(t, err)
+		assert.Len(t, failures, 1)
+	}
+}
+
+func TestProviderParallelismIsReserved(t *testing.T) {
+	t.Parallel()
+
+	assertReserved := func(inputs resource.PropertyMap) {
+		assert.NotContains(t, inputs, resource.PropertyKey("pluginParallelism"))
+	}
+	loaders := []*providerLoader{
+		newLoader(t, "pkgA", "", func(pkg tokens.Package, ver semver.Version) (plugin.Provider, error) {
+			return &testProvider{
+				pkg:     pkg,
+				version: ver,
+				checkConfig: func(urn resource.URN, olds,
+					news resource.PropertyMap, allowUnknowns bool,
+				) (resource.PropertyMap, []plugin.CheckFailure, error) {
+					assertReserved(olds)
+					assertReserved(news)
+					return news, nil, nil
+				},
+				diffConfig: func(urn resource.URN, olds, news resource.PropertyMap,
+					allowUnknowns bool, ignoreChanges []string,
+				) (plugin.DiffResult, error) {
+					assertReserved(olds)
+					assertReserved(news)
+					return plugin.DiffResult{}, nil
+				},
+				config: func(inputs resource.PropertyMap) error {
+					assertReserved(inputs)
+					return nil
+				},
+			}, nil
+		}),
+	}
+	host := newPluginHost(t, loaders)
+
+	oldInputs := resource.PropertyMap{"foo": resource.NewStringProperty("bar")}
+	SetProviderParallelism(oldInputs, 2)
+	olds := []*resource.State{newProviderState("pkgA", "a", "id1", false, oldInputs)}
+	r, err := NewRegistry(host, olds, false, nil)
+	assert.NoError(t, err)
+
+	// The limit is recorded in the checked inputs, but changing it doesn't produce a diff.
+	urn, id := olds[0].URN, olds[0].ID
+	news := resource.PropertyMap{"foo": resource.NewStringProperty("bar")}
+	SetProviderParallelism(news, 4)
+	inputs, failures, err := r.Check(urn, oldInputs, news, false, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, failures)
+	assert.Equal(t, news, inputs)
+
+	diff, err := r.Diff(urn, id, oldInputs, inputs, false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, plugin.DiffNone, diff.Changes)
+
+	// The new limit takes effect when the provider is updated or left unchanged.
+	assert.NoError(t, r.SetParallelism(Reference{urn: urn, id: id}, inputs))
+	assert.Equal(t, 4, r.Parallelism(Reference{urn: urn, id: id}))
+	_, _, err = r.Update(urn, id, oldInputs, inputs, 120, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, r.Parallelism(Reference{urn: urn, id: id}))
+}
+
 func TestCRUDPreview(t *testing.T) {
 	t.Parallel()
 
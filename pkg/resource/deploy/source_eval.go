@@ -388,6 +388,9 @@ func (d *defaultProviders) handleRequest(req providers.ProviderRequest) (provide
 	case <-d.cancel:
 		return providers.Reference{}, context.Canceled
 	}
+	if result.Failed {
+		return providers.Reference{}, fmt.Errorf("registering the default provider for package %s failed", req)
+	}
 
 	logging.V(5).Infof("registered default provider for package %s: %s", req, result.State.URN)
 
@@ -496,6 +499,9 @@ type resmon struct {
 	done                      <-chan error                       // a channel that resolves when the server completes.
 	disableResourceReferences bool                               // true if resource references are disabled.
 	disableOutputValues       bool                               // true if output values are disabled.
+
+	// outputsSchema are the types that the project declares for the stack's outputs, if any.
+	outputsSchema map[string]workspace.ProjectOutputType
 }
 
 var _ SourceResourceMonitor = (*resmon)(nil)
@@ -530,6 +536,7 @@ func newResourceMonitor(src *evalSource, provs ProviderSource, regChan chan *reg
 		cancel:                    cancel,
 		disableResourceReferences: opts.DisableResourceReferences,
 		disableOutputValues:       opts.DisableOutputValues,
+		outputsSchema:             src.runinfo.Proj.Outputs,
 	}
 
 	// Fire up a gRPC server and start listening for incomings.
@@ -977,6 +984,9 @@ func (rm *resmon) ReadResource(ctx context.Context,
 	}
 
 	contract.Assertf(result != nil, "ReadResource operation returned a nil result")
+	if result.Failed {
+		return nil, fmt.Errorf("reading resource %s of type %s failed", name, t)
+	}
 	marshaled, err := plugin.MarshalProperties(result.State.Outputs, plugin.MarshalOptions{
 		Label:         label,
 		KeepUnknowns:  true,
@@ -1257,11 +1267,18 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 			}
 		}
 
+		retryPolicy, err := generateRetryPolicy(req.GetRetry())
+		if err != nil {
+			return nil, err
+		}
+
 		// Send the goal state to the engine.
+		goal := resource.NewGoal(t, name, custom, props, parent, protect, dependencies,
+			providerRef.String(), nil, propertyDependencies, deleteBeforeReplace, ignoreChanges,
+			additionalSecretKeys, aliases, id, &timeouts, replaceOnChanges, retainOnDelete, deletedWith)
+		goal.RetryPolicy = retryPolicy
 		step := &registerResourceEvent{
-			goal: resource.NewGoal(t, name, custom, props, parent, protect, dependencies,
-				providerRef.String(), nil, propertyDependencies, deleteBeforeReplace, ignoreChanges,
-				additionalSecretKeys, aliases, id, &timeouts, replaceOnChanges, retainOnDelete, deletedWith),
+			goal: goal,
 			done: make(chan *RegisterResult),
 		}
 
@@ -1279,6 +1296,9 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 			logging.V(5).Infof("ResourceMonitor.RegisterResource operation canceled, name=%s", name)
 			return nil, rpcerror.New(codes.Unavailable, "resource monitor shut down while waiting on step's done channel")
 		}
+		if result.Failed {
+			return nil, fmt.Errorf("registering resource %s of type %s failed", name, t)
+		}
 	}
 
 	if !custom && result != nil && result.State != nil && result.State.URN != "" {
@@ -1411,6 +1431,13 @@ func (rm *resmon) RegisterResourceOutputs(ctx context.Context,
 	}
 	logging.V(5).Infof("ResourceMonitor.RegisterResourceOutputs received: urn=%v, #outs=%v", urn, len(outs))
 
+	if urn.Type() == resource.RootStackType && len(rm.outputsSchema) > 0 {
+		if err := workspace.ValidateStackOutputs(rm.outputsSchema, outs); err != nil {
+			return nil, rpcerror.New(codes.InvalidArgument,
+				fmt.Sprintf("the stack's outputs do not match the outputs schema of the project: %v", err))
+		}
+	}
+
 	// Now send the step over to the engine to perform.
 	step := &registerResourceOutputsEvent{
 		urn:     urn,
@@ -1519,6 +1546,39 @@ func generateTimeoutInSeconds(timeout string) (float64, error) {
 	return duration.Seconds(), nil
 }
 
+// generateRetryPolicy validates the retry policy of a resource registration and converts it to its engine form.
+func generateRetryPolicy(retry *pulumirpc.RegisterResourceRequest_RetryPolicy) (*resource.RetryPolicy, error) {
+	if retry == nil || retry.MaxAttempts == 0 {
+		return nil, nil
+	}
+	if retry.MaxAttempts < 0 {
+		return nil, fmt.Errorf("invalid retry policy: maxAttempts must not be negative, got %d", retry.MaxAttempts)
+	}
+
+	policy := &resource.RetryPolicy{
+		MaxAttempts:     int(retry.MaxAttempts),
+		RetryableErrors: retry.RetryableErrors,
+	}
+	if retry.Backoff != "" {
+		duration, err := time.ParseDuration(retry.Backoff)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse retry backoff value %s", retry.Backoff)
+		}
+		policy.Backoff = duration.Seconds()
+	}
+	if retry.MaxBackoff != "" {
+		duration, err := time.ParseDuration(retry.MaxBackoff)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse retry maxBackoff value %s", retry.MaxBackoff)
+		}
+		policy.MaxBackoff = duration.Seconds()
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
 func decorateResourceSpans(span opentracing.Span, method string, req, resp interface{}, grpcError error) {
 	if req == nil {
 		return
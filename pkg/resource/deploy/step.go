@@ -54,6 +54,10 @@ type Step interface {
 	Res() *resource.State    // the latest state for the resource that is known (worst case, old).
 	Logical() bool           // true if this step represents a logical operation in the program.
 	Deployment() *Deployment // the owning deployment.
+
+	// Fail signals the failure of this step, or of a step before it in its chain, to the program that registered
+	// the resource, if any. It must only be called if the StepCompleteFunc returned by Apply was not called.
+	Fail()
 }
 
 // SameStep is a mutating step that does nothing.
@@ -125,6 +129,7 @@ func (s *SameStep) Old() *resource.State    { return s.old }
 func (s *SameStep) New() *resource.State    { return s.new }
 func (s *SameStep) Res() *resource.State    { return s.new }
 func (s *SameStep) Logical() bool           { return true }
+func (s *SameStep) Fail()                   { s.reg.Done(&RegisterResult{Failed: true}) }
 
 func (s *SameStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
 	// Retain the ID and outputs
@@ -139,7 +144,9 @@ func (s *SameStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error
 				fmt.Errorf("bad provider reference '%v' for resource %v: %v", s.Provider(), s.URN(), err)
 		}
 		if s.Deployment() != nil {
-			s.Deployment().SameProvider(ref)
+			if err := s.Deployment().SameProvider(ref, s.new.Inputs); err != nil {
+				return resource.StatusOK, nil, err
+			}
 		}
 	}
 
@@ -232,6 +239,7 @@ func (s *CreateStep) Keys() []resource.PropertyKey                 { return s.ke
 func (s *CreateStep) Diffs() []resource.PropertyKey                { return s.diffs }
 func (s *CreateStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
 func (s *CreateStep) Logical() bool                                { return !s.replacing }
+func (s *CreateStep) Fail()                                        { s.reg.Done(&RegisterResult{Failed: true}) }
 
 func (s *CreateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
 	var resourceError error
@@ -368,6 +376,7 @@ func (s *DeleteStep) Old() *resource.State    { return s.old }
 func (s *DeleteStep) New() *resource.State    { return nil }
 func (s *DeleteStep) Res() *resource.State    { return s.old }
 func (s *DeleteStep) Logical() bool           { return !s.replacing }
+func (s *DeleteStep) Fail()                   {}
 
 func isDeletedWith(with resource.URN, otherDeletions map[resource.URN]bool) bool {
 	if with == "" {
@@ -449,6 +458,7 @@ func (s *RemovePendingReplaceStep) Old() *resource.State    { return s.old }
 func (s *RemovePendingReplaceStep) New() *resource.State    { return nil }
 func (s *RemovePendingReplaceStep) Res() *resource.State    { return s.old }
 func (s *RemovePendingReplaceStep) Logical() bool           { return false }
+func (s *RemovePendingReplaceStep) Fail()                   {}
 
 func (s *RemovePendingReplaceStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
 	return resource.StatusOK, nil, nil
@@ -509,6 +519,7 @@ func (s *UpdateStep) Old() *resource.State                         { return s.ol
 func (s *UpdateStep) New() *resource.State                         { return s.new }
 func (s *UpdateStep) Res() *resource.State                         { return s.new }
 func (s *UpdateStep) Logical() bool                                { return true }
+func (s *UpdateStep) Fail()                                        { s.reg.Done(&RegisterResult{Failed: true}) }
 func (s *UpdateStep) Diffs() []resource.PropertyKey                { return s.diffs }
 func (s *UpdateStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
 
@@ -611,6 +622,7 @@ func (s *ReplaceStep) Keys() []resource.PropertyKey                 { return s.k
 func (s *ReplaceStep) Diffs() []resource.PropertyKey                { return s.diffs }
 func (s *ReplaceStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
 func (s *ReplaceStep) Logical() bool                                { return true }
+func (s *ReplaceStep) Fail()                                        {}
 
 func (s *ReplaceStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
 	// If this is a pending delete, we should have marked the old resource for deletion in the CreateReplacement step.
@@ -698,6 +710,7 @@ func (s *ReadStep) Old() *resource.State    { return s.old }
 func (s *ReadStep) New() *resource.State    { return s.new }
 func (s *ReadStep) Res() *resource.State    { return s.new }
 func (s *ReadStep) Logical() bool           { return !s.replacing }
+func (s *ReadStep) Fail()                   { s.event.Done(&ReadResult{Failed: true}) }
 
 func (s *ReadStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
 	urn := s.new.URN
@@ -803,6 +816,7 @@ func (s *RefreshStep) Old() *resource.State    { return s.old }
 func (s *RefreshStep) New() *resource.State    { return s.new }
 func (s *RefreshStep) Res() *resource.State    { return s.old }
 func (s *RefreshStep) Logical() bool           { return false }
+func (s *RefreshStep) Fail()                   {}
 
 // ResultOp returns the operation that corresponds to the change to this resource after reading its current state, if
 // any.
@@ -875,6 +889,7 @@ func (s *RefreshStep) Apply(preview bool) (resource.Status, StepCompleteFunc, er
 			s.old.Parent, s.old.Protect, s.old.External, s.old.Dependencies, initErrors, s.old.Provider,
 			s.old.PropertyDependencies, s.old.PendingReplacement, s.old.AdditionalSecretOutputs, s.old.Aliases,
 			&s.old.CustomTimeouts, s.old.ImportID, s.old.RetainOnDelete, s.old.DeletedWith, s.old.Created, s.old.Modified)
+		s.new.RetryPolicy = s.old.RetryPolicy
 		complete = func() {
 			var inputsChange, outputsChange bool
 			if s.old != nil {
@@ -991,6 +1006,7 @@ func (s *ImportStep) Old() *resource.State                         { return s.ol
 func (s *ImportStep) New() *resource.State                         { return s.new }
 func (s *ImportStep) Res() *resource.State                         { return s.new }
 func (s *ImportStep) Logical() bool                                { return !s.replacing }
+func (s *ImportStep) Fail()                                        { s.reg.Done(&RegisterResult{Failed: true}) }
 func (s *ImportStep) Diffs() []resource.PropertyKey                { return s.diffs }
 func (s *ImportStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
 
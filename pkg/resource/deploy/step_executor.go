@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
@@ -42,10 +44,12 @@ const (
 // that we don't do so.
 var errStepApplyFailed = errors.New("step application failed")
 
-// The step executor operates in terms of "chains" and "antichains". A chain is set of steps that are totally ordered
-// when ordered by dependency; each step in a chain depends directly on the step that comes before it. An antichain
-// is a set of steps that is completely incomparable when ordered by dependency. The step executor is aware that chains
-// must be executed serially and antichains can be executed concurrently.
+// The step executor operates in terms of step DAGs. A step DAG is a set of steps along with the dependencies between
+// them; the step executor begins each step of a DAG as soon as the steps it depends on have completed. Chains and
+// antichains are the two simplest step DAGs. A chain is set of steps that are totally ordered when ordered by
+// dependency; each step in a chain depends directly on the step that comes before it. An antichain is a set of steps
+// that is completely incomparable when ordered by dependency. The step executor is aware that chains must be executed
+// serially and antichains can be executed concurrently.
 //
 // See https://en.wikipedia.org/wiki/Antichain for more complete definitions. The below type aliases are useful for
 // documentation purposes.
@@ -56,8 +60,14 @@ type chain = []Step
 // An Antichain is a set of Steps that can be executed in parallel.
 type antichain = []Step
 
-// A CompletionToken is a token returned by the step executor that is completed when the chain has completed execution.
-// Callers can use it to optionally wait synchronously on the completion of a chain.
+// A stepDAG is a set of Steps along with the dependencies between them. The dependencies must not contain cycles.
+type stepDAG struct {
+	steps        []Step          // the steps to execute.
+	dependencies map[Step][]Step // the steps that must complete successfully before each step can begin.
+}
+
+// A CompletionToken is a token returned by the step executor that is completed when the steps it was given have
+// completed execution. Callers can use it to optionally wait synchronously on the completion of the steps.
 type completionToken struct {
 	channel chan bool
 }
@@ -70,19 +80,19 @@ func (c completionToken) Wait(ctx context.Context) {
 	}
 }
 
-// incomingChain represents a request to the step executor to execute a chain.
-type incomingChain struct {
-	Chain          chain     // The chain we intend to execute
-	CompletionChan chan bool // A completion channel to be closed when the chain has completed execution
+// incomingStep represents a request to the step executor to execute a step.
+type incomingStep struct {
+	Step           Step      // The step we intend to execute
+	CompletionChan chan bool // A completion channel to be closed when the step has completed execution
 }
 
 // stepExecutor is the component of the engine responsible for taking steps and executing
 // them, possibly in parallel if requested. The step generator operates on the granularity
-// of "chains", which are sequences of steps that must be executed exactly in the given order.
-// Chains are a simplification of the full dependency graph DAG within Pulumi programs. Since
-// Pulumi language hosts can only invoke the resource monitor once all of their dependencies have
-// resolved, we (the engine) can assume that any chain given to us by the step generator is already
-// ready to execute.
+// of step DAGs, which are sets of steps along with the dependencies between them. Step DAGs
+// only include the dependencies between the steps of a single resource, or between the deletes
+// that are scheduled once the program has finished. Since Pulumi language hosts can only invoke
+// the resource monitor once all of their dependencies have resolved, we (the engine) can assume
+// that the steps for the resources that a program registers depend on no steps outside of their DAG.
 type stepExecutor struct {
 	deployment      *Deployment // The deployment currently being executed.
 	opts            Options     // The options for this current deployment.
@@ -90,49 +100,114 @@ type stepExecutor struct {
 	pendingNews     sync.Map    // Resources that have been created but are pending a RegisterResourceOutputs.
 	continueOnError bool        // True if we want to continue the deployment after a step error.
 
-	workers        sync.WaitGroup     // WaitGroup tracking the worker goroutines that are owned by this step executor.
-	incomingChains chan incomingChain // Incoming chains that we are to execute
+	workers       sync.WaitGroup    // WaitGroup tracking the worker goroutines that are owned by this step executor.
+	submitters    sync.WaitGroup    // WaitGroup tracking the goroutines that may still submit steps.
+	incomingSteps chan incomingStep // Incoming steps that we are to execute
 
 	ctx      context.Context    // cancellation context for the current deployment.
 	cancel   context.CancelFunc // CancelFunc that cancels the above context.
 	sawError atomic.Value       // atomic boolean indicating whether or not the step excecutor saw that there was an error.
+
+	erroredStepsLock sync.Mutex // Lock protecting erroredSteps.
+	erroredSteps     []Step     // The steps that failed, in the order in which they failed.
+
+	limitersLock sync.Mutex               // Lock protecting limiters.
+	limiters     map[string]chan struct{} // Semaphores limiting the concurrent operations of packages and providers.
 }
 
 //
 // The stepExecutor communicates with a stepGenerator by listening to a channel. As the step generator
-// generates new chains that need to be executed, the step executor will listen to this channel to execute
+// generates new steps that need to be executed, the step executor will listen to this channel to execute
 // those steps.
 //
 
-// Execute submits a Chain for asynchronous execution. The execution of the chain will begin as soon as there
-// is a worker available to execute it.
+// ExecuteSerial submits a Chain for asynchronous execution. Each step of the chain is executed once the step before it
+// has completed successfully.
 func (se *stepExecutor) ExecuteSerial(chain chain) completionToken {
-	// The select here is to avoid blocking on a send to se.incomingChains if a cancellation is pending.
-	// If one is pending, we should exit early - we will shortly be tearing down the engine and exiting.
-
-	completion := make(chan bool)
-	select {
-	case se.incomingChains <- incomingChain{Chain: chain, CompletionChan: completion}:
-	case <-se.ctx.Done():
-		close(completion)
+	dag := stepDAG{steps: chain, dependencies: make(map[Step][]Step)}
+	for i := 1; i < len(chain); i++ {
+		dag.dependencies[chain[i]] = []Step{chain[i-1]}
 	}
-
-	return completionToken{channel: completion}
+	return se.ExecuteDAG(dag)
 }
 
 // ExecuteParallel submits an antichain for parallel execution. All of the steps within the antichain are submitted for
 // concurrent execution.
 func (se *stepExecutor) ExecuteParallel(antichain antichain) completionToken {
+	return se.ExecuteDAG(stepDAG{steps: antichain})
+}
+
+// ExecuteDAG submits a step DAG for execution. Each step is submitted for execution as soon as all of the steps it
+// depends on have completed, so that unrelated steps never wait on one another. The steps that depend on no other
+// steps are submitted before ExecuteDAG returns, in the given order; the execution of each of them will begin as soon
+// as there is a worker available to execute it. If a step fails, the steps that depend on it, directly or indirectly,
+// are skipped.
+func (se *stepExecutor) ExecuteDAG(dag stepDAG) completionToken {
+	// Each step gets a node whose channel is closed when the step has completed or has been skipped. The failed flag
+	// is written before the channel is closed, so it is safe to read once the channel is closed.
+	type node struct {
+		done   chan bool
+		failed bool
+	}
+	nodes := make(map[Step]*node, len(dag.steps))
+	for _, step := range dag.steps {
+		nodes[step] = &node{done: make(chan bool)}
+	}
+
 	var wg sync.WaitGroup
+	wg.Add(len(dag.steps))
+	for _, step := range dag.steps {
+		step, n := step, nodes[step]
+
+		// If the step depends on no other steps, submit it right away. The execution of the DAG then only waits on its
+		// completion.
+		deps := dag.dependencies[step]
+		if len(deps) == 0 {
+			tok := se.submit(step)
+			go func() {
+				defer wg.Done()
+				defer close(n.done)
 
-	// ExecuteParallel is implemented in terms of ExecuteSerial - it executes each step individually and waits for all
-	// of the steps to complete.
-	wg.Add(len(antichain))
-	for _, step := range antichain {
-		tok := se.ExecuteSerial(chain{step})
+				tok.Wait(se.ctx)
+				n.failed = se.ctx.Err() != nil || se.stepFailed(step)
+			}()
+			continue
+		}
+
+		// Otherwise, wait on the steps it depends on before submitting it.
+		se.submitters.Add(1)
 		go func() {
 			defer wg.Done()
+			defer close(n.done)
+
+			for _, dep := range deps {
+				d, ok := nodes[dep]
+				contract.Assertf(ok, "step %v on %v depends on a step outside of the DAG", step.Op(), step.URN())
+				select {
+				case <-d.done:
+				case <-se.ctx.Done():
+					se.submitters.Done()
+					n.failed = true
+					return
+				}
+				if d.failed {
+					se.log(synchronousWorkerID, "skipping step %v on %v, as step %v on %v did not succeed",
+						step.Op(), step.URN(), dep.Op(), dep.URN())
+					if se.continueOnError {
+						// The deployment goes on, so let the program know that this step failed. The program won't
+						// register any resources that depend on it.
+						step.Fail()
+					}
+					se.submitters.Done()
+					n.failed = true
+					return
+				}
+			}
+
+			tok := se.submit(step)
+			se.submitters.Done()
 			tok.Wait(se.ctx)
+			n.failed = se.ctx.Err() != nil || se.stepFailed(step)
 		}()
 	}
 
@@ -145,6 +220,22 @@ func (se *stepExecutor) ExecuteParallel(antichain antichain) completionToken {
 	return completionToken{channel: done}
 }
 
+// submit submits a single step for asynchronous execution. The execution of the step will begin as soon as there is a
+// worker available to execute it.
+func (se *stepExecutor) submit(step Step) completionToken {
+	// The select here is to avoid blocking on a send to se.incomingSteps if a cancellation is pending.
+	// If one is pending, we should exit early - we will shortly be tearing down the engine and exiting.
+
+	completion := make(chan bool)
+	select {
+	case se.incomingSteps <- incomingStep{Step: step, CompletionChan: completion}:
+	case <-se.ctx.Done():
+		close(completion)
+	}
+
+	return completionToken{channel: completion}
+}
+
 // ExecuteRegisterResourceOutputs services a RegisterResourceOutputsEvent synchronously on the calling goroutine.
 func (se *stepExecutor) ExecuteRegisterResourceOutputs(e RegisterResourceOutputsEvent) result.Result {
 	// Look up the final state in the pending registration list.
@@ -190,8 +281,8 @@ func (se *stepExecutor) ExecuteRegisterResourceOutputs(e RegisterResourceOutputs
 	}
 
 	// If there is an event subscription for finishing the resource, execute them.
-	if e := se.opts.Events; e != nil {
-		if eventerr := e.OnResourceOutputs(reg); eventerr != nil {
+	if events := se.opts.Events; events != nil {
+		if eventerr := events.OnResourceOutputs(reg); eventerr != nil {
 			se.log(synchronousWorkerID, "register resource outputs failed: %s", eventerr.Error())
 
 			// This is a bit of a kludge, but ExecuteRegisterResourceOutputs is an odd duck
@@ -203,7 +294,11 @@ func (se *stepExecutor) ExecuteRegisterResourceOutputs(e RegisterResourceOutputs
 			outErr := fmt.Errorf("resource complete event returned an error: %w", eventerr)
 			diagMsg := diag.RawMessage(reg.URN(), outErr.Error())
 			se.deployment.Diag().Errorf(diagMsg)
-			se.cancelDueToError()
+			se.cancelDueToError(nil)
+			if se.continueOnError {
+				// The program is still running, so let it know that the outputs have been registered.
+				e.Done()
+			}
 			return nil
 		}
 	}
@@ -216,14 +311,29 @@ func (se *stepExecutor) Errored() bool {
 	return se.sawError.Load().(bool)
 }
 
-// SignalCompletion signals to the stepExecutor that there are no more chains left to execute. All worker
-// threads will terminate as soon as they retire all of the work they are currently executing.
+// stepFailed returns true if the execution of the given step ended in failure.
+func (se *stepExecutor) stepFailed(step Step) bool {
+	se.erroredStepsLock.Lock()
+	defer se.erroredStepsLock.Unlock()
+
+	for _, errored := range se.erroredSteps {
+		if errored == step {
+			return true
+		}
+	}
+	return false
+}
+
+// SignalCompletion signals to the stepExecutor that there are no more steps left to execute. All worker
+// threads will terminate as soon as they retire all of the work they are currently executing. Any step DAG that is
+// still executing is first allowed to submit its remaining steps.
 func (se *stepExecutor) SignalCompletion() {
-	close(se.incomingChains)
+	se.submitters.Wait()
+	close(se.incomingSteps)
 }
 
 // WaitForCompletion blocks the calling goroutine until the step executor completes execution of all in-flight
-// chains.
+// steps.
 func (se *stepExecutor) WaitForCompletion() {
 	se.log(synchronousWorkerID, "StepExecutor.waitForCompletion(): waiting for worker threads to exit")
 	se.workers.Wait()
@@ -231,41 +341,50 @@ func (se *stepExecutor) WaitForCompletion() {
 }
 
 //
-// As calls to `Execute` submit chains for execution, some number of worker goroutines will continuously
-// read from `incomingChains` and execute any chains that are received. The core execution logic is in
+// As calls to `Execute` submit steps for execution, some number of worker goroutines will continuously
+// read from `incomingSteps` and execute any steps that are received. The core execution logic is in
 // the next few functions.
 //
 
-// executeChain executes a chain, one step at a time. If any step in the chain fails to execute, or if the
-// context is canceled, the chain stops execution.
-func (se *stepExecutor) executeChain(workerID int, chain chain) {
-	for _, step := range chain {
-		select {
-		case <-se.ctx.Done():
-			se.log(workerID, "step %v on %v canceled", step.Op(), step.URN())
-			return
-		default:
-		}
+// executeIncomingStep executes a step that was submitted to the step executor, unless the context is canceled. If the
+// step fails to execute, the steps that depend on it are skipped.
+func (se *stepExecutor) executeIncomingStep(workerID int, step Step) {
+	select {
+	case <-se.ctx.Done():
+		se.log(workerID, "step %v on %v canceled", step.Op(), step.URN())
+		return
+	default:
+	}
 
-		if err := se.executeStep(workerID, step); err != nil {
-			se.log(workerID, "step %v on %v failed, signalling cancellation", step.Op(), step.URN())
-			se.cancelDueToError()
-			if err != errStepApplyFailed {
-				// Step application errors are recorded by the OnResourceStepPost callback. This is confusing,
-				// but it means that at this level we shouldn't be logging any errors that came from there.
-				//
-				// The errStepApplyFailed sentinel signals that the error that failed this chain was a step apply
-				// error and that we shouldn't log it. Everything else should be logged to the diag system as usual.
-				diagMsg := diag.RawMessage(step.URN(), err.Error())
-				se.deployment.Diag().Errorf(diagMsg)
-			}
-			return
+	if err := se.executeStep(workerID, step); err != nil {
+		se.log(workerID, "step %v on %v failed, signalling cancellation", step.Op(), step.URN())
+		se.cancelDueToError(step)
+		if se.continueOnError {
+			// The deployment goes on, so let the program know that this step failed. The program won't register any
+			// resources that depend on it.
+			step.Fail()
+		}
+		if err != errStepApplyFailed {
+			// Step application errors are recorded by the OnResourceStepPost callback. This is confusing,
+			// but it means that at this level we shouldn't be logging any errors that came from there.
+			//
+			// The errStepApplyFailed sentinel signals that the error that failed this step was a step apply
+			// error and that we shouldn't log it. Everything else should be logged to the diag system as usual.
+			diagMsg := diag.RawMessage(step.URN(), err.Error())
+			se.deployment.Diag().Errorf(diagMsg)
 		}
 	}
 }
 
-func (se *stepExecutor) cancelDueToError() {
+// cancelDueToError records the failure of the given step, if any, and cancels the deployment unless the step
+// executor continues on errors.
+func (se *stepExecutor) cancelDueToError(step Step) {
 	se.sawError.Store(true)
+	if step != nil {
+		se.erroredStepsLock.Lock()
+		se.erroredSteps = append(se.erroredSteps, step)
+		se.erroredStepsLock.Unlock()
+	}
 	if !se.continueOnError {
 		se.cancel()
 	}
@@ -282,6 +401,128 @@ func (se *stepExecutor) cancelDueToError() {
 // verbatim to the post-step event.
 //
 
+// applyStep applies the given step. If the step fails without changing its resource, it is retried according to the
+// retry policy of the resource.
+func (se *stepExecutor) applyStep(workerID int, step Step) (resource.Status, StepCompleteFunc, error) {
+	policy := stepRetryPolicy(step)
+	for attempts := 1; ; attempts++ {
+		release, err := se.acquireProviderLimits(step)
+		if err != nil {
+			return resource.StatusOK, nil, err
+		}
+		status, stepComplete, err := step.Apply(se.preview)
+		release()
+		if err == nil || se.preview || !retryable(status, policy, err, attempts) {
+			return status, stepComplete, err
+		}
+		var protectErr deleteProtectedError
+		if errors.As(err, &protectErr) {
+			return status, stepComplete, err
+		}
+
+		delay := policy.Delay(attempts)
+		se.log(workerID, "step %v on %v failed with a retryable error, retrying in %v: %v",
+			step.Op(), step.URN(), delay, err)
+		se.deployment.Diag().Warningf(diag.Message(step.URN(), "%v failed (attempt %d of %d), retrying in %v: %v"),
+			step.Op(), attempts, policy.MaxAttempts, delay, err)
+
+		select {
+		case <-time.After(delay):
+		case <-se.ctx.Done():
+			return status, stepComplete, err
+		}
+	}
+}
+
+// retryable returns true if the given step, which failed with the given status and error after the given number of
+// attempts, should be retried according to the given policy. Partial failures leave behind a resource that must be
+// recorded, so they are never retried. Other failed creates, including those that provider plugins report with
+// StatusUnknown, don't record a resource, so retrying them is no different from running the update again.
+func retryable(status resource.Status, policy *resource.RetryPolicy, err error, attempts int) bool {
+	if policy == nil || status == resource.StatusPartialFailure {
+		return false
+	}
+	return policy.Retryable(err, attempts)
+}
+
+// stepRetryPolicy returns the retry policy that applies to the given step, if any. Only the steps that perform
+// provider operations which leave the resource unchanged when they fail are retried.
+func stepRetryPolicy(step Step) *resource.RetryPolicy {
+	switch step.Op() {
+	case OpCreate, OpCreateReplacement, OpUpdate:
+		return step.New().RetryPolicy
+	case OpDelete, OpDeleteReplaced:
+		return step.Old().RetryPolicy
+	default:
+		return nil
+	}
+}
+
+// acquireProviderLimits waits until the concurrency limits of the package and of the provider of the given step, if
+// any, permit it to perform its provider operation. It returns a function that releases the acquired slots, or an
+// error if the deployment is canceled while waiting.
+func (se *stepExecutor) acquireProviderLimits(step Step) (func(), error) {
+	if !stepCallsProvider(step) || providers.IsProviderType(step.Type()) || step.Provider() == "" {
+		return func() {}, nil
+	}
+	ref, err := providers.ParseReference(step.Provider())
+	if err != nil {
+		// Applying the step reports the bad provider reference.
+		return func() {}, nil
+	}
+
+	var limiters []chan struct{}
+	pkg := providers.GetProviderPackage(ref.URN().Type())
+	if limit := se.opts.ProviderParallelism[pkg]; limit > 0 {
+		limiters = append(limiters, se.limiter("package:"+string(pkg), limit))
+	}
+	if se.deployment.providers != nil {
+		if limit := se.deployment.providers.Parallelism(ref); limit > 0 {
+			limiters = append(limiters, se.limiter("provider:"+ref.String(), limit))
+		}
+	}
+
+	// The package limiter is always acquired before the provider limiter, so that steps cannot deadlock on each other.
+	release := func(acquired []chan struct{}) {
+		for _, l := range acquired {
+			<-l
+		}
+	}
+	for i, l := range limiters {
+		select {
+		case l <- struct{}{}:
+		case <-se.ctx.Done():
+			release(limiters[:i])
+			return nil, se.ctx.Err()
+		}
+	}
+	return func() { release(limiters) }, nil
+}
+
+// limiter returns the semaphore with the given key, creating it with the given capacity if it does not exist yet.
+func (se *stepExecutor) limiter(key string, limit int) chan struct{} {
+	se.limitersLock.Lock()
+	defer se.limitersLock.Unlock()
+
+	l, ok := se.limiters[key]
+	if !ok {
+		l = make(chan struct{}, limit)
+		se.limiters[key] = l
+	}
+	return l
+}
+
+// stepCallsProvider returns true if applying the given step performs an operation on the provider of its resource.
+func stepCallsProvider(step Step) bool {
+	switch step.Op() {
+	case OpCreate, OpCreateReplacement, OpUpdate, OpDelete, OpDeleteReplaced, OpRead, OpReadReplacement,
+		OpRefresh, OpImport, OpImportReplacement:
+		return true
+	default:
+		return false
+	}
+}
+
 // executeStep executes a single step, returning true if the step execution was successful and
 // false if it was not.
 func (se *stepExecutor) executeStep(workerID int, step Step) error {
@@ -297,7 +538,7 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 	}
 
 	se.log(workerID, "applying step %v on %v (preview %v)", step.Op(), step.URN(), se.preview)
-	status, stepComplete, err := step.Apply(se.preview)
+	status, stepComplete, err := se.applyStep(workerID, step)
 
 	if err == nil {
 		// If we have a state object, and this is a create or update, remember it, as we may need to update it later.
@@ -366,8 +607,9 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 	}
 
 	// Calling stepComplete allows steps that depend on this step to continue. OnResourceStepPost saved the results
-	// of the step in the snapshot, so we are ready to go.
-	if stepComplete != nil {
+	// of the step in the snapshot, so we are ready to go. If we continue on errors, a step that failed partially
+	// must not let its dependents continue; executeIncomingStep signals its failure instead.
+	if stepComplete != nil && (err == nil || !se.continueOnError) {
 		se.log(workerID, "step %v on %v retired", step.Op(), step.URN())
 		stepComplete()
 	}
@@ -393,7 +635,7 @@ func (se *stepExecutor) log(workerID int, msg string, args ...interface{}) {
 // executing steps. By default, as we ease into the waters of parallelism, there is at most one worker
 // active.
 //
-// Workers continuously pull from se.incomingChains, executing chains as they are provided to the executor.
+// Workers continuously pull from se.incomingSteps, executing steps as they are provided to the executor.
 // There are two reasons why a worker would exit:
 //
 //  1. A worker exits if se.ctx is canceled. There are two ways that se.ctx gets canceled: first, if there is
@@ -402,27 +644,27 @@ func (se *stepExecutor) log(workerID int, msg string, args ...interface{}) {
 //  2. A worker exits if it experiences an error when running a step.
 //
 
-// worker is the base function for all step executor worker goroutines. It continuously polls for new chains
+// worker is the base function for all step executor worker goroutines. It continuously polls for new steps
 // and executes any that it gets from the channel. If `launchAsync` is true, worker launches a new goroutine
-// that will execute the chain so that the execution continues asynchronously and this worker can proceed to
-// the next chain.
+// that will execute the step so that the execution continues asynchronously and this worker can proceed to
+// the next step.
 func (se *stepExecutor) worker(workerID int, launchAsync bool) {
 	se.log(workerID, "worker coming online")
 	defer se.workers.Done()
 
 	oneshotWorkerID := 0
 	for {
-		se.log(workerID, "worker waiting for incoming chains")
+		se.log(workerID, "worker waiting for incoming steps")
 		select {
-		case request := <-se.incomingChains:
-			if request.Chain == nil {
-				se.log(workerID, "worker received nil chain, exiting")
+		case request := <-se.incomingSteps:
+			if request.Step == nil {
+				se.log(workerID, "worker received nil step, exiting")
 				return
 			}
 
-			se.log(workerID, "worker received chain for execution")
+			se.log(workerID, "worker received step for execution")
 			if !launchAsync {
-				se.executeChain(workerID, request.Chain)
+				se.executeIncomingStep(workerID, request.Step)
 				close(request.CompletionChan)
 				continue
 			}
@@ -434,7 +676,7 @@ func (se *stepExecutor) worker(workerID int, launchAsync bool) {
 			go func() {
 				defer se.workers.Done()
 				se.log(newWorkerID, "launching oneshot worker")
-				se.executeChain(newWorkerID, request.Chain)
+				se.executeIncomingStep(newWorkerID, request.Step)
 				close(request.CompletionChan)
 			}()
 
@@ -454,14 +696,15 @@ func newStepExecutor(ctx context.Context, cancel context.CancelFunc, deployment
 		opts:            opts,
 		preview:         preview,
 		continueOnError: continueOnError,
-		incomingChains:  make(chan incomingChain),
+		incomingSteps:   make(chan incomingStep),
+		limiters:        make(map[string]chan struct{}),
 		ctx:             ctx,
 		cancel:          cancel,
 	}
 
 	exec.sawError.Store(false)
 
-	// If we're being asked to run as parallel as possible, spawn a single worker that launches chain executions
+	// If we're being asked to run as parallel as possible, spawn a single worker that launches step executions
 	// asynchronously.
 	if opts.InfiniteParallelism() {
 		exec.workers.Add(1)
@@ -533,6 +533,7 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, res
 		goal.Dependencies, goal.InitErrors, goal.Provider, goal.PropertyDependencies, false,
 		goal.AdditionalSecretOutputs, aliasUrns, &goal.CustomTimeouts, "", goal.RetainOnDelete, goal.DeletedWith,
 		createdAt, modifiedAt)
+	new.RetryPolicy = goal.RetryPolicy
 
 	// Mark the URN/resource as having been seen. So we can run analyzers on all resources seen, as well as
 	// lookup providers for calculating replacement of resources that use the provider.
@@ -1287,91 +1288,82 @@ func (sg *stepGenerator) determineAllowedResourcesToDeleteFromTargets(
 	return resourcesToDelete, nil
 }
 
-// ScheduleDeletes takes a list of steps that will delete resources and "schedules" them by producing a list of list of
-// steps, where each list can be executed in parallel but a previous list must be executed to completion before
-// advancing to the next list.
+// ScheduleDeletes takes a list of steps that will delete resources and "schedules" them by producing a DAG of these
+// steps, in which each step depends on the steps that must complete before it can safely begin executing.
 //
-// In lieu of tracking per-step dependencies and orienting the step executor around these dependencies, this function
-// provides a conservative approximation of what deletions can safely occur in parallel. The insight here is that the
-// resource dependency graph is a partially-ordered set and all partially-ordered sets can be easily decomposed into
-// antichains - subsets of the set that are all not comparable to one another. (In this definition, "not comparable"
-// means "do not depend on one another").
+// We must process deletes in reverse (so we don't delete resources upon which other resources depend): a resource
+// can only be deleted when all of the *condemned resources that depend on it* have been deleted. The step that deletes
+// a resource therefore depends on the steps that delete each condemned resource whose dependencies (as computed by
+// DependencyGraph.DependenciesOf, so including parents and providers) include it. Since the dependency graph is a
+// partially-ordered set, the resulting DAG has no cycles.
 //
-// The algorithm for decomposing a poset into antichains is:
-//  1. While there exist elements in the poset,
-//     1a. There must exist at least one "maximal" element of the poset. Let E_max be those elements.
-//     2a. Remove all elements E_max from the poset. E_max is an antichain.
-//     3a. Goto 1.
-//
-// Translated to our dependency graph:
-//  1. While the set of condemned resources is not empty:
-//     1a. Remove all resources with no outgoing edges from the graph and add them to the current antichain.
-//     2a. Goto 1.
-//
-// The resulting list of antichains is a list of list of steps that can be safely executed in parallel. Since we must
-// process deletes in reverse (so we don't delete resources upon which other resources depend), we reverse the list and
-// hand it back to the deployment executor for safe execution.
-func (sg *stepGenerator) ScheduleDeletes(deleteSteps []Step) []antichain {
-	var antichains []antichain                // the list of parallelizable steps we intend to return.
-	dg := sg.deployment.depGraph              // the current deployment's dependency graph.
-	condemned := make(graph.ResourceSet)      // the set of condemned resources.
-	stepMap := make(map[*resource.State]Step) // a map from resource states to the steps that delete them.
+// Unlike scheduling the deletes as a list of antichains, which must each complete before the next one begins, this
+// lets the step executor begin each delete as soon as the deletes it actually waits on have completed.
+func (sg *stepGenerator) ScheduleDeletes(deleteSteps []Step) stepDAG {
+	dag := stepDAG{steps: deleteSteps, dependencies: make(map[Step][]Step)}
 
 	// If we don't trust the dependency graph we've been given, we must be conservative and delete everything serially.
 	if !sg.opts.TrustDependencies {
 		logging.V(7).Infof("Planner does not trust dependency graph, scheduling deletions serially")
-		for _, step := range deleteSteps {
-			antichains = append(antichains, antichain{step})
+		for i := 1; i < len(deleteSteps); i++ {
+			dag.dependencies[deleteSteps[i]] = []Step{deleteSteps[i-1]}
 		}
 
-		return antichains
+		return dag
 	}
 
 	logging.V(7).Infof("Planner trusts dependency graph, scheduling deletions in parallel")
 
-	// For every step we've been given, record it as condemned and save the step that will be used to delete it. We'll
-	// iteratively place these steps into antichains as we remove elements from the condemned set.
+	dg := sg.deployment.depGraph              // the current deployment's dependency graph.
+	condemned := make(graph.ResourceSet)      // the set of condemned resources.
+	stepMap := make(map[*resource.State]Step) // a map from resource states to the steps that delete them.
 	for _, step := range deleteSteps {
 		condemned[step.Res()] = true
 		stepMap[step.Res()] = step
 	}
 
-	for len(condemned) > 0 {
-		var steps antichain
-		logging.V(7).Infof("Planner beginning schedule of new deletion antichain")
-		for res := range condemned {
-			// Does res have any outgoing edges to resources that haven't already been removed from the graph?
-			condemnedDependencies := dg.DependenciesOf(res).Intersect(condemned)
-			if len(condemnedDependencies) == 0 {
-				// If not, it's safe to delete res at this stage.
-				logging.V(7).Infof("Planner scheduling deletion of '%v'", res.URN)
-				steps = append(steps, stepMap[res])
-			}
-
-			// If one of this resource's dependencies or this resource's parent hasn't been removed from the graph yet,
-			// it can't be deleted this round.
-		}
-
-		// For all reosurces that are to be deleted in this round, remove them from the graph.
-		for _, step := range steps {
-			delete(condemned, step.Res())
+	// Each condemned dependency of a condemned resource can only be deleted once the resource itself is deleted.
+	for _, step := range deleteSteps {
+		for dep := range dg.DependenciesOf(step.Res()).Intersect(condemned) {
+			logging.V(7).Infof("Planner scheduling deletion of '%v' after deletion of '%v'", dep.URN, step.URN())
+			depStep := stepMap[dep]
+			dag.dependencies[depStep] = append(dag.dependencies[depStep], step)
 		}
-
-		antichains = append(antichains, steps)
 	}
 
-	// Up until this point, all logic has been "backwards" - we're scheduling resources for deletion when all of their
-	// dependencies finish deletion, but that's exactly the opposite of what we need to do. We can only delete a
-	// resource when all *resources that depend on it* complete deletion. Our solution is still correct, though, it's
-	// just backwards.
-	//
-	// All we have to do here is reverse the list and then our solution is correct.
-	for i := len(antichains)/2 - 1; i >= 0; i-- {
-		opp := len(antichains) - 1 - i
-		antichains[i], antichains[opp] = antichains[opp], antichains[i]
+	return dag
+}
+
+// ScheduleSteps takes the list of steps that were generated for a single resource and "schedules" them by producing a
+// DAG of these steps, in which each step depends on the steps that must complete before it can safely begin executing.
+//
+// The steps for a resource must generally be executed in the order in which they were generated, so each step depends
+// on the step before it. The exception are the deletes that lead the list when a resource is deleted before it is
+// replaced: the deletes of the resource and of the resources that would be replaced along with it. These are
+// scheduled like the deletes at the end of an update (see ScheduleDeletes), so that the deletes of unrelated
+// dependents don't wait on one another, and the step that follows them depends on all of them.
+func (sg *stepGenerator) ScheduleSteps(steps []Step) stepDAG {
+	deletes := 0
+	for deletes < len(steps) && (steps[deletes].Op() == OpDelete || steps[deletes].Op() == OpDeleteReplaced) {
+		deletes++
+	}
+
+	dag := stepDAG{steps: steps, dependencies: make(map[Step][]Step)}
+	if deletes > 0 {
+		dag.dependencies = sg.ScheduleDeletes(steps[:deletes]).dependencies
+	}
+	for i := deletes; i < len(steps); i++ {
+		switch {
+		case i == 0:
+			// The first step depends on no other steps.
+		case i == deletes:
+			dag.dependencies[steps[i]] = append([]Step(nil), steps[:deletes]...)
+		default:
+			dag.dependencies[steps[i]] = []Step{steps[i-1]}
+		}
 	}
 
-	return antichains
+	return dag
 }
 
 // providerChanged diffs the Provider field of old and new resources, returning true if the rest of the step generator
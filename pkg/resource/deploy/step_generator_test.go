@@ -18,8 +18,10 @@ import (
 	"testing"
 
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/v3/resource/graph"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -360,3 +362,87 @@ func TestEngineDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestScheduleDeletes(t *testing.T) {
+	t.Parallel()
+
+	newState := func(name string, parent resource.URN, deps ...resource.URN) *resource.State {
+		return &resource.State{
+			Type:         "my:mod:Comp",
+			URN:          resource.NewURN("stack", "proj", "", "my:mod:Comp", tokens.QName(name)),
+			Parent:       parent,
+			Dependencies: deps,
+		}
+	}
+	a := newState("a", "")
+	b := newState("b", "", a.URN)
+	c := newState("c", a.URN)
+	d := newState("d", "")
+
+	deployment := &Deployment{depGraph: graph.NewDependencyGraph([]*resource.State{a, b, c, d})}
+	deletes := map[resource.URN]bool{}
+	var steps []Step
+	for _, res := range []*resource.State{d, c, b, a} {
+		steps = append(steps, NewDeleteStep(deployment, deletes, res))
+	}
+	stepD, stepC, stepB, stepA := steps[0], steps[1], steps[2], steps[3]
+
+	// a can only be deleted after its dependent b and its child c, which can be deleted right away, as can d.
+	sg := &stepGenerator{deployment: deployment, opts: Options{TrustDependencies: true}}
+	dag := sg.ScheduleDeletes(steps)
+	assert.Equal(t, steps, dag.steps)
+	assert.ElementsMatch(t, []Step{stepB, stepC}, dag.dependencies[stepA])
+	assert.Empty(t, dag.dependencies[stepB])
+	assert.Empty(t, dag.dependencies[stepC])
+	assert.Empty(t, dag.dependencies[stepD])
+
+	// Without trusting the dependency graph, the deletes are executed serially.
+	sg = &stepGenerator{deployment: deployment, opts: Options{}}
+	dag = sg.ScheduleDeletes(steps)
+	assert.Empty(t, dag.dependencies[stepD])
+	assert.Equal(t, []Step{stepD}, dag.dependencies[stepC])
+	assert.Equal(t, []Step{stepC}, dag.dependencies[stepB])
+	assert.Equal(t, []Step{stepB}, dag.dependencies[stepA])
+}
+
+func TestScheduleSteps(t *testing.T) {
+	t.Parallel()
+
+	newState := func(name string, deps ...resource.URN) *resource.State {
+		return &resource.State{
+			Type:         "my:mod:Comp",
+			URN:          resource.NewURN("stack", "proj", "", "my:mod:Comp", tokens.QName(name)),
+			Dependencies: deps,
+		}
+	}
+	a := newState("a")
+	b := newState("b", a.URN)
+	c := newState("c", a.URN)
+	newA := newState("a")
+
+	deployment := &Deployment{depGraph: graph.NewDependencyGraph([]*resource.State{a, b, c})}
+	sg := &stepGenerator{deployment: deployment, opts: Options{TrustDependencies: true}}
+
+	// When a is deleted before it is replaced, the deletes of its dependents b and c don't wait on one another, the
+	// delete of a waits on both of them, and the replacement waits on all of the deletes.
+	deletes := map[resource.URN]bool{}
+	deleteB := NewDeleteReplacementStep(deployment, deletes, b, true)
+	deleteC := NewDeleteReplacementStep(deployment, deletes, c, true)
+	deleteA := NewDeleteReplacementStep(deployment, deletes, a, true)
+	replace := NewReplaceStep(deployment, a, newA, nil, nil, nil, false)
+	same := NewSameStep(deployment, nil, a, newA)
+	steps := []Step{deleteB, deleteC, deleteA, replace, same}
+
+	dag := sg.ScheduleSteps(steps)
+	assert.Equal(t, steps, dag.steps)
+	assert.Empty(t, dag.dependencies[deleteB])
+	assert.Empty(t, dag.dependencies[deleteC])
+	assert.ElementsMatch(t, []Step{deleteB, deleteC}, dag.dependencies[deleteA])
+	assert.ElementsMatch(t, []Step{deleteB, deleteC, deleteA}, dag.dependencies[replace])
+	assert.Equal(t, []Step{replace}, dag.dependencies[same])
+
+	// Without leading deletes, the steps are executed serially.
+	dag = sg.ScheduleSteps([]Step{replace, same})
+	assert.Empty(t, dag.dependencies[replace])
+	assert.Equal(t, []Step{replace}, dag.dependencies[same])
+}
@@ -39,3 +39,20 @@ type ResourceProtectedError struct {
 func (ResourceProtectedError) Error() string {
 	return "Can't delete protected resource"
 }
+
+// DanglingDependency records a reference from one resource to another that would be broken by moving resources
+// between stacks.
+type DanglingDependency struct {
+	From *resource.State
+	To   resource.URN
+}
+
+// MoveHasDanglingDependenciesError is returned by MoveResources if moving the requested resources would leave
+// dependencies that refer to resources in the other stack.
+type MoveHasDanglingDependenciesError struct {
+	Dependencies []DanglingDependency
+}
+
+func (e MoveHasDanglingDependenciesError) Error() string {
+	return fmt.Sprintf("Can't move resources due to %d dangling dependencies", len(e.Dependencies))
+}
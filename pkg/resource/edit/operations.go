@@ -216,3 +216,239 @@ func RenameStack(snap *deploy.Snapshot, newName tokens.Name, newProject tokens.P
 
 	return nil
 }
+
+// MoveResources moves the resources in the source snapshot that are selected by targets, along with all of their
+// descendants, into the destination snapshot. The URNs of moved resources are rewritten to belong to the given
+// destination stack and project. Providers required by the moved resources are copied into the destination; they are
+// only removed from the source if no remaining resource there refers to them.
+//
+// If moving the resources would leave a resource in either snapshot depending on a resource that only exists in the
+// other, an error instance of `MoveHasDanglingDependenciesError` is returned and neither snapshot is modified. If force
+// is true, the dangling dependencies are instead removed, and dangling parents are replaced by the root stack.
+//
+// The moved resources, as they now appear in the destination snapshot, are returned.
+func MoveResources(
+	source, dest *deploy.Snapshot, targets deploy.UrnTargets,
+	destStack tokens.Name, destProject tokens.PackageName, force bool,
+) ([]*resource.State, error) {
+	contract.Requiref(source != nil, "source", "must not be nil")
+	contract.Requiref(dest != nil, "dest", "must not be nil")
+
+	if err := source.VerifyIntegrity(); err != nil {
+		return nil, fmt.Errorf("source checkpoint is invalid: %w", err)
+	}
+	if err := dest.VerifyIntegrity(); err != nil {
+		return nil, fmt.Errorf("destination checkpoint is invalid: %w", err)
+	}
+
+	findRoot := func(snap *deploy.Snapshot) resource.URN {
+		for _, res := range snap.Resources {
+			if res.Type == resource.RootStackType && res.Parent == "" {
+				return res.URN
+			}
+		}
+		return ""
+	}
+	sourceRoot, destRoot := findRoot(source), findRoot(dest)
+
+	// Select the targeted resources and their descendants. Parents always precede their children, so a single pass
+	// is sufficient to pick up entire subtrees.
+	moving := make(map[*resource.State]bool)
+	movingURNs := make(map[resource.URN]bool)
+	for _, res := range source.Resources {
+		if res.URN == sourceRoot {
+			continue
+		}
+		if targets.Contains(res.URN) || movingURNs[res.Parent] {
+			moving[res] = true
+			movingURNs[res.URN] = true
+		}
+	}
+	if len(moving) == 0 {
+		return nil, fmt.Errorf("no resources matched the given URNs")
+	}
+
+	// Every provider used by a moving resource must come along with it.
+	requiredProviders := make(map[string]bool)
+	for res := range moving {
+		if res.Provider != "" {
+			requiredProviders[res.Provider] = true
+		}
+	}
+	for _, res := range source.Resources {
+		if !providers.IsProviderType(res.Type) || moving[res] {
+			continue
+		}
+		ref, err := providers.NewReference(res.URN, res.ID)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s is not referenceable: %w", res.URN, err)
+		}
+		if requiredProviders[ref.String()] {
+			moving[res] = true
+			movingURNs[res.URN] = true
+		}
+	}
+
+	// Providers that are still used by resources staying behind are copied rather than moved.
+	var remaining, transferred []*resource.State
+	retainedProviders := make(map[string]bool)
+	for _, res := range source.Resources {
+		if !moving[res] && res.Provider != "" {
+			retainedProviders[res.Provider] = true
+		}
+	}
+	remainingURNs := make(map[resource.URN]bool)
+	for _, res := range source.Resources {
+		if moving[res] {
+			transferred = append(transferred, res)
+			if providers.IsProviderType(res.Type) {
+				ref, err := providers.NewReference(res.URN, res.ID)
+				contract.AssertNoErrorf(err, "provider reference was validated above")
+				if !retainedProviders[ref.String()] {
+					continue
+				}
+			} else {
+				continue
+			}
+		}
+		remaining = append(remaining, res)
+		remainingURNs[res.URN] = true
+	}
+
+	// Find the dependencies that would refer across the two stacks once the move is done.
+	findDangling := func(resources []*resource.State, available map[resource.URN]bool) []DanglingDependency {
+		var dangling []DanglingDependency
+		check := func(res *resource.State, urn resource.URN) {
+			if urn != "" && urn != sourceRoot && !available[urn] {
+				dangling = append(dangling, DanglingDependency{From: res, To: urn})
+			}
+		}
+		for _, res := range resources {
+			check(res, res.Parent)
+			for _, dep := range res.Dependencies {
+				check(res, dep)
+			}
+			for _, deps := range res.PropertyDependencies {
+				for _, dep := range deps {
+					check(res, dep)
+				}
+			}
+			check(res, res.DeletedWith)
+		}
+		return dangling
+	}
+	dangling := findDangling(remaining, remainingURNs)
+	dangling = append(dangling, findDangling(transferred, movingURNs)...)
+	if len(dangling) != 0 && !force {
+		return nil, MoveHasDanglingDependenciesError{Dependencies: dangling}
+	}
+
+	rewriteURN := func(u resource.URN) resource.URN {
+		if u == sourceRoot {
+			return destRoot
+		}
+		return resource.NewURN(destStack.Q(), destProject, "", u.QualifiedType(), u.Name())
+	}
+
+	// Make sure that none of the rewritten URNs collide with resources already in the destination. Providers that are
+	// already present with the same ID are reused.
+	existing := make(map[resource.URN]*resource.State)
+	for _, res := range dest.Resources {
+		existing[res.URN] = res
+	}
+	var toAdd []*resource.State
+	for _, res := range transferred {
+		if other, has := existing[rewriteURN(res.URN)]; has {
+			if providers.IsProviderType(res.Type) && other.ID == res.ID {
+				continue
+			}
+			return nil, fmt.Errorf("resource %s already exists in the destination stack", other.URN)
+		}
+		toAdd = append(toAdd, res)
+	}
+
+	// At this point the move is known to succeed, so we can start mutating state. Resources that remain in the source
+	// may have to drop references to moved resources.
+	filterURNs := func(urns []resource.URN, available map[resource.URN]bool) []resource.URN {
+		var filtered []resource.URN
+		for _, urn := range urns {
+			if urn == sourceRoot || available[urn] {
+				filtered = append(filtered, urn)
+			}
+		}
+		return filtered
+	}
+	for _, res := range remaining {
+		if res.Parent != "" && !remainingURNs[res.Parent] {
+			res.Parent = sourceRoot
+		}
+		res.Dependencies = filterURNs(res.Dependencies, remainingURNs)
+		for key, deps := range res.PropertyDependencies {
+			res.PropertyDependencies[key] = filterURNs(deps, remainingURNs)
+		}
+		if res.DeletedWith != "" && !remainingURNs[res.DeletedWith] {
+			res.DeletedWith = ""
+		}
+	}
+
+	// Moved resources are copied so that providers that remain in the source are left untouched.
+	moved := make([]*resource.State, 0, len(toAdd))
+	for _, res := range toAdd {
+		copied := *res
+		copied.URN = rewriteURN(res.URN)
+		if res.Parent != "" {
+			if res.Parent != sourceRoot && !movingURNs[res.Parent] {
+				copied.Parent = destRoot
+			} else {
+				copied.Parent = rewriteURN(res.Parent)
+			}
+		}
+
+		copied.Dependencies = nil
+		for _, dep := range filterURNs(res.Dependencies, movingURNs) {
+			if dep != sourceRoot || destRoot != "" {
+				copied.Dependencies = append(copied.Dependencies, rewriteURN(dep))
+			}
+		}
+
+		if res.PropertyDependencies != nil {
+			copied.PropertyDependencies = make(map[resource.PropertyKey][]resource.URN, len(res.PropertyDependencies))
+			for key, deps := range res.PropertyDependencies {
+				var rewritten []resource.URN
+				for _, dep := range filterURNs(deps, movingURNs) {
+					if dep != sourceRoot || destRoot != "" {
+						rewritten = append(rewritten, rewriteURN(dep))
+					}
+				}
+				copied.PropertyDependencies[key] = rewritten
+			}
+		}
+
+		if res.DeletedWith != "" {
+			if movingURNs[res.DeletedWith] {
+				copied.DeletedWith = rewriteURN(res.DeletedWith)
+			} else {
+				copied.DeletedWith = ""
+			}
+		}
+
+		if res.Provider != "" {
+			providerRef, err := providers.ParseReference(res.Provider)
+			contract.AssertNoErrorf(err, "failed to parse provider reference from validated checkpoint")
+
+			providerRef, err = providers.NewReference(rewriteURN(providerRef.URN()), providerRef.ID())
+			contract.AssertNoErrorf(err, "failed to generate provider reference from valid reference")
+
+			copied.Provider = providerRef.String()
+		}
+
+		// Aliases refer to URNs in the source stack, and so are meaningless in the destination.
+		copied.Aliases = nil
+
+		moved = append(moved, &copied)
+	}
+
+	source.Resources = remaining
+	dest.Resources = append(dest.Resources, moved...)
+	return moved, nil
+}
@@ -526,3 +526,102 @@ func TestRenameStack(t *testing.T) {
 		assert.Len(t, LocateResource(snap, updatedResourceURN), 1)
 	})
 }
+
+func TestMoveResources(t *testing.T) {
+	t.Parallel()
+
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA)
+	c := NewResource("c", pA)
+	c.Parent = a.URN
+	source := NewSnapshot([]*resource.State{pA, a, b, c})
+	dest := NewSnapshot(nil)
+
+	moved, err := MoveResources(source, dest, deploy.NewUrnTargetsFromUrns([]resource.URN{a.URN}),
+		tokens.Name("dest"), tokens.PackageName("other"), false)
+	require.NoError(t, err)
+	assert.Len(t, moved, 3)
+
+	// The provider is still used by b, so it must remain in the source.
+	assert.Equal(t, []*resource.State{pA, b}, source.Resources)
+
+	// The destination contains the provider, a and its child c.
+	require.Len(t, dest.Resources, 3)
+	for _, res := range dest.Resources {
+		assert.EqualValues(t, "dest", res.URN.Stack())
+		assert.EqualValues(t, "other", res.URN.Project())
+	}
+	movedProvider, movedA, movedC := dest.Resources[0], dest.Resources[1], dest.Resources[2]
+	assert.Equal(t, pA.URN.Name(), movedProvider.URN.Name())
+	assert.Equal(t, movedA.URN, movedC.Parent)
+
+	ref, err := providers.ParseReference(movedA.Provider)
+	require.NoError(t, err)
+	assert.Equal(t, movedProvider.URN, ref.URN())
+
+	assert.NoError(t, source.VerifyIntegrity())
+	assert.NoError(t, dest.VerifyIntegrity())
+}
+
+func TestMoveResourcesMovesUnusedProvider(t *testing.T) {
+	t.Parallel()
+
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	source := NewSnapshot([]*resource.State{pA, a})
+	dest := NewSnapshot(nil)
+
+	_, err := MoveResources(source, dest, deploy.NewUrnTargets([]string{"urn:pulumi:test::test::a:b:c::*"}),
+		tokens.Name("dest"), tokens.PackageName("test"), false)
+	require.NoError(t, err)
+	assert.Empty(t, source.Resources)
+	assert.Len(t, dest.Resources, 2)
+}
+
+func TestMoveResourcesDanglingDependencies(t *testing.T) {
+	t.Parallel()
+
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, a.URN)
+	source := NewSnapshot([]*resource.State{pA, a, b})
+	dest := NewSnapshot(nil)
+
+	targets := deploy.NewUrnTargetsFromUrns([]resource.URN{a.URN})
+	_, err := MoveResources(source, dest, targets, tokens.Name("dest"), tokens.PackageName("test"), false)
+	var danglingErr MoveHasDanglingDependenciesError
+	require.ErrorAs(t, err, &danglingErr)
+	require.Len(t, danglingErr.Dependencies, 1)
+	assert.Equal(t, b, danglingErr.Dependencies[0].From)
+	assert.Equal(t, a.URN, danglingErr.Dependencies[0].To)
+
+	// Nothing should have changed.
+	assert.Len(t, source.Resources, 3)
+	assert.Empty(t, dest.Resources)
+
+	// Forcing the move drops the dangling dependency.
+	_, err = MoveResources(source, dest, targets, tokens.Name("dest"), tokens.PackageName("test"), true)
+	require.NoError(t, err)
+	assert.Equal(t, []*resource.State{pA, b}, source.Resources)
+	assert.Empty(t, b.Dependencies)
+	assert.NoError(t, source.VerifyIntegrity())
+	assert.NoError(t, dest.VerifyIntegrity())
+}
+
+func TestMoveResourcesConflict(t *testing.T) {
+	t.Parallel()
+
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	source := NewSnapshot([]*resource.State{pA, a})
+
+	// The destination has the same stack and project, so the URNs are identical.
+	dest := NewSnapshot([]*resource.State{NewResource("a", nil)})
+
+	_, err := MoveResources(source, dest, deploy.NewUrnTargetsFromUrns([]resource.URN{a.URN}),
+		tokens.Name("test"), tokens.PackageName("test"), false)
+	assert.ErrorContains(t, err, "already exists in the destination stack")
+	assert.Len(t, source.Resources, 2)
+	assert.Len(t, dest.Resources, 1)
+}
@@ -81,6 +81,10 @@ func Main(name string, provMaker func(*HostClient) (pulumirpc.ResourceProviderSe
 				return fmt.Errorf("failed to create resource provider: %v", proverr)
 			}
 			pulumirpc.RegisterResourceProviderServer(srv, prov)
+			// Providers may optionally serve the ResourceDiscovery service, which lists resources to import.
+			if discovery, ok := prov.(pulumirpc.ResourceDiscoveryServer); ok {
+				pulumirpc.RegisterResourceDiscoveryServer(srv, discovery)
+			}
 			return nil
 		},
 		Options: rpcutil.OpenTracingServerInterceptorOptions(nil),
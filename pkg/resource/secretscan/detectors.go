@@ -0,0 +1,188 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretscan
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// EntropyDetector flags long strings whose characters look random, e.g. generated passwords and keys.
+	EntropyDetector = "entropy"
+	// TokenDetector flags strings in the formats of well-known credentials, e.g. AWS access keys.
+	TokenDetector = "tokens"
+	// ConfigDetector flags strings that are equal to one of the stack's secret config values.
+	ConfigDetector = "config"
+
+	// DefaultMinLength is the default length below which strings are not checked for entropy.
+	DefaultMinLength = 20
+	// DefaultMinEntropy is the default Shannon entropy, in bits per character, above which strings are flagged. Strings
+	// that are too short to have this much entropy are flagged by shortStringEntropyFraction instead.
+	DefaultMinEntropy = 4.5
+
+	// shortStringEntropyFraction is the fraction of the highest entropy that a string of its length can have, log2 of
+	// its length, above which strings are flagged if that is less than the minimum entropy. A string of n characters
+	// has at most log2(n) bits of entropy per character, so e.g. strings shorter than 23 characters could otherwise
+	// never reach the default minimum entropy.
+	shortStringEntropyFraction = 0.9
+)
+
+// Detectors is the list of the names of all detectors.
+var Detectors = []string{EntropyDetector, TokenDetector, ConfigDetector}
+
+// Detector checks strings for values that look like secrets.
+type Detector interface {
+	// Name returns the name of the detector, which is reported alongside its findings.
+	Name() string
+	// Detect returns true if the given string looks like a secret.
+	Detect(s string) bool
+}
+
+type entropyDetector struct {
+	minLength  int
+	minEntropy float64
+}
+
+func (d *entropyDetector) Name() string {
+	return EntropyDetector
+}
+
+func (d *entropyDetector) Detect(s string) bool {
+	if len(s) < d.minLength || strings.ContainsAny(s, " \t\r\n") {
+		return false
+	}
+	minEntropy := math.Min(d.minEntropy, shortStringEntropyFraction*math.Log2(float64(utf8.RuneCountInString(s))))
+	return shannonEntropy(s) >= minEntropy
+}
+
+// shannonEntropy returns the Shannon entropy of the characters of s, in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	n := 0
+	for _, c := range s {
+		counts[c]++
+		n++
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(n)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// tokenPatterns are the formats of well-known credentials.
+var tokenPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`),                                  // AWS access key IDs
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),                               // GitHub tokens
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{22,}\b`),                             // GitHub fine-grained tokens
+	regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`),                              // Slack tokens
+	regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`),                                    // Google API keys
+	regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`),                              // Stripe keys
+	regexp.MustCompile(`\bpul-[0-9a-f]{40}\b`),                                         // Pulumi access tokens
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]+`), // JSON web tokens
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`),                           // PEM private keys
+}
+
+type tokenDetector struct{}
+
+func (tokenDetector) Name() string {
+	return TokenDetector
+}
+
+func (tokenDetector) Detect(s string) bool {
+	for _, p := range tokenPatterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+type configDetector struct {
+	secrets map[string]bool
+}
+
+func (d *configDetector) Name() string {
+	return ConfigDetector
+}
+
+func (d *configDetector) Detect(s string) bool {
+	return d.secrets[s]
+}
+
+// Options configures the detectors of a Scanner.
+type Options struct {
+	// Detectors are the names of the detectors to run. All detectors are run if this is empty.
+	Detectors []string
+	// MinLength is the length below which strings are not checked for entropy. Defaults to DefaultMinLength.
+	MinLength int
+	// MinEntropy is the entropy above which strings are flagged, for strings that are long enough to have that much
+	// entropy. Defaults to DefaultMinEntropy.
+	MinEntropy float64
+}
+
+// NewDetectors returns the detectors selected by the given options. configSecrets are the plaintext values of the
+// stack's secret config, which are used by the config detector.
+func NewDetectors(opts Options, configSecrets []string) ([]Detector, error) {
+	names := opts.Detectors
+	if len(names) == 0 {
+		names = Detectors
+	}
+
+	var detectors []Detector
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		switch name {
+		case EntropyDetector:
+			d := &entropyDetector{minLength: opts.MinLength, minEntropy: opts.MinEntropy}
+			if d.minLength <= 0 {
+				d.minLength = DefaultMinLength
+			}
+			if d.minEntropy <= 0 {
+				d.minEntropy = DefaultMinEntropy
+			}
+			detectors = append(detectors, d)
+		case TokenDetector:
+			detectors = append(detectors, tokenDetector{})
+		case ConfigDetector:
+			d := &configDetector{secrets: map[string]bool{}}
+			for _, s := range configSecrets {
+				// Short values such as "true" or "1" are far more likely to match by chance than to be leaks.
+				if len(s) >= 4 {
+					d.secrets[s] = true
+				}
+			}
+			detectors = append(detectors, d)
+		default:
+			known := append([]string(nil), Detectors...)
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown secret detector %q (supported detectors: %s)",
+				name, strings.Join(known, ", "))
+		}
+	}
+	return detectors, nil
+}
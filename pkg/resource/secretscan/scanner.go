@@ -0,0 +1,182 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secretscan finds plaintext property values in resource states that look like secrets, e.g. credentials
+// returned by a provider in outputs that it doesn't mark as secret.
+package secretscan
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// Finding is a plaintext property value that looks like a secret.
+type Finding struct {
+	URN      resource.URN          // the URN of the resource.
+	Input    bool                  // true if the value is an input of the resource rather than an output.
+	Path     resource.PropertyPath // the path of the value within the resource's inputs or outputs.
+	Detector string                // the name of the detector that flagged the value.
+
+	// State is the state in which the value was found. It is only set by ScanState and ScanSnapshot.
+	State *resource.State
+}
+
+func (f Finding) String() string {
+	kind := "output"
+	if f.Input {
+		kind = "input"
+	}
+	return fmt.Sprintf("%s %s (%s)", kind, f.Path, f.Detector)
+}
+
+// Scanner checks property values with a set of detectors.
+type Scanner struct {
+	detectors []Detector
+}
+
+// NewScanner returns a scanner that uses the given detectors.
+func NewScanner(detectors ...Detector) *Scanner {
+	return &Scanner{detectors: detectors}
+}
+
+// ScanProperties returns the findings for the plaintext values in the given properties of a resource, sorted by path.
+func (s *Scanner) ScanProperties(urn resource.URN, input bool, props resource.PropertyMap) []Finding {
+	var findings []Finding
+	for _, k := range props.StableKeys() {
+		s.scanValue(props[k], resource.PropertyPath{string(k)}, func(path resource.PropertyPath, detector string) {
+			findings = append(findings, Finding{URN: urn, Input: input, Path: path, Detector: detector})
+		})
+	}
+	return findings
+}
+
+// ScanState returns the findings for the plaintext inputs and outputs of the given resource.
+func (s *Scanner) ScanState(state *resource.State) []Finding {
+	findings := s.ScanProperties(state.URN, true, state.Inputs)
+	findings = append(findings, s.ScanProperties(state.URN, false, state.Outputs)...)
+	for i := range findings {
+		findings[i].State = state
+	}
+	return findings
+}
+
+// ScanSnapshot returns the findings for the plaintext inputs and outputs of every resource in the snapshot.
+func (s *Scanner) ScanSnapshot(snap *deploy.Snapshot) []Finding {
+	var findings []Finding
+	if snap == nil {
+		return findings
+	}
+	for _, state := range snap.Resources {
+		findings = append(findings, s.ScanState(state)...)
+	}
+	return findings
+}
+
+func (s *Scanner) scanValue(v resource.PropertyValue, path resource.PropertyPath,
+	report func(path resource.PropertyPath, detector string),
+) {
+	switch {
+	case v.IsSecret():
+		// Secret values are already protected.
+	case v.IsString():
+		for _, d := range s.detectors {
+			if d.Detect(v.StringValue()) {
+				report(path, d.Name())
+				return
+			}
+		}
+	case v.IsArray():
+		for i, e := range v.ArrayValue() {
+			s.scanValue(e, append(append(resource.PropertyPath{}, path...), i), report)
+		}
+	case v.IsObject():
+		obj := v.ObjectValue()
+		for _, k := range obj.StableKeys() {
+			s.scanValue(obj[k], append(append(resource.PropertyPath{}, path...), string(k)), report)
+		}
+	case v.IsOutput():
+		if o := v.OutputValue(); !o.Secret {
+			s.scanValue(o.Element, path, report)
+		}
+	}
+}
+
+// MarkSecrets rewrites the states in the snapshot in which the given findings were found so that the top-level
+// properties that contain them are secret. Outputs are also recorded in the resource's AdditionalSecretOutputs.
+//
+// This is a one-off rewrite of the snapshot: the next update builds the state of each resource from its program, so
+// outputs only stay secret if the program also lists them in the resource's additionalSecretOutputs option. Findings
+// that were not produced by ScanState or ScanSnapshot are ignored. It returns the number of properties that were
+// marked.
+func MarkSecrets(snap *deploy.Snapshot, findings []Finding) int {
+	contract.Requiref(snap != nil, "snap", "must not be nil")
+
+	type property struct {
+		state *resource.State
+		input bool
+		key   resource.PropertyKey
+	}
+	marked := map[property]bool{}
+	for _, f := range findings {
+		if f.State == nil {
+			continue
+		}
+		contract.Assertf(len(f.Path) > 0, "finding for %v has an empty path", f.URN)
+		key, ok := f.Path[0].(string)
+		contract.Assertf(ok, "finding for %v has a non-property path %v", f.URN, f.Path)
+		marked[property{f.State, f.Input, resource.PropertyKey(key)}] = true
+	}
+
+	count := 0
+	for _, state := range snap.Resources {
+		for p := range marked {
+			// Match the exact state, e.g. so that a resource that is pending deletion is left alone.
+			if p.state != state {
+				continue
+			}
+
+			props := state.Outputs
+			if p.input {
+				props = state.Inputs
+			}
+			v, ok := props[p.key]
+			if !ok || v.IsSecret() {
+				continue
+			}
+			props[p.key] = resource.MakeSecret(v)
+			count++
+
+			if !p.input && !hasKey(state.AdditionalSecretOutputs, p.key) {
+				state.AdditionalSecretOutputs = append(state.AdditionalSecretOutputs, p.key)
+				sort.Slice(state.AdditionalSecretOutputs, func(i, j int) bool {
+					return state.AdditionalSecretOutputs[i] < state.AdditionalSecretOutputs[j]
+				})
+			}
+		}
+	}
+	return count
+}
+
+func hasKey(keys []resource.PropertyKey, key resource.PropertyKey) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
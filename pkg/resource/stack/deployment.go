@@ -331,6 +331,7 @@ func SerializeResource(res *resource.State, enc config.Encrypter, showSecrets bo
 		DeletedWith:             res.DeletedWith,
 		Created:                 res.Created,
 		Modified:                res.Modified,
+		RetryPolicy:             res.RetryPolicy,
 	}
 
 	if res.CustomTimeouts.IsNotEmpty() {
@@ -513,11 +514,13 @@ func DeserializeResource(res apitype.ResourceV3, dec config.Decrypter, enc confi
 		return nil, fmt.Errorf("resource '%s' has 'custom' false but non-empty ID", res.URN)
 	}
 
-	return resource.NewState(
+	state := resource.NewState(
 		res.Type, res.URN, res.Custom, res.Delete, res.ID,
 		inputs, outputs, res.Parent, res.Protect, res.External, res.Dependencies, res.InitErrors, res.Provider,
 		res.PropertyDependencies, res.PendingReplacement, res.AdditionalSecretOutputs, res.Aliases, res.CustomTimeouts,
-		res.ImportID, res.RetainOnDelete, res.DeletedWith, res.Created, res.Modified), nil
+		res.ImportID, res.RetainOnDelete, res.DeletedWith, res.Created, res.Modified)
+	state.RetryPolicy = res.RetryPolicy
+	return state, nil
 }
 
 // DeserializeOperation hydrates a pending resource/operation pair.
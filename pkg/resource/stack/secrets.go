@@ -18,11 +18,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/age"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/cloud"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/plugin"
 	"github.com/pulumi/pulumi/pkg/v3/secrets/service"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
@@ -50,6 +53,10 @@ func (defaultSecretsProvider) OfType(ty string, state json.RawMessage) (secrets.
 		sm, err = service.NewServiceSecretsManagerFromState(state)
 	case cloud.Type:
 		sm, err = cloud.NewCloudSecretsManagerFromState(state)
+	case age.Type:
+		sm, err = age.NewAgeSecretsManagerFromState(state)
+	case plugin.Type:
+		sm, err = plugin.NewPluginSecretsManagerFromState(state)
 	default:
 		return nil, fmt.Errorf("no known secrets provider for type %q", ty)
 	}
@@ -88,6 +95,14 @@ func (csm *cachingSecretsManager) State() interface{} {
 	return csm.manager.State()
 }
 
+// Close closes the underlying secrets manager if it holds resources that must be released.
+func (csm *cachingSecretsManager) Close() error {
+	if c, ok := csm.manager.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 func (csm *cachingSecretsManager) Encrypter() (config.Encrypter, error) {
 	enc, err := csm.manager.Encrypter()
 	if err != nil {
@@ -0,0 +1,439 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statequery
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Result is the result of a query.
+type Result struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Objects returns the rows of the result as objects that map the names of the columns to their values.
+func (r *Result) Objects() []map[string]interface{} {
+	objects := make([]map[string]interface{}, len(r.Rows))
+	for i, row := range r.Rows {
+		obj := make(map[string]interface{}, len(r.Columns))
+		for j, column := range r.Columns {
+			obj[column] = row[j]
+		}
+		objects[i] = obj
+	}
+	return objects
+}
+
+// binding maps the aliases of the tables of a query to the rows that are being considered.
+type binding map[string]map[string]interface{}
+
+// Run runs the query against the given tables.
+func (q *Query) Run(tables map[string]*Table) (*Result, error) {
+	refs := append([]tableRef{q.from}, joinRefs(q.joins)...)
+	columns := make(map[string][]string, len(refs))
+	var aliases []string
+	for _, ref := range refs {
+		table, ok := tables[ref.table]
+		if !ok {
+			return nil, fmt.Errorf("unknown table '%s'", ref.table)
+		}
+		if _, has := columns[ref.alias]; has {
+			return nil, fmt.Errorf("table alias '%s' is used more than once", ref.alias)
+		}
+		columns[ref.alias] = table.Columns
+		aliases = append(aliases, ref.alias)
+	}
+
+	// Resolve the paths in the query to the tables' columns.
+	var exprs []expr
+	for _, item := range q.selects {
+		exprs = append(exprs, item.expr)
+	}
+	for _, j := range q.joins {
+		exprs = append(exprs, j.on)
+	}
+	exprs = append(exprs, q.where)
+	for _, item := range q.orderBy {
+		exprs = append(exprs, item.expr)
+	}
+	for _, e := range exprs {
+		if err := bindExpr(e, aliases, columns); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows []binding
+	for _, row := range tables[q.from.table].Rows {
+		rows = append(rows, binding{q.from.alias: row})
+	}
+	for _, j := range q.joins {
+		var joined []binding
+		for _, b := range rows {
+			for _, row := range tables[j.table].Rows {
+				nb := make(binding, len(b)+1)
+				for alias, r := range b {
+					nb[alias] = r
+				}
+				nb[j.alias] = row
+				if isTrue(eval(j.on, nb)) {
+					joined = append(joined, nb)
+				}
+			}
+		}
+		rows = joined
+	}
+
+	if q.where != nil {
+		var filtered []binding
+		for _, b := range rows {
+			if isTrue(eval(q.where, b)) {
+				filtered = append(filtered, b)
+			}
+		}
+		rows = filtered
+	}
+
+	if len(q.orderBy) > 0 {
+		sort.SliceStable(rows, func(i, j int) bool {
+			for _, item := range q.orderBy {
+				c := compareValues(eval(item.expr, rows[i]), eval(item.expr, rows[j]))
+				if c == 0 {
+					continue
+				}
+				if item.desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+
+	if q.limit >= 0 && len(rows) > q.limit {
+		rows = rows[:q.limit]
+	}
+
+	result := &Result{}
+	if q.selects == nil {
+		// Select all columns, qualified by their tables' aliases if there is more than one table.
+		for _, alias := range aliases {
+			for _, column := range columns[alias] {
+				name := column
+				if len(aliases) > 1 {
+					name = alias + "." + column
+				}
+				result.Columns = append(result.Columns, name)
+			}
+		}
+		for _, b := range rows {
+			var values []interface{}
+			for _, alias := range aliases {
+				for _, column := range columns[alias] {
+					values = append(values, b[alias][column])
+				}
+			}
+			result.Rows = append(result.Rows, values)
+		}
+		return result, nil
+	}
+
+	for _, item := range q.selects {
+		result.Columns = append(result.Columns, item.name)
+	}
+	for _, b := range rows {
+		values := make([]interface{}, len(q.selects))
+		for i, item := range q.selects {
+			values[i] = eval(item.expr, b)
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, nil
+}
+
+func joinRefs(joins []join) []tableRef {
+	refs := make([]tableRef, len(joins))
+	for i, j := range joins {
+		refs[i] = j.tableRef
+	}
+	return refs
+}
+
+// bindExpr resolves the paths in the given expression to the columns of the tables with the given aliases. Paths
+// that start with an alias refer to the table with that alias. Other paths refer to the only table that has a column
+// named by their first segment.
+func bindExpr(e expr, aliases []string, columns map[string][]string) error {
+	switch e := e.(type) {
+	case *pathExpr:
+		first := e.segments[0].(string)
+		if tableColumns, ok := columns[first]; ok {
+			e.alias = first
+			if len(e.segments) == 1 {
+				// The path refers to the whole row.
+				return nil
+			}
+			column, ok := e.segments[1].(string)
+			if !ok || !contains(tableColumns, column) {
+				return fmt.Errorf("unknown column '%v' of table '%s'", e.segments[1], first)
+			}
+			e.column, e.rest = column, e.segments[2:]
+			return nil
+		}
+
+		var matches []string
+		for _, alias := range aliases {
+			if contains(columns[alias], first) {
+				matches = append(matches, alias)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return fmt.Errorf("unknown column '%s'", first)
+		case 1:
+			e.alias, e.column, e.rest = matches[0], first, e.segments[1:]
+			return nil
+		default:
+			return fmt.Errorf("column '%s' is ambiguous, qualify it with one of the table aliases %s",
+				first, strings.Join(matches, ", "))
+		}
+	case *notExpr:
+		return bindExpr(e.operand, aliases, columns)
+	case *binaryExpr:
+		if err := bindExpr(e.left, aliases, columns); err != nil {
+			return err
+		}
+		return bindExpr(e.right, aliases, columns)
+	case *isNullExpr:
+		return bindExpr(e.operand, aliases, columns)
+	case *likeExpr:
+		return bindExpr(e.operand, aliases, columns)
+	case *inExpr:
+		if err := bindExpr(e.operand, aliases, columns); err != nil {
+			return err
+		}
+		for _, item := range e.list {
+			if err := bindExpr(item, aliases, columns); err != nil {
+				return err
+			}
+		}
+		if e.collection != nil {
+			return bindExpr(e.collection, aliases, columns)
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// eval evaluates the given expression for the given rows. Paths that don't exist evaluate to nil, as do comparisons
+// of values of different types.
+func eval(e expr, b binding) interface{} {
+	switch e := e.(type) {
+	case nil:
+		return nil
+	case *literalExpr:
+		return e.value
+	case *pathExpr:
+		row := b[e.alias]
+		if e.column == "" {
+			obj := make(map[string]interface{}, len(row))
+			for k, v := range row {
+				obj[k] = v
+			}
+			return obj
+		}
+		v := row[e.column]
+		for _, segment := range e.rest {
+			switch segment := segment.(type) {
+			case string:
+				obj, ok := v.(map[string]interface{})
+				if !ok {
+					return nil
+				}
+				v = obj[segment]
+			case int:
+				items, ok := v.([]interface{})
+				if !ok || segment < 0 || segment >= len(items) {
+					return nil
+				}
+				v = items[segment]
+			}
+		}
+		return v
+	case *notExpr:
+		return !isTrue(eval(e.operand, b))
+	case *binaryExpr:
+		switch e.op {
+		case "AND":
+			return isTrue(eval(e.left, b)) && isTrue(eval(e.right, b))
+		case "OR":
+			return isTrue(eval(e.left, b)) || isTrue(eval(e.right, b))
+		case "=":
+			return equalValues(eval(e.left, b), eval(e.right, b))
+		case "!=":
+			return !equalValues(eval(e.left, b), eval(e.right, b))
+		}
+		left, right := eval(e.left, b), eval(e.right, b)
+		if !orderable(left, right) {
+			return nil
+		}
+		c := compareValues(left, right)
+		switch e.op {
+		case "<":
+			return c < 0
+		case "<=":
+			return c <= 0
+		case ">":
+			return c > 0
+		default:
+			return c >= 0
+		}
+	case *isNullExpr:
+		return (eval(e.operand, b) == nil) != e.not
+	case *likeExpr:
+		s, ok := eval(e.operand, b).(string)
+		if !ok {
+			return nil
+		}
+		return e.pattern.MatchString(s) != e.not
+	case *inExpr:
+		v := eval(e.operand, b)
+		var items []interface{}
+		if e.collection != nil {
+			collection, ok := eval(e.collection, b).([]interface{})
+			if !ok {
+				return nil
+			}
+			items = collection
+		} else {
+			for _, item := range e.list {
+				items = append(items, eval(item, b))
+			}
+		}
+		for _, item := range items {
+			if equalValues(v, item) {
+				return !e.not
+			}
+		}
+		return e.not
+	}
+	panic(fmt.Sprintf("unexpected expression %T", e))
+}
+
+func isTrue(v interface{}) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
+
+func equalValues(a, b interface{}) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// orderable returns true if the given values can be ordered by <, <=, > and >=, i.e. if they are both numbers or
+// both strings.
+func orderable(a, b interface{}) bool {
+	switch a.(type) {
+	case float64:
+		_, ok := b.(float64)
+		return ok
+	case string:
+		_, ok := b.(string)
+		return ok
+	default:
+		return false
+	}
+}
+
+// compareValues orders values of all types. Values of different types are ordered by type: nil first, then bools,
+// numbers, strings, and other values by their JSON representations.
+func compareValues(a, b interface{}) int {
+	rank := func(v interface{}) int {
+		switch v.(type) {
+		case nil:
+			return 0
+		case bool:
+			return 1
+		case float64:
+			return 2
+		case string:
+			return 3
+		default:
+			return 4
+		}
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+
+	switch a := a.(type) {
+	case nil:
+		return 0
+	case bool:
+		switch {
+		case a == b.(bool):
+			return 0
+		case !a:
+			return -1
+		default:
+			return 1
+		}
+	case float64:
+		switch bf := b.(float64); {
+		case a < bf:
+			return -1
+		case a > bf:
+			return 1
+		default:
+			return 0
+		}
+	case string:
+		return strings.Compare(a, b.(string))
+	default:
+		ja, _ := json.Marshal(a)
+		jb, _ := json.Marshal(b)
+		return strings.Compare(string(ja), string(jb))
+	}
+}
+
+// likePattern returns a regular expression for the given LIKE pattern, in which % matches any sequence of characters
+// and _ matches any single character.
+func likePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^(?s)")
+	for _, c := range pattern {
+		switch c {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,602 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statequery
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenKeyword
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	// text is the text of the token. It is the upper-cased keyword for keywords, and the unquoted value for strings.
+	text string
+	// raw is the text of the token as it appears in the query.
+	raw string
+	pos int
+}
+
+var keywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "JOIN": true, "ON": true, "AS": true,
+	"AND": true, "OR": true, "NOT": true, "IN": true, "IS": true, "NULL": true, "LIKE": true,
+	"ORDER": true, "BY": true, "ASC": true, "DESC": true, "LIMIT": true, "TRUE": true, "FALSE": true,
+}
+
+// puncts are the punctuation tokens, with the longer ones first so that they are preferred.
+var puncts = []string{"==", "!=", "<>", "<=", ">=", "=", "<", ">", ",", ".", "(", ")", "[", "]", "*", "-"}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	for pos := 0; pos < len(input); {
+		c := rune(input[pos])
+		switch {
+		case unicode.IsSpace(c):
+			pos++
+		case c == '_' || unicode.IsLetter(c):
+			end := pos + 1
+			for end < len(input) && (input[end] == '_' || unicode.IsLetter(rune(input[end])) ||
+				unicode.IsDigit(rune(input[end]))) {
+				end++
+			}
+			raw := input[pos:end]
+			if upper := strings.ToUpper(raw); keywords[upper] {
+				tokens = append(tokens, token{kind: tokenKeyword, text: upper, raw: raw, pos: pos})
+			} else {
+				tokens = append(tokens, token{kind: tokenIdent, text: raw, raw: raw, pos: pos})
+			}
+			pos = end
+		case unicode.IsDigit(c):
+			end := pos + 1
+			for end < len(input) && (unicode.IsDigit(rune(input[end])) || input[end] == '.') {
+				end++
+			}
+			raw := input[pos:end]
+			tokens = append(tokens, token{kind: tokenNumber, text: raw, raw: raw, pos: pos})
+			pos = end
+		case c == '\'' || c == '"':
+			var b strings.Builder
+			end := pos + 1
+			for ; end < len(input) && rune(input[end]) != c; end++ {
+				if input[end] == '\\' && end+1 < len(input) {
+					end++
+				}
+				b.WriteByte(input[end])
+			}
+			if end == len(input) {
+				return nil, fmt.Errorf("unterminated string at position %d", pos)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: b.String(), raw: input[pos : end+1], pos: pos})
+			pos = end + 1
+		default:
+			matched := false
+			for _, p := range puncts {
+				if strings.HasPrefix(input[pos:], p) {
+					tokens = append(tokens, token{kind: tokenPunct, text: p, raw: p, pos: pos})
+					pos += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, pos)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// Query is a parsed query. Queries have the form
+//
+//	SELECT <expr> [AS <name>], ... | *
+//	FROM <table> [[AS] <alias>]
+//	[JOIN <table> [[AS] <alias>] ON <expr>]...
+//	[WHERE <expr>]
+//	[ORDER BY <expr> [ASC | DESC], ...]
+//	[LIMIT <n>]
+//
+// Expressions are literals (strings, numbers, true, false and null), paths into the columns of the tables (e.g.
+// `outputs.tags["Name"]` or `r.dependencies[0]`), and the operators `=`, `!=`, `<`, `<=`, `>`, `>=`, `AND`, `OR`,
+// `NOT`, `IS [NOT] NULL`, `[NOT] LIKE <pattern>` and `[NOT] IN (<expr>, ...)` or `[NOT] IN <array>`. Queries that
+// don't start with SELECT are the WHERE clause of a query of the URNs and types of resources.
+type Query struct {
+	// selects are the columns of the result, or nil for all columns.
+	selects []selectItem
+	from    tableRef
+	joins   []join
+	where   expr
+	orderBy []orderItem
+	// limit is the maximum number of rows of the result, or -1 for no limit.
+	limit int
+}
+
+type selectItem struct {
+	expr expr
+	name string
+}
+
+type tableRef struct {
+	table string
+	alias string
+}
+
+type join struct {
+	tableRef
+	on expr
+}
+
+type orderItem struct {
+	expr expr
+	desc bool
+}
+
+type expr interface {
+	isExpr()
+}
+
+type literalExpr struct {
+	value interface{}
+}
+
+// pathExpr is a path into a column of a table. Its segments are strings for properties and ints for indices.
+type pathExpr struct {
+	segments []interface{}
+
+	// alias and column are the table alias and column that the path refers to, and rest is the path within the
+	// column. They are set when the query is bound to tables.
+	alias  string
+	column string
+	rest   []interface{}
+}
+
+type notExpr struct {
+	operand expr
+}
+
+type binaryExpr struct {
+	op          string
+	left, right expr
+}
+
+type isNullExpr struct {
+	operand expr
+	not     bool
+}
+
+type likeExpr struct {
+	operand expr
+	pattern *regexp.Regexp
+	not     bool
+}
+
+type inExpr struct {
+	operand expr
+	// list is the list of values to compare with, or nil if the operand is compared with the items of collection.
+	list       []expr
+	collection expr
+	not        bool
+}
+
+func (*literalExpr) isExpr() {}
+func (*pathExpr) isExpr()    {}
+func (*notExpr) isExpr()     {}
+func (*binaryExpr) isExpr()  {}
+func (*isNullExpr) isExpr()  {}
+func (*likeExpr) isExpr()    {}
+func (*inExpr) isExpr()      {}
+
+// Parse parses the given query.
+func Parse(input string) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{input: input, tokens: tokens}
+
+	var q *Query
+	if p.peek().kind == tokenKeyword && p.peek().text == "SELECT" {
+		q, err = p.parseQuery()
+	} else {
+		q = &Query{
+			selects: []selectItem{
+				{expr: &pathExpr{segments: []interface{}{"urn"}}, name: "urn"},
+				{expr: &pathExpr{segments: []interface{}{"type"}}, name: "type"},
+			},
+			from:  tableRef{table: ResourcesTable, alias: ResourcesTable},
+			limit: -1,
+		}
+		q.where, err = p.parseExpr()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.unexpected(t)
+	}
+	return q, nil
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// keyword consumes the next token if it is the given keyword.
+func (p *parser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == tokenKeyword && t.text == kw {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// punct consumes the next token if it is the given punctuation.
+func (p *parser) punct(s string) bool {
+	if t := p.peek(); t.kind == tokenPunct && t.text == s {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(kw string) error {
+	if !p.keyword(kw) {
+		return fmt.Errorf("expected %s at position %d", kw, p.peek().pos)
+	}
+	return nil
+}
+
+func (p *parser) expectPunct(s string) error {
+	if !p.punct(s) {
+		return fmt.Errorf("expected '%s' at position %d", s, p.peek().pos)
+	}
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of query")
+	}
+	return fmt.Errorf("unexpected '%s' at position %d", t.raw, t.pos)
+}
+
+func (p *parser) parseQuery() (*Query, error) {
+	q := &Query{limit: -1}
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	if !p.punct("*") {
+		for {
+			start := p.peek().pos
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			name := strings.TrimSpace(p.input[start:p.peek().pos])
+			if p.keyword("AS") {
+				t := p.next()
+				if t.kind != tokenIdent && t.kind != tokenString {
+					return nil, p.unexpected(t)
+				}
+				name = t.text
+			}
+			q.selects = append(q.selects, selectItem{expr: e, name: name})
+			if !p.punct(",") {
+				break
+			}
+		}
+	}
+
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	from, err := p.parseTableRef()
+	if err != nil {
+		return nil, err
+	}
+	q.from = from
+
+	for p.keyword("JOIN") {
+		ref, err := p.parseTableRef()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("ON"); err != nil {
+			return nil, err
+		}
+		on, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		q.joins = append(q.joins, join{tableRef: ref, on: on})
+	}
+
+	if p.keyword("WHERE") {
+		if q.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.keyword("ORDER") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			item := orderItem{expr: e}
+			if p.keyword("DESC") {
+				item.desc = true
+			} else {
+				p.keyword("ASC")
+			}
+			q.orderBy = append(q.orderBy, item)
+			if !p.punct(",") {
+				break
+			}
+		}
+	}
+
+	if p.keyword("LIMIT") {
+		t := p.next()
+		limit, err := strconv.Atoi(t.text)
+		if t.kind != tokenNumber || err != nil {
+			return nil, fmt.Errorf("expected the number of rows after LIMIT at position %d", t.pos)
+		}
+		q.limit = limit
+	}
+
+	return q, nil
+}
+
+func (p *parser) parseTableRef() (tableRef, error) {
+	t := p.next()
+	if t.kind != tokenIdent {
+		return tableRef{}, p.unexpected(t)
+	}
+	ref := tableRef{table: t.text, alias: t.text}
+	if p.keyword("AS") || p.peek().kind == tokenIdent {
+		alias := p.next()
+		if alias.kind != tokenIdent {
+			return tableRef{}, p.unexpected(alias)
+		}
+		ref.alias = alias.text
+	}
+	return ref, nil
+}
+
+func (p *parser) parseExpr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.keyword("NOT") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind == tokenPunct {
+		switch t.text {
+		case "=", "==", "!=", "<>", "<", "<=", ">", ">=":
+			p.next()
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			op := t.text
+			switch op {
+			case "==":
+				op = "="
+			case "<>":
+				op = "!="
+			}
+			return &binaryExpr{op: op, left: left, right: right}, nil
+		}
+	}
+
+	if p.keyword("IS") {
+		not := p.keyword("NOT")
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullExpr{operand: left, not: not}, nil
+	}
+
+	not := p.keyword("NOT")
+	switch {
+	case p.keyword("LIKE"):
+		t := p.next()
+		if t.kind != tokenString {
+			return nil, fmt.Errorf("expected a string pattern after LIKE at position %d", t.pos)
+		}
+		return &likeExpr{operand: left, pattern: likePattern(t.text), not: not}, nil
+	case p.keyword("IN"):
+		in := &inExpr{operand: left, not: not}
+		if p.punct("(") {
+			for {
+				item, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				in.list = append(in.list, item)
+				if !p.punct(",") {
+					break
+				}
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+		} else if in.collection, err = p.parseOperand(); err != nil {
+			return nil, err
+		}
+		return in, nil
+	case not:
+		return nil, fmt.Errorf("expected LIKE or IN after NOT at position %d", p.peek().pos)
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseOperand() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &literalExpr{value: t.text}, nil
+	case tokenNumber:
+		return parseNumber(t)
+	case tokenKeyword:
+		switch t.text {
+		case "TRUE":
+			return &literalExpr{value: true}, nil
+		case "FALSE":
+			return &literalExpr{value: false}, nil
+		case "NULL":
+			return &literalExpr{value: nil}, nil
+		}
+	case tokenPunct:
+		switch t.text {
+		case "(":
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		case "-":
+			n := p.next()
+			if n.kind != tokenNumber {
+				return nil, p.unexpected(n)
+			}
+			e, err := parseNumber(n)
+			if err != nil {
+				return nil, err
+			}
+			e.value = -e.value.(float64)
+			return e, nil
+		}
+	case tokenIdent:
+		return p.parsePath(t)
+	}
+	return nil, p.unexpected(t)
+}
+
+func parseNumber(t token) (*literalExpr, error) {
+	n, err := strconv.ParseFloat(t.text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number '%s' at position %d", t.raw, t.pos)
+	}
+	return &literalExpr{value: n}, nil
+}
+
+func (p *parser) parsePath(first token) (*pathExpr, error) {
+	path := &pathExpr{segments: []interface{}{first.text}}
+	for {
+		switch {
+		case p.punct("."):
+			// Keywords are allowed as property names after dots.
+			t := p.next()
+			if t.kind != tokenIdent && t.kind != tokenKeyword {
+				return nil, p.unexpected(t)
+			}
+			path.segments = append(path.segments, t.raw)
+		case p.punct("["):
+			t := p.next()
+			switch t.kind {
+			case tokenString:
+				path.segments = append(path.segments, t.text)
+			case tokenNumber:
+				index, err := strconv.Atoi(t.text)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index '%s' at position %d", t.raw, t.pos)
+				}
+				path.segments = append(path.segments, index)
+			default:
+				return nil, p.unexpected(t)
+			}
+			if err := p.expectPunct("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return path, nil
+		}
+	}
+}
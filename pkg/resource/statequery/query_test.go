@@ -0,0 +1,145 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statequery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func testSnapshot() *deploy.Snapshot {
+	urn := func(typ, name string) resource.URN {
+		return resource.NewURN("dev", "proj", resource.RootStackType, tokens.Type(typ), tokens.QName(name))
+	}
+	bucket := func(name, acl string) *resource.State {
+		return &resource.State{
+			URN:    urn("aws:s3/bucket:Bucket", name),
+			Type:   "aws:s3/bucket:Bucket",
+			Custom: true,
+			ID:     resource.ID(name + "-1234"),
+			Outputs: resource.PropertyMap{
+				"acl":    resource.NewStringProperty(acl),
+				"tags":   resource.NewObjectProperty(resource.PropertyMap{"team": resource.NewStringProperty("web")}),
+				"secret": resource.MakeSecret(resource.NewStringProperty("hunter2")),
+			},
+		}
+	}
+	logs, site, assets := bucket("logs", "private"), bucket("site", "public-read"), bucket("assets", "public-read")
+	policy := &resource.State{
+		URN:          urn("aws:s3/bucketPolicy:BucketPolicy", "site-policy"),
+		Type:         "aws:s3/bucketPolicy:BucketPolicy",
+		Custom:       true,
+		ID:           "site-policy",
+		Inputs:       resource.PropertyMap{"bucket": resource.NewStringProperty("site-1234")},
+		Dependencies: []resource.URN{site.URN},
+		PropertyDependencies: map[resource.PropertyKey][]resource.URN{
+			"bucket": {site.URN},
+		},
+	}
+	return &deploy.Snapshot{Resources: []*resource.State{logs, site, assets, policy}}
+}
+
+func runQuery(t *testing.T, query string) *Result {
+	t.Helper()
+	q, err := Parse(query)
+	require.NoError(t, err)
+	result, err := q.Run(SnapshotTables(testSnapshot(), false))
+	require.NoError(t, err)
+	return result
+}
+
+func TestQueryFilter(t *testing.T) {
+	t.Parallel()
+
+	result := runQuery(t, `SELECT name, outputs.tags.team AS team FROM resources
+		WHERE type = 'aws:s3/bucket:Bucket' AND outputs.acl LIKE 'public%' ORDER BY name`)
+	assert.Equal(t, []string{"name", "team"}, result.Columns)
+	assert.Equal(t, [][]interface{}{{"assets", "web"}, {"site", "web"}}, result.Rows)
+
+	// Queries that don't start with SELECT filter the resources.
+	result = runQuery(t, `outputs["acl"] = "private"`)
+	assert.Equal(t, []string{"urn", "type"}, result.Columns)
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, "aws:s3/bucket:Bucket", result.Rows[0][1])
+
+	result = runQuery(t, `SELECT name FROM resources WHERE name IN ('logs', 'site') AND NOT name = 'logs'`)
+	assert.Equal(t, [][]interface{}{{"site"}}, result.Rows)
+
+	result = runQuery(t, `SELECT name FROM resources WHERE inputs.bucket IS NOT NULL`)
+	assert.Equal(t, [][]interface{}{{"site-policy"}}, result.Rows)
+
+	result = runQuery(t, `SELECT name FROM resources ORDER BY name DESC LIMIT 2`)
+	assert.Equal(t, [][]interface{}{{"site-policy"}, {"site"}}, result.Rows)
+
+	// Secrets are hidden unless requested.
+	result = runQuery(t, `SELECT outputs.secret FROM resources WHERE name = 'logs'`)
+	assert.Equal(t, [][]interface{}{{"[secret]"}}, result.Rows)
+	q, err := Parse(`SELECT outputs.secret AS secret FROM resources WHERE name = 'logs'`)
+	require.NoError(t, err)
+	result, err = q.Run(SnapshotTables(testSnapshot(), true))
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"secret": "hunter2"}}, result.Objects())
+}
+
+func TestQueryJoin(t *testing.T) {
+	t.Parallel()
+
+	// Join resources with the resources that they depend on.
+	result := runQuery(t, `SELECT r.name, d.name, d.outputs.acl FROM resources r
+		JOIN resources d ON d.urn IN r.dependencies`)
+	assert.Equal(t, []string{"r.name", "d.name", "d.outputs.acl"}, result.Columns)
+	assert.Equal(t, [][]interface{}{{"site-policy", "site", "public-read"}}, result.Rows)
+
+	// Join through the dependencies table to find the policies of public buckets.
+	result = runQuery(t, `SELECT b.name, p.name FROM resources b
+		JOIN dependencies d ON d.dependency = b.urn
+		JOIN resources p ON p.urn = d.urn
+		WHERE b.outputs.acl = 'public-read' AND p.type LIKE '%BucketPolicy'`)
+	assert.Equal(t, [][]interface{}{{"site", "site-policy"}}, result.Rows)
+
+	result = runQuery(t, `SELECT * FROM dependencies`)
+	assert.Equal(t, []string{"urn", "dependency", "properties"}, result.Columns)
+	require.Len(t, result.Rows, 1)
+	assert.Equal(t, []interface{}{"bucket"}, result.Rows[0][2])
+}
+
+func TestQueryErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		`SELECT name FROM`:                                     "unexpected end of query",
+		`SELECT name FROM resources WHERE name = 'x`:           "unterminated string at position 40",
+		`SELECT name resources`:                                "expected FROM at position 12",
+		`name LIKE 5`:                                          "expected a string pattern after LIKE at position 10",
+		`SELECT name FROM buckets`:                             "unknown table 'buckets'",
+		`SELECT nom FROM resources`:                            "unknown column 'nom'",
+		`SELECT r.nom FROM resources r`:                        "unknown column 'nom' of table 'r'",
+		`SELECT urn FROM resources r JOIN dependencies d ON 1`: "column 'urn' is ambiguous",
+		`SELECT urn FROM resources JOIN resources ON true`:     "table alias 'resources' is used more than once",
+	}
+	for query, expected := range cases {
+		q, err := Parse(query)
+		if err == nil {
+			_, err = q.Run(SnapshotTables(testSnapshot(), false))
+		}
+		assert.ErrorContains(t, err, expected, query)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package statequery implements a small SQL-like query language over the resources in a snapshot, so that questions
+// about a stack, e.g. which of its buckets are public, can be answered from its state alone.
+package statequery
+
+import (
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+const (
+	// ResourcesTable has a row for each resource in the snapshot.
+	ResourcesTable = "resources"
+	// DependenciesTable has a row for each resource that a resource depends on.
+	DependenciesTable = "dependencies"
+)
+
+// Table is a table of rows, which map the names of the table's columns to plain values: nil, bools, float64s,
+// strings, []interface{}s and map[string]interface{}s.
+type Table struct {
+	Columns []string
+	Rows    []map[string]interface{}
+}
+
+// SnapshotTables returns the tables of the given snapshot, keyed by name. Secret values are replaced by "[secret]"
+// unless showSecrets is true.
+func SnapshotTables(snap *deploy.Snapshot, showSecrets bool) map[string]*Table {
+	resources := &Table{Columns: []string{
+		"urn", "type", "name", "id", "custom", "provider", "parent", "protect", "external", "delete",
+		"inputs", "outputs", "dependencies",
+	}}
+	dependencies := &Table{Columns: []string{"urn", "dependency", "properties"}}
+
+	if snap != nil {
+		for _, res := range snap.Resources {
+			deps := make([]interface{}, len(res.Dependencies))
+			for i, dep := range res.Dependencies {
+				deps[i] = string(dep)
+			}
+			resources.Rows = append(resources.Rows, map[string]interface{}{
+				"urn":          string(res.URN),
+				"type":         string(res.Type),
+				"name":         string(res.URN.Name()),
+				"id":           string(res.ID),
+				"custom":       res.Custom,
+				"provider":     res.Provider,
+				"parent":       string(res.Parent),
+				"protect":      res.Protect,
+				"external":     res.External,
+				"delete":       res.Delete,
+				"inputs":       plainObject(res.Inputs, showSecrets),
+				"outputs":      plainObject(res.Outputs, showSecrets),
+				"dependencies": deps,
+			})
+
+			dependencies.Rows = append(dependencies.Rows, dependencyRows(res)...)
+		}
+	}
+
+	return map[string]*Table{
+		ResourcesTable:    resources,
+		DependenciesTable: dependencies,
+	}
+}
+
+// dependencyRows returns a row for each resource that the given resource depends on, along with the names of the
+// properties whose values depend on it.
+func dependencyRows(res *resource.State) []map[string]interface{} {
+	var deps []resource.URN
+	properties := make(map[resource.URN][]interface{})
+	for _, dep := range res.Dependencies {
+		if _, has := properties[dep]; !has {
+			deps = append(deps, dep)
+			properties[dep] = []interface{}{}
+		}
+	}
+
+	keys := make([]string, 0, len(res.PropertyDependencies))
+	for k := range res.PropertyDependencies {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, dep := range res.PropertyDependencies[resource.PropertyKey(k)] {
+			if _, has := properties[dep]; !has {
+				deps = append(deps, dep)
+			}
+			properties[dep] = append(properties[dep], k)
+		}
+	}
+
+	rows := make([]map[string]interface{}, len(deps))
+	for i, dep := range deps {
+		rows[i] = map[string]interface{}{
+			"urn":        string(res.URN),
+			"dependency": string(dep),
+			"properties": properties[dep],
+		}
+	}
+	return rows
+}
+
+func plainObject(props resource.PropertyMap, showSecrets bool) map[string]interface{} {
+	obj := make(map[string]interface{}, len(props))
+	for k, v := range props {
+		obj[string(k)] = plainValue(v, showSecrets)
+	}
+	return obj
+}
+
+// plainValue returns the given property value as a plain value. Unknown values are nil, and resource references are
+// the URNs of the resources that they refer to.
+func plainValue(v resource.PropertyValue, showSecrets bool) interface{} {
+	switch {
+	case v.IsNull(), v.IsComputed():
+		return nil
+	case v.IsBool():
+		return v.BoolValue()
+	case v.IsNumber():
+		return v.NumberValue()
+	case v.IsString():
+		return v.StringValue()
+	case v.IsArray():
+		items := make([]interface{}, len(v.ArrayValue()))
+		for i, item := range v.ArrayValue() {
+			items[i] = plainValue(item, showSecrets)
+		}
+		return items
+	case v.IsObject():
+		return plainObject(v.ObjectValue(), showSecrets)
+	case v.IsSecret():
+		if !showSecrets {
+			return "[secret]"
+		}
+		return plainValue(v.SecretValue().Element, showSecrets)
+	case v.IsOutput():
+		output := v.OutputValue()
+		if !output.Known {
+			return nil
+		}
+		if output.Secret && !showSecrets {
+			return "[secret]"
+		}
+		return plainValue(output.Element, showSecrets)
+	case v.IsResourceReference():
+		return string(v.ResourceReferenceValue().URN)
+	default:
+		// Assets and archives.
+		return v.Mappable()
+	}
+}
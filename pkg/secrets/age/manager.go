@@ -0,0 +1,276 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package age implements support for a local secrets manager that wraps its data key for multiple age recipients, so
+// that stack secrets can be decrypted by any one of several keys without sharing a passphrase.
+package age
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	filippoage "filippo.io/age"
+
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// Type is the type of secrets managed by this secrets provider
+const Type = "age"
+
+// Scheme is the prefix of age secrets providers, which is followed by the path of a file listing the recipients, e.g.
+// `age://recipients.txt`. Relative paths are relative to the project's root directory.
+const Scheme = "age://"
+
+// IdentityFileEnvVar names the file that holds the age identities used to decrypt the data key.
+const IdentityFileEnvVar = "PULUMI_AGE_IDENTITY_FILE"
+
+// IsAgeURL returns true if the given secrets provider is an age secrets provider.
+func IsAgeURL(secretsProvider string) bool {
+	return strings.HasPrefix(secretsProvider, Scheme)
+}
+
+type ageSecretsManagerState struct {
+	URL string `json:"url"`
+	// EncryptedKey is the data key, encrypted for each of the recipients as an age file.
+	EncryptedKey []byte `json:"encryptedkey"`
+}
+
+// errNoIdentity is returned when none of the available identities is a recipient of the data key.
+var errNoIdentity = errors.New("no identity matched any of the recipients")
+
+// recipientsPath returns the path of the recipients file named by the given secrets provider. Relative paths are
+// relative to the root of the current project, so that the stack's secrets provider works from any directory within
+// the project.
+func recipientsPath(secretsProvider string) (string, error) {
+	path := strings.TrimPrefix(secretsProvider, Scheme)
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+
+	projectPath, err := workspace.DetectProjectPath()
+	if err != nil {
+		return "", err
+	}
+	if projectPath == "" {
+		return path, nil
+	}
+	return filepath.Join(filepath.Dir(projectPath), path), nil
+}
+
+// readRecipients reads the recipients listed in the given file.
+func readRecipients(path string) ([]filippoage.Recipient, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading age recipients: %w", err)
+	}
+	defer contract.IgnoreClose(f)
+
+	recipients, err := filippoage.ParseRecipients(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading age recipients from %s: %w", path, err)
+	}
+	return recipients, nil
+}
+
+// readIdentities reads the identities from the file named by PULUMI_AGE_IDENTITY_FILE or, if that is not set, from
+// ~/.pulumi/age/keys.txt.
+func readIdentities() ([]filippoage.Identity, error) {
+	path, ok := os.LookupEnv(IdentityFileEnvVar)
+	if !ok || path == "" {
+		defaultPath, err := workspace.GetPulumiPath("age", "keys.txt")
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errNoIdentity
+		}
+		return nil, fmt.Errorf("reading age identities: %w", err)
+	}
+	defer contract.IgnoreClose(f)
+
+	identities, err := filippoage.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading age identities from %s: %w", path, err)
+	}
+	return identities, nil
+}
+
+// generateNewDataKey generates a new random 32-byte data key and encrypts it for each of the given recipients.
+func generateNewDataKey(recipients []filippoage.Recipient) ([]byte, []byte, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+
+	var encryptedKey bytes.Buffer
+	w, err := filippoage.Encrypt(&encryptedKey, recipients...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encrypting data key: %w", err)
+	}
+	if _, err := w.Write(dataKey); err != nil {
+		return nil, nil, fmt.Errorf("encrypting data key: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, nil, fmt.Errorf("encrypting data key: %w", err)
+	}
+	return dataKey, encryptedKey.Bytes(), nil
+}
+
+// unwrapDataKey decrypts the data key with whichever of the given identities is one of its recipients.
+func unwrapDataKey(encryptedKey []byte, identities []filippoage.Identity) ([]byte, error) {
+	if len(identities) == 0 {
+		return nil, errNoIdentity
+	}
+
+	r, err := filippoage.Decrypt(bytes.NewReader(encryptedKey), identities...)
+	if err != nil {
+		var noMatch *filippoage.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, errNoIdentity
+		}
+		return nil, fmt.Errorf("decrypting data key: %w", err)
+	}
+	dataKey, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting data key: %w", err)
+	}
+	return dataKey, nil
+}
+
+// Manager is the secrets.Manager implementation for age recipients.
+type Manager struct {
+	state   ageSecretsManagerState
+	crypter config.Crypter
+}
+
+func (m *Manager) Type() string                         { return Type }
+func (m *Manager) State() interface{}                   { return m.state }
+func (m *Manager) Encrypter() (config.Encrypter, error) { return m.crypter, nil }
+func (m *Manager) Decrypter() (config.Decrypter, error) { return m.crypter, nil }
+func (m *Manager) EncryptedKey() []byte                 { return m.state.EncryptedKey }
+
+// NewAgeSecretsManagerFromState deserializes configuration from state and returns a secrets manager that decrypts its
+// data key with the local age identities. If none of the identities is a recipient of the data key, the returned
+// manager preserves the state but fails to encrypt or decrypt values.
+func NewAgeSecretsManagerFromState(state json.RawMessage) (secrets.Manager, error) {
+	var s ageSecretsManagerState
+	if err := json.Unmarshal(state, &s); err != nil {
+		return nil, fmt.Errorf("unmarshalling state: %w", err)
+	}
+
+	identities, err := readIdentities()
+	if err != nil && !errors.Is(err, errNoIdentity) {
+		return nil, err
+	}
+	dataKey, err := unwrapDataKey(s.EncryptedKey, identities)
+	switch {
+	case errors.Is(err, errNoIdentity):
+		return &Manager{state: s, crypter: &errorCrypter{}}, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &Manager{state: s, crypter: config.NewSymmetricCrypter(dataKey)}, nil
+	}
+}
+
+// NewAgeSecretsManager returns a secrets manager for the given `age://` secrets provider. A new data key is generated
+// and wrapped for the recipients listed in the provider's file if the stack has no key yet, if the secrets provider
+// is changing, or if rotateSecretsProvider is set; this is how recipients are added and removed. Otherwise the stack's
+// existing data key is unwrapped with the local age identities.
+func NewAgeSecretsManager(info *workspace.ProjectStack,
+	secretsProvider string, rotateSecretsProvider bool,
+) (secrets.Manager, error) {
+	// Only a passphrase provider has an encryption salt, so remove any leftover salt when switching to age.
+	info.EncryptionSalt = ""
+
+	if rotateSecretsProvider || info.EncryptedKey == "" || info.SecretsProvider != secretsProvider {
+		path, err := recipientsPath(secretsProvider)
+		if err != nil {
+			return nil, err
+		}
+		recipients, err := readRecipients(path)
+		if err != nil {
+			return nil, err
+		}
+		dataKey, encryptedKey, err := generateNewDataKey(recipients)
+		if err != nil {
+			return nil, err
+		}
+		info.SecretsProvider = secretsProvider
+		info.EncryptedKey = base64.StdEncoding.EncodeToString(encryptedKey)
+
+		return &Manager{
+			state:   ageSecretsManagerState{URL: secretsProvider, EncryptedKey: encryptedKey},
+			crypter: config.NewSymmetricCrypter(dataKey),
+		}, nil
+	}
+
+	encryptedKey, err := base64.StdEncoding.DecodeString(info.EncryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := readIdentities()
+	if err != nil && !errors.Is(err, errNoIdentity) {
+		return nil, err
+	}
+	dataKey, err := unwrapDataKey(encryptedKey, identities)
+	if errors.Is(err, errNoIdentity) {
+		return nil, noIdentityError()
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		state:   ageSecretsManagerState{URL: secretsProvider, EncryptedKey: encryptedKey},
+		crypter: config.NewSymmetricCrypter(dataKey),
+	}, nil
+}
+
+func noIdentityError() error {
+	return fmt.Errorf("none of the age identities in %s or ~/.pulumi/age/keys.txt is a recipient of the stack's "+
+		"data key", IdentityFileEnvVar)
+}
+
+// errorCrypter is the crypter of a manager whose data key could not be unwrapped. Like the locked passphrase
+// manager, it allows deployments to round trip without access to their secrets.
+type errorCrypter struct{}
+
+func (ec *errorCrypter) EncryptValue(ctx context.Context, _ string) (string, error) {
+	return "", fmt.Errorf("failed to encrypt: %w", noIdentityError())
+}
+
+func (ec *errorCrypter) DecryptValue(ctx context.Context, _ string) (string, error) {
+	return "", fmt.Errorf("failed to decrypt: %w", noIdentityError())
+}
+
+func (ec *errorCrypter) BulkDecrypt(ctx context.Context, _ []string) (map[string]string, error) {
+	return nil, fmt.Errorf("failed to decrypt: %w", noIdentityError())
+}
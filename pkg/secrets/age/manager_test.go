@@ -0,0 +1,192 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package age
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	filippoage "filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func TestDataKey(t *testing.T) {
+	t.Parallel()
+
+	alice, err := filippoage.GenerateX25519Identity()
+	require.NoError(t, err)
+	bob, err := filippoage.GenerateX25519Identity()
+	require.NoError(t, err)
+	eve, err := filippoage.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	dataKey, encryptedKey, err := generateNewDataKey([]filippoage.Recipient{alice.Recipient(), bob.Recipient()})
+	require.NoError(t, err)
+	assert.Len(t, dataKey, 32)
+
+	for _, id := range []filippoage.Identity{alice, bob} {
+		unwrapped, err := unwrapDataKey(encryptedKey, []filippoage.Identity{eve, id})
+		require.NoError(t, err)
+		assert.Equal(t, dataKey, unwrapped)
+	}
+
+	_, err = unwrapDataKey(encryptedKey, []filippoage.Identity{eve})
+	assert.ErrorIs(t, err, errNoIdentity)
+	_, err = unwrapDataKey(encryptedKey, nil)
+	assert.ErrorIs(t, err, errNoIdentity)
+}
+
+// writeKeys writes the given keys to a file in dir and returns its path.
+func writeKeys(t *testing.T, dir, name string, keys ...string) string {
+	path := filepath.Join(dir, name)
+	contents := "# created by " + t.Name() + "\n" + strings.Join(keys, "\n") + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestAgeSecretsManager(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	alice, err := filippoage.GenerateX25519Identity()
+	require.NoError(t, err)
+	bob, err := filippoage.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	recipients := writeKeys(t, dir, "recipients.txt", alice.Recipient().String(), bob.Recipient().String())
+	provider := Scheme + recipients
+
+	// Creating the manager generates a data key for both recipients and doesn't need an identity.
+	t.Setenv(IdentityFileEnvVar, filepath.Join(dir, "missing.txt"))
+	info := &workspace.ProjectStack{EncryptionSalt: "v1:salt"}
+	sm, err := NewAgeSecretsManager(info, provider, false)
+	require.NoError(t, err)
+	assert.Equal(t, provider, info.SecretsProvider)
+	assert.NotEmpty(t, info.EncryptedKey)
+	assert.Empty(t, info.EncryptionSalt)
+
+	enc, err := sm.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue(ctx, "hunter2")
+	require.NoError(t, err)
+
+	state, err := json.Marshal(sm.State())
+	require.NoError(t, err)
+
+	// Either recipient can decrypt.
+	for _, id := range []*filippoage.X25519Identity{alice, bob} {
+		t.Setenv(IdentityFileEnvVar, writeKeys(t, dir, "identity.txt", id.String()))
+
+		sm, err := NewAgeSecretsManager(info, provider, false)
+		require.NoError(t, err)
+		dec, err := sm.Decrypter()
+		require.NoError(t, err)
+		plaintext, err := dec.DecryptValue(ctx, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", plaintext)
+
+		sm, err = NewAgeSecretsManagerFromState(state)
+		require.NoError(t, err)
+		dec, err = sm.Decrypter()
+		require.NoError(t, err)
+		plaintext, err = dec.DecryptValue(ctx, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", plaintext)
+	}
+
+	// Removing bob from the recipients and rotating locks bob out.
+	writeKeys(t, dir, "recipients.txt", alice.Recipient().String())
+	_, err = NewAgeSecretsManager(info, provider, true)
+	require.NoError(t, err)
+
+	t.Setenv(IdentityFileEnvVar, writeKeys(t, dir, "identity.txt", bob.String()))
+	_, err = NewAgeSecretsManager(info, provider, false)
+	assert.ErrorContains(t, err, "is a recipient of the stack's data key")
+
+	t.Setenv(IdentityFileEnvVar, writeKeys(t, dir, "identity.txt", bob.String(), alice.String()))
+	_, err = NewAgeSecretsManager(info, provider, false)
+	assert.NoError(t, err)
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestAgeSecretsManagerLocked(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	alice, err := filippoage.GenerateX25519Identity()
+	require.NoError(t, err)
+	provider := Scheme + writeKeys(t, dir, "recipients.txt", alice.Recipient().String())
+
+	sm, err := NewAgeSecretsManager(&workspace.ProjectStack{}, provider, false)
+	require.NoError(t, err)
+	state, err := json.Marshal(sm.State())
+	require.NoError(t, err)
+
+	// Without a matching identity the manager still round trips its state, but can't decrypt.
+	t.Setenv(IdentityFileEnvVar, filepath.Join(dir, "missing.txt"))
+	locked, err := NewAgeSecretsManagerFromState(state)
+	require.NoError(t, err)
+	assert.Equal(t, sm.State(), locked.State())
+
+	dec, err := locked.Decrypter()
+	require.NoError(t, err)
+	_, err = dec.DecryptValue(ctx, "ciphertext")
+	assert.ErrorContains(t, err, "failed to decrypt")
+}
+
+func TestAgeSecretsManagerBadRecipients(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := NewAgeSecretsManager(&workspace.ProjectStack{}, Scheme+writeKeys(t, dir, "empty.txt"), false)
+	assert.ErrorContains(t, err, "no recipients found")
+
+	bad := writeKeys(t, dir, "bad.txt", "ssh-ed25519 AAAA")
+	_, err = NewAgeSecretsManager(&workspace.ProjectStack{}, Scheme+bad, false)
+	assert.ErrorContains(t, err, "malformed recipient")
+
+	_, err = NewAgeSecretsManager(&workspace.ProjectStack{}, Scheme+filepath.Join(dir, "missing.txt"), false)
+	assert.ErrorContains(t, err, "reading age recipients")
+}
+
+//nolint:paralleltest // changes the working directory
+func TestAgeSecretsManagerRelativeRecipients(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "Pulumi.yaml"), []byte("name: test\nruntime: go\n"), 0o600))
+	alice, err := filippoage.GenerateX25519Identity()
+	require.NoError(t, err)
+	writeKeys(t, root, "recipients.txt", alice.Recipient().String())
+	sub := filepath.Join(root, "sub")
+	require.NoError(t, os.Mkdir(sub, 0o700))
+
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(sub))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(cwd)) })
+
+	// The recipients file is found relative to the project's root rather than the working directory.
+	info := &workspace.ProjectStack{}
+	_, err = NewAgeSecretsManager(info, Scheme+"recipients.txt", false)
+	require.NoError(t, err)
+	assert.Equal(t, Scheme+"recipients.txt", info.SecretsProvider)
+}
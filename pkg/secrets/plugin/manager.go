@@ -0,0 +1,338 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugin implements support for secrets managers that are provided by secrets plugins.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	netUrl "net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	sdkplugin "github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// Type is the type of secrets managed by this secrets provider
+const Type = "plugin"
+
+// Scheme is the URL scheme of secrets providers that are implemented by secrets plugins, e.g. `plugin://vault?key=k`.
+const Scheme = "plugin"
+
+// IsPluginURL returns true if the given secrets provider is implemented by a secrets plugin.
+func IsPluginURL(secretsProvider string) bool {
+	return strings.HasPrefix(secretsProvider, Scheme+"://")
+}
+
+type pluginSecretsManagerState struct {
+	URL   string `json:"url"`
+	State string `json:"state,omitempty"`
+}
+
+// parseURL returns the name of the secrets plugin and the arguments that are passed to it for the given secrets
+// provider URL.
+func parseURL(url string) (string, map[string]string, error) {
+	u, err := netUrl.Parse(url)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to parse the secrets provider URL: %w", err)
+	}
+	if u.Scheme != Scheme {
+		return "", nil, fmt.Errorf("secrets provider URL %q must use the %s:// scheme", url, Scheme)
+	}
+	if u.Host == "" {
+		return "", nil, fmt.Errorf("secrets provider URL %q must name a secrets plugin, e.g. %s://<name>", url, Scheme)
+	}
+
+	args := map[string]string{}
+	for k, vs := range u.Query() {
+		if len(vs) > 0 {
+			args[k] = vs[len(vs)-1]
+		}
+	}
+	return u.Host, args, nil
+}
+
+// loadedPlugin is a secrets plugin that has been loaded along with the plugin context that it runs in.
+type loadedPlugin struct {
+	pctx *sdkplugin.Context
+	sm   sdkplugin.SecretsManager
+}
+
+// loadSecretsPlugin loads the named secrets plugin.
+func loadSecretsPlugin(name string) (*loadedPlugin, error) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	pctx, err := sdkplugin.NewContext(nil, nil, nil, nil, pwd, nil, true, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sm, err := sdkplugin.NewSecretsManager(pctx, name, nil)
+	if err != nil {
+		contract.IgnoreClose(pctx)
+		return nil, fmt.Errorf("loading secrets plugin %q: %w", name, err)
+	}
+	return &loadedPlugin{pctx: pctx, sm: sm}, nil
+}
+
+// loadedCrypters tracks the crypters that currently have a secrets plugin loaded so that CloseAll can shut down the
+// plugins of managers that are never closed explicitly.
+var loadedCrypters = struct {
+	sync.Mutex
+	m map[*crypter]struct{}
+}{m: map[*crypter]struct{}{}}
+
+// CloseAll shuts down the secrets plugins of all managers that have not been closed yet. Secrets managers are
+// frequently shared without a single owner, so the CLI calls this before it exits to avoid leaking plugin processes.
+func CloseAll() error {
+	loadedCrypters.Lock()
+	crypters := make([]*crypter, 0, len(loadedCrypters.m))
+	for c := range loadedCrypters.m {
+		crypters = append(crypters, c)
+	}
+	loadedCrypters.Unlock()
+
+	var result error
+	for _, c := range crypters {
+		if err := c.close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// Close shuts down the plugin and its context.
+func (p *loadedPlugin) Close() error {
+	err := p.sm.Close()
+	if cerr := p.pctx.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Manager is the secrets.Manager implementation for secrets plugins. The plugin is loaded when the manager is first
+// used and stays loaded until the manager is closed.
+type Manager struct {
+	state   pluginSecretsManagerState
+	crypter *crypter
+}
+
+var _ io.Closer = (*Manager)(nil)
+
+func newPluginSecretsManager(state pluginSecretsManagerState, plug *loadedPlugin) (*Manager, error) {
+	name, args, err := parseURL(state.URL)
+	if err != nil {
+		return nil, err
+	}
+	c := &crypter{name: name, args: args, state: state.State}
+	if plug != nil {
+		c.setPlugin(plug)
+	}
+	return &Manager{state: state, crypter: c}, nil
+}
+
+func (m *Manager) Type() string                         { return Type }
+func (m *Manager) State() interface{}                   { return m.state }
+func (m *Manager) Encrypter() (config.Encrypter, error) { return m.crypter, nil }
+func (m *Manager) Decrypter() (config.Decrypter, error) { return m.crypter, nil }
+
+// Close shuts down the secrets plugin if it has been loaded. The manager loads the plugin again if it is used after
+// it has been closed.
+func (m *Manager) Close() error {
+	return m.crypter.close()
+}
+
+// NewPluginSecretsManagerFromState deserializes configuration from state and returns a secrets manager that uses the
+// secrets plugin named by the state's URL to encrypt and decrypt secrets values.
+func NewPluginSecretsManagerFromState(state json.RawMessage) (secrets.Manager, error) {
+	var s pluginSecretsManagerState
+	if err := json.Unmarshal(state, &s); err != nil {
+		return nil, fmt.Errorf("unmarshalling state: %w", err)
+	}
+
+	return newPluginSecretsManager(s, nil)
+}
+
+// NewPluginSecretsManager returns a secrets manager for the given `plugin://` secrets provider URL. The plugin is
+// initialized with the state it previously returned for the stack, unless the secrets provider is changing or
+// rotateSecretsProvider is set, and the state it returns is recorded in the stack's settings.
+func NewPluginSecretsManager(info *workspace.ProjectStack,
+	secretsProvider string, rotateSecretsProvider bool,
+) (secrets.Manager, error) {
+	name, args, err := parseURL(secretsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	state := info.EncryptedKey
+	if rotateSecretsProvider || info.SecretsProvider != secretsProvider {
+		state = ""
+	}
+
+	plug, err := loadSecretsPlugin(name)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := plug.sm.Initialize(context.Background(), &sdkplugin.InitializeSecretsRequest{
+		Args:  args,
+		State: state,
+	})
+	if err != nil {
+		contract.IgnoreClose(plug)
+		return nil, fmt.Errorf("initializing secrets plugin %q: %w", name, err)
+	}
+
+	// Only a passphrase provider has an encryption salt, so remove any leftover salt now that the stack has switched
+	// to the plugin.
+	info.EncryptionSalt = ""
+	info.SecretsProvider = secretsProvider
+	info.EncryptedKey = resp.State
+
+	return newPluginSecretsManager(pluginSecretsManagerState{URL: secretsProvider, State: resp.State}, plug)
+}
+
+// crypter is a config.Crypter that forwards to a secrets plugin. Values are sent to the plugin in batches where the
+// config.Crypter interface allows it.
+type crypter struct {
+	name  string
+	args  map[string]string
+	state string
+
+	m      sync.Mutex
+	plugin *loadedPlugin
+}
+
+// secretsManager returns the loaded secrets plugin, loading it if necessary.
+func (c *crypter) secretsManager() (sdkplugin.SecretsManager, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.plugin == nil {
+		plug, err := loadSecretsPlugin(c.name)
+		if err != nil {
+			return nil, err
+		}
+		c.setPlugin(plug)
+	}
+	return c.plugin.sm, nil
+}
+
+func (c *crypter) close() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.plugin == nil {
+		return nil
+	}
+	err := c.plugin.Close()
+	c.setPlugin(nil)
+	return err
+}
+
+// setPlugin records the crypter's loaded plugin and tracks the crypter for CloseAll. c.m must be held.
+func (c *crypter) setPlugin(plug *loadedPlugin) {
+	c.plugin = plug
+
+	loadedCrypters.Lock()
+	defer loadedCrypters.Unlock()
+	if plug == nil {
+		delete(loadedCrypters.m, c)
+	} else {
+		loadedCrypters.m[c] = struct{}{}
+	}
+}
+
+func (c *crypter) EncryptValue(ctx context.Context, plaintext string) (string, error) {
+	ciphertexts, err := c.encrypt(ctx, []string{plaintext})
+	if err != nil {
+		return "", err
+	}
+	return ciphertexts[0], nil
+}
+
+func (c *crypter) DecryptValue(ctx context.Context, ciphertext string) (string, error) {
+	plaintexts, err := c.decrypt(ctx, []string{ciphertext})
+	if err != nil {
+		return "", err
+	}
+	return plaintexts[0], nil
+}
+
+func (c *crypter) BulkDecrypt(ctx context.Context, ciphertexts []string) (map[string]string, error) {
+	secretMap := map[string]string{}
+	if len(ciphertexts) == 0 {
+		return secretMap, nil
+	}
+
+	plaintexts, err := c.decrypt(ctx, ciphertexts)
+	if err != nil {
+		return nil, err
+	}
+	for i, ct := range ciphertexts {
+		secretMap[ct] = plaintexts[i]
+	}
+	return secretMap, nil
+}
+
+// encrypt encrypts the given plaintexts in a single request, returning the ciphertexts in the same order.
+func (c *crypter) encrypt(ctx context.Context, plaintexts []string) ([]string, error) {
+	sm, err := c.secretsManager()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := sm.Encrypt(ctx, &sdkplugin.EncryptSecretsRequest{
+		Args:       c.args,
+		State:      c.state,
+		Plaintexts: plaintexts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Ciphertexts) != len(plaintexts) {
+		return nil, fmt.Errorf("secrets plugin %q returned %d ciphertexts for %d plaintexts",
+			c.name, len(resp.Ciphertexts), len(plaintexts))
+	}
+	return resp.Ciphertexts, nil
+}
+
+// decrypt decrypts the given ciphertexts in a single request, returning the plaintexts in the same order.
+func (c *crypter) decrypt(ctx context.Context, ciphertexts []string) ([]string, error) {
+	sm, err := c.secretsManager()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := sm.Decrypt(ctx, &sdkplugin.DecryptSecretsRequest{
+		Args:        c.args,
+		State:       c.state,
+		Ciphertexts: ciphertexts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Plaintexts) != len(ciphertexts) {
+		return nil, fmt.Errorf("secrets plugin %q returned %d plaintexts for %d ciphertexts",
+			c.name, len(resp.Plaintexts), len(ciphertexts))
+	}
+	return resp.Plaintexts, nil
+}
@@ -0,0 +1,205 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sdkplugin "github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// installTestPlugin builds the secrets plugin in testdata and puts it on $PATH as the ambient `test` secrets plugin.
+func installTestPlugin(t *testing.T) {
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "pulumi-secrets-test")
+	cmd := exec.Command("go", "build", "-o", bin, "./testdata/pulumi-secrets-test")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "building test plugin: %s", out)
+
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("PULUMI_IGNORE_AMBIENT_PLUGINS", "false")
+}
+
+func TestParseURL(t *testing.T) {
+	t.Parallel()
+
+	name, args, err := parseURL("plugin://vault?key=k&region=us-west-2")
+	require.NoError(t, err)
+	assert.Equal(t, "vault", name)
+	assert.Equal(t, map[string]string{"key": "k", "region": "us-west-2"}, args)
+
+	_, _, err = parseURL("awskms://alias/key")
+	assert.ErrorContains(t, err, "must use the plugin:// scheme")
+
+	_, _, err = parseURL("plugin://?key=k")
+	assert.ErrorContains(t, err, "must name a secrets plugin")
+
+	assert.True(t, IsPluginURL("plugin://vault"))
+	assert.False(t, IsPluginURL("hashivault://mykey"))
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestPluginSecretsManager(t *testing.T) {
+	installTestPlugin(t)
+	ctx := context.Background()
+
+	info := &workspace.ProjectStack{EncryptionSalt: "v1:salt"}
+	sm, err := NewPluginSecretsManager(info, "plugin://test?key=abc", false)
+	require.NoError(t, err)
+	assert.Equal(t, Type, sm.Type())
+	assert.Equal(t, "plugin://test?key=abc", info.SecretsProvider)
+	assert.Equal(t, "abc", info.EncryptedKey)
+	assert.Empty(t, info.EncryptionSalt)
+
+	enc, err := sm.Encrypter()
+	require.NoError(t, err)
+	ct1, err := enc.EncryptValue(ctx, "hunter2")
+	require.NoError(t, err)
+	ct2, err := enc.EncryptValue(ctx, "correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, "hunter2", ct1)
+
+	// Round trip the manager through its serialized state, as a deployment would.
+	state, err := json.Marshal(sm.State())
+	require.NoError(t, err)
+	sm, err = NewPluginSecretsManagerFromState(state)
+	require.NoError(t, err)
+
+	dec, err := sm.Decrypter()
+	require.NoError(t, err)
+	pt, err := dec.DecryptValue(ctx, ct1)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", pt)
+
+	pts, err := dec.BulkDecrypt(ctx, []string{ct1, ct2})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{ct1: "hunter2", ct2: "correct horse"}, pts)
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestPluginSecretsManagerState(t *testing.T) {
+	installTestPlugin(t)
+
+	// A fresh stack is initialized with a generated key, which is reused while the secrets provider is unchanged.
+	info := &workspace.ProjectStack{}
+	_, err := NewPluginSecretsManager(info, "plugin://test", false)
+	require.NoError(t, err)
+	key := info.EncryptedKey
+	assert.NotEmpty(t, key)
+
+	_, err = NewPluginSecretsManager(info, "plugin://test", false)
+	require.NoError(t, err)
+	assert.Equal(t, key, info.EncryptedKey)
+
+	// Rotating the secrets provider discards the previous state.
+	_, err = NewPluginSecretsManager(info, "plugin://test", true)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, info.EncryptedKey)
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestPluginSecretsManagerMissingPlugin(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("PULUMI_HOME", t.TempDir())
+
+	// The stack's settings are left alone when the plugin can't be initialized.
+	info := &workspace.ProjectStack{EncryptionSalt: "v1:salt"}
+	_, err := NewPluginSecretsManager(info, "plugin://does-not-exist", false)
+	assert.ErrorContains(t, err, `loading secrets plugin "does-not-exist"`)
+	assert.Equal(t, "v1:salt", info.EncryptionSalt)
+	assert.Empty(t, info.SecretsProvider)
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestPluginSecretsManagerLoadsPluginOnce(t *testing.T) {
+	installTestPlugin(t)
+	ctx := context.Background()
+
+	sm, err := NewPluginSecretsManager(&workspace.ProjectStack{}, "plugin://test?key=abc", false)
+	require.NoError(t, err)
+	m := sm.(*Manager)
+	plug := m.crypter.plugin
+	require.NotNil(t, plug)
+
+	ct, err := m.crypter.EncryptValue(ctx, "hunter2")
+	require.NoError(t, err)
+	_, err = m.crypter.DecryptValue(ctx, ct)
+	require.NoError(t, err)
+	assert.Same(t, plug, m.crypter.plugin)
+
+	// Closing the manager shuts the plugin down; using the manager again reloads it.
+	require.NoError(t, m.Close())
+	assert.Nil(t, m.crypter.plugin)
+	pt, err := m.crypter.DecryptValue(ctx, ct)
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", pt)
+	require.NoError(t, m.Close())
+}
+
+// shortSecretsManager is a secrets plugin that drops the last value of every response.
+type shortSecretsManager struct {
+	sdkplugin.SecretsManager
+}
+
+func (shortSecretsManager) Encrypt(ctx context.Context,
+	req *sdkplugin.EncryptSecretsRequest,
+) (*sdkplugin.EncryptSecretsResponse, error) {
+	return &sdkplugin.EncryptSecretsResponse{Ciphertexts: req.Plaintexts[:len(req.Plaintexts)-1]}, nil
+}
+
+func (shortSecretsManager) Decrypt(ctx context.Context,
+	req *sdkplugin.DecryptSecretsRequest,
+) (*sdkplugin.DecryptSecretsResponse, error) {
+	return &sdkplugin.DecryptSecretsResponse{Plaintexts: req.Ciphertexts[:len(req.Ciphertexts)-1]}, nil
+}
+
+func TestPluginSecretsManagerShortResponses(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c := &crypter{name: "short", plugin: &loadedPlugin{sm: shortSecretsManager{}}}
+
+	_, err := c.EncryptValue(ctx, "hunter2")
+	assert.ErrorContains(t, err, `secrets plugin "short" returned 0 ciphertexts for 1 plaintexts`)
+
+	_, err = c.DecryptValue(ctx, "ct")
+	assert.ErrorContains(t, err, `secrets plugin "short" returned 0 plaintexts for 1 ciphertexts`)
+
+	_, err = c.BulkDecrypt(ctx, []string{"ct1", "ct2"})
+	assert.ErrorContains(t, err, `secrets plugin "short" returned 1 plaintexts for 2 ciphertexts`)
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestCloseAll(t *testing.T) {
+	installTestPlugin(t)
+
+	sm, err := NewPluginSecretsManager(&workspace.ProjectStack{}, "plugin://test?key=abc", false)
+	require.NoError(t, err)
+	m := sm.(*Manager)
+	require.NotNil(t, m.crypter.plugin)
+
+	require.NoError(t, CloseAll())
+	assert.Nil(t, m.crypter.plugin)
+	require.NoError(t, CloseAll())
+}
@@ -0,0 +1,103 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// pulumi-secrets-test is a secrets plugin for tests. Its "encryption" is a reversible encoding that is keyed by the
+// state it returns from Initialize, which is either the `key` argument or a freshly generated key.
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+type testSecretsManager struct{}
+
+func (testSecretsManager) Close() error {
+	return nil
+}
+
+func (testSecretsManager) Initialize(ctx context.Context,
+	req *plugin.InitializeSecretsRequest,
+) (*plugin.InitializeSecretsResponse, error) {
+	if key, ok := req.Args["key"]; ok {
+		return &plugin.InitializeSecretsResponse{State: key}, nil
+	}
+	if req.State != "" {
+		return &plugin.InitializeSecretsResponse{State: req.State}, nil
+	}
+
+	key := make([]byte, 8)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return &plugin.InitializeSecretsResponse{State: hex.EncodeToString(key)}, nil
+}
+
+func (testSecretsManager) Encrypt(ctx context.Context,
+	req *plugin.EncryptSecretsRequest,
+) (*plugin.EncryptSecretsResponse, error) {
+	ciphertexts := make([]string, len(req.Plaintexts))
+	for i, pt := range req.Plaintexts {
+		ciphertexts[i] = base64.StdEncoding.EncodeToString([]byte(req.State + ":" + pt))
+	}
+	return &plugin.EncryptSecretsResponse{Ciphertexts: ciphertexts}, nil
+}
+
+func (testSecretsManager) Decrypt(ctx context.Context,
+	req *plugin.DecryptSecretsRequest,
+) (*plugin.DecryptSecretsResponse, error) {
+	plaintexts := make([]string, len(req.Ciphertexts))
+	for i, ct := range req.Ciphertexts {
+		bytes, err := base64.StdEncoding.DecodeString(ct)
+		if err != nil {
+			return nil, err
+		}
+		prefix := req.State + ":"
+		if !strings.HasPrefix(string(bytes), prefix) {
+			return nil, fmt.Errorf("ciphertext was not encrypted with this key")
+		}
+		plaintexts[i] = strings.TrimPrefix(string(bytes), prefix)
+	}
+	return &plugin.DecryptSecretsResponse{Plaintexts: plaintexts}, nil
+}
+
+func main() {
+	handle, err := rpcutil.ServeWithOptions(rpcutil.ServeOptions{
+		Init: func(srv *grpc.Server) error {
+			pulumirpc.RegisterSecretsManagerServer(srv, plugin.NewSecretsManagerServer(testSecretsManager{}))
+			return nil
+		},
+	})
+	if err != nil {
+		cmdutil.Exit(err)
+	}
+
+	fmt.Fprintf(os.Stdout, "%d\n", handle.Port)
+
+	if err := <-handle.Done; err != nil {
+		cmdutil.Exit(err)
+	}
+}
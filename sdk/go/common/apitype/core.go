@@ -333,6 +333,8 @@ type ResourceV3 struct {
 	Created *time.Time `json:"created,omitempty" yaml:"created,omitempty"`
 	// Modified tracks when the resource state was last altered. Checkpoints prior to early 2023 do not include this.
 	Modified *time.Time `json:"modified,omitempty" yaml:"modified,omitempty"`
+	// RetryPolicy is a configuration block that controls how failed CRUD operations are retried.
+	RetryPolicy *resource.RetryPolicy `json:"retryPolicy,omitempty" yaml:"retryPolicy,omitempty"`
 }
 
 // ManifestV1 captures meta-information about this checkpoint file, such as versions of binaries, etc.
@@ -345,6 +347,8 @@ type ManifestV1 struct {
 	Version string `json:"version" yaml:"version"`
 	// Plugins contains the binary version info of plug-ins used.
 	Plugins []PluginInfoV1 `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+	// OutputsSchema contains the types that the project declared for the stack's outputs, if any.
+	OutputsSchema map[string]workspace.ProjectOutputType `json:"outputsSchema,omitempty" yaml:"outputsSchema,omitempty"`
 }
 
 // PluginInfoV1 captures the version and information about a plugin.
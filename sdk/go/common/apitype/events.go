@@ -80,6 +80,15 @@ type SummaryEvent struct {
 	// compatibility. For older clients this will map to the version, while for newer ones
 	// it will be the version tag prepended with "v".
 	PolicyPacks map[string]string `json:"PolicyPacks"`
+	// Failures are the resource operations that failed during an update that continued on errors.
+	Failures []ResourceFailure `json:"failures,omitempty"`
+}
+
+// ResourceFailure describes a resource operation that failed during an update.
+type ResourceFailure struct {
+	URN     string `json:"urn"`
+	Op      OpType `json:"op"`
+	Message string `json:"message"`
 }
 
 // DiffKind describes the kind of a particular property diff.
@@ -76,4 +76,14 @@ var (
 
 	SelfManagedStateLegacyLayout = env.Bool("SELF_MANAGED_STATE_LEGACY_LAYOUT",
 		"Uses the legacy layout for new buckets, which currently default to project-scoped stacks.")
+
+	SelfManagedStateLockTTL = env.Int("SELF_MANAGED_STATE_LOCK_TTL",
+		"The number of seconds for which locks on stacks are leased. Defaults to 300. "+
+			"Locks whose lease has expired are taken over by the next operation on the stack.")
+
+	SelfManagedStateJournal = env.Bool("SELF_MANAGED_STATE_JOURNAL",
+		"Journals the steps of updates instead of rewriting the whole checkpoint after every step.")
+
+	SelfManagedStateJournalCompactionInterval = env.Int("SELF_MANAGED_STATE_JOURNAL_COMPACTION_INTERVAL",
+		"The number of journal entries after which the journal is compacted into a checkpoint. Defaults to 1000.")
 )
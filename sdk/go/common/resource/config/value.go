@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
@@ -29,6 +30,61 @@ type Value struct {
 	value  string
 	secure bool
 	object bool
+	// ref is set if the value refers to an environment variable or a file, which is read when the value is used.
+	ref *Reference
+}
+
+// Reference is a config value that refers to an environment variable or a file, which is read when the value is used
+// rather than stored in the config. In config files, references are objects with either a "fromEnv" or a "fromFile"
+// key and an optional "secret" key, e.g. `aws:region: {fromEnv: AWS_REGION}` or
+// `app:cert: {fromFile: ./cert.pem, secret: true}`.
+type Reference struct {
+	// FromEnv is the name of the environment variable to read the value from.
+	FromEnv string `json:"fromEnv,omitempty" yaml:"fromEnv,omitempty"`
+	// FromFile is the path of the file to read the value from, relative to the working directory.
+	FromFile string `json:"fromFile,omitempty" yaml:"fromFile,omitempty"`
+	// Secret is true if the value is a secret.
+	Secret bool `json:"secret,omitempty" yaml:"secret,omitempty"`
+}
+
+// String returns a description of the source of the referenced value.
+func (r Reference) String() string {
+	if r.FromEnv != "" {
+		return fmt.Sprintf("environment variable %s", r.FromEnv)
+	}
+	return fmt.Sprintf("file %s", r.FromFile)
+}
+
+// Resolve reads the referenced value.
+func (r Reference) Resolve() (string, error) {
+	if r.FromEnv != "" {
+		v, ok := os.LookupEnv(r.FromEnv)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s referenced by config is not set", r.FromEnv)
+		}
+		return v, nil
+	}
+
+	b, err := os.ReadFile(r.FromFile)
+	if err != nil {
+		return "", fmt.Errorf("reading file referenced by config: %w", err)
+	}
+	return string(b), nil
+}
+
+// marshal returns the representation of the reference in config files.
+func (r Reference) marshal() map[string]interface{} {
+	m := make(map[string]interface{})
+	if r.FromEnv != "" {
+		m["fromEnv"] = r.FromEnv
+	}
+	if r.FromFile != "" {
+		m["fromFile"] = r.FromFile
+	}
+	if r.Secret {
+		m["secret"] = true
+	}
+	return m
 }
 
 func NewSecureValue(v string) Value {
@@ -47,9 +103,18 @@ func NewObjectValue(v string) Value {
 	return Value{value: v, secure: false, object: true}
 }
 
+// NewReferenceValue returns a value that refers to an environment variable or a file. The value is secure if the
+// reference is secret.
+func NewReferenceValue(ref Reference) Value {
+	return Value{secure: ref.Secret, ref: &ref}
+}
+
 // Value fetches the value of this configuration entry, using decrypter to decrypt if necessary.  If the value
 // is a secret and decrypter is nil, or if decryption fails for any reason, a non-nil error is returned.
 func (c Value) Value(decrypter Decrypter) (string, error) {
+	if c.ref != nil {
+		return c.resolveReference(decrypter)
+	}
 	if !c.secure {
 		return c.value, nil
 	}
@@ -75,7 +140,39 @@ func (c Value) Value(decrypter Decrypter) (string, error) {
 	return decrypter.DecryptValue(context.TODO(), c.value)
 }
 
+// resolveReference reads the value that c refers to. Secret values are blinded by blinding decrypters, like encrypted
+// values, and recorded by tracking decrypters.
+func (c Value) resolveReference(decrypter Decrypter) (string, error) {
+	if c.ref.Secret {
+		if _, isBlinding := decrypter.(blindingCrypter); isBlinding {
+			return decrypter.DecryptValue(context.TODO(), "")
+		}
+	}
+
+	v, err := c.ref.Resolve()
+	if err != nil {
+		return "", err
+	}
+	if tracking, isTracking := decrypter.(*trackingDecrypter); isTracking && c.ref.Secret {
+		tracking.secureValues = append(tracking.secureValues, v)
+	}
+	return v, nil
+}
+
+// Reference returns the environment variable or file that the value refers to, if any.
+func (c Value) Reference() (Reference, bool) {
+	if c.ref == nil {
+		return Reference{}, false
+	}
+	return *c.ref, true
+}
+
 func (c Value) Copy(decrypter Decrypter, encrypter Encrypter) (Value, error) {
+	// References are copied as they are, as the values they refer to aren't stored in the config.
+	if c.ref != nil {
+		return c, nil
+	}
+
 	var val Value
 	raw, err := c.Value(decrypter)
 	if err != nil {
@@ -133,6 +230,9 @@ func (c Value) Object() bool {
 
 // ToObject returns the string value (if not an object), or the unmarshalled JSON object (if an object).
 func (c Value) ToObject() (interface{}, error) {
+	if c.ref != nil {
+		return c.ref.marshal(), nil
+	}
 	if !c.object {
 		return c.value, nil
 	}
@@ -173,6 +273,7 @@ func (c *Value) unmarshalValue(unmarshal func(interface{}) error, fix func(inter
 	if err == nil {
 		c.secure = false
 		c.object = false
+		c.ref = nil
 		return nil
 	}
 
@@ -189,6 +290,19 @@ func (c *Value) unmarshalValue(unmarshal func(interface{}) error, fix func(inter
 		c.value = val
 		c.secure = true
 		c.object = false
+		c.ref = nil
+		return nil
+	}
+
+	is, ref, err := isReference(obj)
+	if err != nil {
+		return err
+	}
+	if is {
+		c.value = ""
+		c.secure = ref.Secret
+		c.object = false
+		c.ref = &ref
 		return nil
 	}
 
@@ -199,10 +313,15 @@ func (c *Value) unmarshalValue(unmarshal func(interface{}) error, fix func(inter
 	c.value = string(json)
 	c.secure = hasSecureValue(obj)
 	c.object = true
+	c.ref = nil
 	return nil
 }
 
 func (c Value) marshalValue() (interface{}, error) {
+	if c.ref != nil {
+		return c.ref.marshal(), nil
+	}
+
 	if c.object {
 		return c.unmarshalObjectJSON()
 	}
@@ -274,6 +393,48 @@ func isSecureValue(v interface{}) (bool, string) {
 	return false, ""
 }
 
+// isReference returns true if the object is a map with either a "fromEnv" or a "fromFile" key, and no keys other than
+// these and "secret". It returns an error if such a map is not a valid reference.
+func isReference(v interface{}) (bool, Reference, error) {
+	m, isMap := v.(map[string]interface{})
+	if !isMap {
+		return false, Reference{}, nil
+	}
+	for key := range m {
+		if key != "fromEnv" && key != "fromFile" && key != "secret" {
+			return false, Reference{}, nil
+		}
+	}
+
+	_, hasEnv := m["fromEnv"]
+	_, hasFile := m["fromFile"]
+	if !hasEnv && !hasFile {
+		return false, Reference{}, nil
+	}
+	if hasEnv && hasFile {
+		return false, Reference{}, errors.New(
+			"malformed config value: a reference can't have both a 'fromEnv' and a 'fromFile' key")
+	}
+
+	var ref Reference
+	var ok bool
+	if hasEnv {
+		if ref.FromEnv, ok = m["fromEnv"].(string); !ok || ref.FromEnv == "" {
+			return false, Reference{}, errors.New("malformed config value: 'fromEnv' must be a non-empty string")
+		}
+	} else {
+		if ref.FromFile, ok = m["fromFile"].(string); !ok || ref.FromFile == "" {
+			return false, Reference{}, errors.New("malformed config value: 'fromFile' must be a non-empty string")
+		}
+	}
+	if secret, hasSecret := m["secret"]; hasSecret {
+		if ref.Secret, ok = secret.(bool); !ok {
+			return false, Reference{}, errors.New("malformed config value: 'secret' must be a boolean")
+		}
+	}
+	return true, ref, nil
+}
+
 func reencryptObject(v interface{}, decrypter Decrypter, encrypter Encrypter) (interface{}, error) {
 	reencryptIt := func(val interface{}) (interface{}, error) {
 		if isSecure, secureVal := isSecureValue(val); isSecure {
@@ -18,6 +18,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -323,6 +325,127 @@ func TestCopyValue(t *testing.T) {
 	}
 }
 
+func TestReferenceValues(t *testing.T) {
+	t.Parallel()
+
+	t.Run("YAML", func(t *testing.T) {
+		t.Parallel()
+
+		var v Value
+		err := yaml.Unmarshal([]byte("fromFile: ./cert.pem\nsecret: true\n"), &v)
+		assert.NoError(t, err)
+		assert.True(t, v.Secure())
+		assert.False(t, v.Object())
+		ref, ok := v.Reference()
+		assert.True(t, ok)
+		assert.Equal(t, Reference{FromFile: "./cert.pem", Secret: true}, ref)
+
+		b, err := yaml.Marshal(v)
+		assert.NoError(t, err)
+		assert.Equal(t, "fromFile: ./cert.pem\nsecret: true\n", string(b))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		v := NewReferenceValue(Reference{FromEnv: "AWS_REGION"})
+		assert.False(t, v.Secure())
+
+		b, err := json.Marshal(v)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"fromEnv":"AWS_REGION"}`, string(b))
+
+		newV, err := roundtripValueJSON(v)
+		assert.NoError(t, err)
+		assert.Equal(t, v, newV)
+	})
+
+	t.Run("Map", func(t *testing.T) {
+		t.Parallel()
+
+		var m Map
+		err := yaml.Unmarshal([]byte("aws:region: {fromEnv: AWS_REGION}\n"), &m)
+		assert.NoError(t, err)
+		ref, ok := m[MustMakeKey("aws", "region")].Reference()
+		assert.True(t, ok)
+		assert.Equal(t, Reference{FromEnv: "AWS_REGION"}, ref)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		t.Parallel()
+
+		for _, text := range []string{
+			"fromEnv: A\nfromFile: b\n",
+			"fromEnv: [a]\n",
+			"fromEnv: \"\"\n",
+			"fromEnv: A\nsecret: yes please\n",
+		} {
+			var v Value
+			assert.Error(t, yaml.Unmarshal([]byte(text), &v), text)
+		}
+	})
+
+	t.Run("Objects", func(t *testing.T) {
+		t.Parallel()
+
+		// Maps with keys other than the reserved ones, or without a source, are objects.
+		for _, text := range []string{
+			"fromEnv: A\nother: b\n",
+			"secret: true\n",
+			"reference: {fromEnv: A}\n",
+		} {
+			var v Value
+			err := yaml.Unmarshal([]byte(text), &v)
+			assert.NoError(t, err, text)
+			assert.True(t, v.Object(), text)
+			_, ok := v.Reference()
+			assert.False(t, ok, text)
+		}
+	})
+}
+
+//nolint:paralleltest // uses t.Setenv
+func TestResolvingReferenceValues(t *testing.T) {
+	t.Setenv("PULUMI_TEST_CONFIG_REFERENCE", "us-west-2")
+	path := filepath.Join(t.TempDir(), "cert.pem")
+	assert.NoError(t, os.WriteFile(path, []byte("certificate\n"), 0o600))
+
+	env := NewReferenceValue(Reference{FromEnv: "PULUMI_TEST_CONFIG_REFERENCE"})
+	file := NewReferenceValue(Reference{FromFile: path, Secret: true})
+
+	v, err := env.Value(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "us-west-2", v)
+
+	v, err = file.Value(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "certificate\n", v)
+
+	// Secret references are blinded like encrypted values.
+	v, err = file.Value(NewBlindingDecrypter())
+	assert.NoError(t, err)
+	assert.Equal(t, "[secret]", v)
+
+	secureValues, err := file.SecureValues(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"certificate\n"}, secureValues)
+	secureValues, err = env.SecureValues(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, secureValues)
+
+	// References are copied as they are.
+	copied, err := file.Copy(newPrefixCrypter("stackA"), newPrefixCrypter("stackB"))
+	assert.NoError(t, err)
+	assert.Equal(t, file, copied)
+
+	_, err = NewReferenceValue(Reference{FromEnv: "PULUMI_TEST_CONFIG_REFERENCE_UNSET"}).Value(nil)
+	assert.EqualError(t, err,
+		"environment variable PULUMI_TEST_CONFIG_REFERENCE_UNSET referenced by config is not set")
+
+	_, err = NewReferenceValue(Reference{FromFile: filepath.Join(t.TempDir(), "missing")}).Value(nil)
+	assert.ErrorContains(t, err, "reading file referenced by config")
+}
+
 func roundtripValueYAML(v Value) (Value, error) {
 	return roundtripValue(v, yaml.Marshal, yaml.Unmarshal)
 }
@@ -107,6 +107,14 @@ type Provider interface {
 	GetMapping(key string) ([]byte, string, error)
 }
 
+// ResourceLister is an optional interface that is implemented by providers that can list existing resources so that
+// they can be imported.
+type ResourceLister interface {
+	// ListResources lists the existing resources of the given type that match the given filters. Providers whose
+	// plugins don't serve the ResourceDiscovery service return ErrNotYetImplemented.
+	ListResources(typ tokens.Type, filters map[string]string) ([]ListedResource, error)
+}
+
 type GrpcProvider interface {
 	Provider
 
@@ -122,6 +130,13 @@ type CheckFailure struct {
 	Reason   string               // the reason the property failed to check.
 }
 
+// ListedResource is an existing resource that was listed by a provider.
+type ListedResource struct {
+	ID         resource.ID          // the ID of the resource, which is used to import it.
+	Name       string               // a suggested name for the resource, if any.
+	Properties resource.PropertyMap // properties that describe the resource to users.
+}
+
 // ErrNotYetImplemented may be returned from a provider for optional methods that are not yet implemented.
 var ErrNotYetImplemented = errors.New("NYI")
 
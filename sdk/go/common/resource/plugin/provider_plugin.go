@@ -62,12 +62,13 @@ const kubernetesProviderType = "pulumi:providers:kubernetes"
 
 // provider reflects a resource plugin, loaded dynamically for a single package.
 type provider struct {
-	ctx                    *Context                         // a plugin context for caching, etc.
-	pkg                    tokens.Package                   // the Pulumi package containing this provider's resources.
-	plug                   *plugin                          // the actual plugin process wrapper.
-	clientRaw              pulumirpc.ResourceProviderClient // the raw provider client; usually unsafe to use directly.
-	disableProviderPreview bool                             // true if previews for Create and Update are disabled.
-	legacyPreview          bool                             // enables legacy behavior for unconfigured provider previews.
+	ctx                    *Context                          // a plugin context for caching, etc.
+	pkg                    tokens.Package                    // the Pulumi package containing this provider's resources.
+	plug                   *plugin                           // the actual plugin process wrapper.
+	clientRaw              pulumirpc.ResourceProviderClient  // the raw provider client; usually unsafe to use directly.
+	discoveryRaw           pulumirpc.ResourceDiscoveryClient // the raw discovery client, if any.
+	disableProviderPreview bool                              // true if previews for Create and Update are disabled.
+	legacyPreview          bool                              // enables legacy behavior for unconfigured provider previews.
 
 	// Await and provide configuration values.
 	// Use of closures here prevents unintentional unguarded access
@@ -260,6 +261,7 @@ func NewProviderFromPath(host Host, ctx *Context, path string) (Provider, error)
 		ctx:           ctx,
 		plug:          plug,
 		clientRaw:     pulumirpc.NewResourceProviderClient(plug.Conn),
+		discoveryRaw:  pulumirpc.NewResourceDiscoveryClient(plug.Conn),
 		legacyPreview: legacyPreview,
 		awaitConfig:   cfgPromise.Await,
 		provideConfig: cfgPromise.Fulfill,
@@ -277,12 +279,21 @@ func NewProviderFromPath(host Host, ctx *Context, path string) (Provider, error)
 
 func NewProviderWithClient(ctx *Context, pkg tokens.Package, client pulumirpc.ResourceProviderClient,
 	disableProviderPreview bool,
+) Provider {
+	return NewProviderWithClients(ctx, pkg, client, nil, disableProviderPreview)
+}
+
+// NewProviderWithClients is like NewProviderWithClient, but also accepts a client for the provider's optional
+// ResourceDiscovery service. If discovery is nil, the provider can't list resources.
+func NewProviderWithClients(ctx *Context, pkg tokens.Package, client pulumirpc.ResourceProviderClient,
+	discovery pulumirpc.ResourceDiscoveryClient, disableProviderPreview bool,
 ) Provider {
 	cfgPromise := newPluginConfigPromise()
 	return &provider{
 		ctx:                    ctx,
 		pkg:                    pkg,
 		clientRaw:              client,
+		discoveryRaw:           discovery,
 		disableProviderPreview: disableProviderPreview,
 		awaitConfig:            cfgPromise.Await,
 		provideConfig:          cfgPromise.Fulfill,
@@ -1819,3 +1830,51 @@ func (p *provider) GetMapping(key string) ([]byte, string, error) {
 	}
 	return resp.Data, resp.Provider, nil
 }
+
+// ListResources lists the existing resources of the given type that match the given filters, using the provider's
+// optional ResourceDiscovery service. If the provider doesn't serve it, ErrNotYetImplemented is returned.
+func (p *provider) ListResources(typ tokens.Type, filters map[string]string) ([]ListedResource, error) {
+	label := fmt.Sprintf("%s.ListResources(%s)", p.label(), typ)
+	logging.V(7).Infof("%s executing (#filters=%d)", label, len(filters))
+
+	if p.discoveryRaw == nil {
+		return nil, ErrNotYetImplemented
+	}
+
+	// Ensure that the plugin is configured.
+	if _, err := p.awaitConfig(context.Background()); err != nil {
+		return nil, err
+	}
+
+	resp, err := p.discoveryRaw.ListResources(p.requestContext(), &pulumirpc.ListResourcesRequest{
+		Type:    string(typ),
+		Filters: filters,
+	})
+	if err != nil {
+		rpcError := rpcerror.Convert(err)
+		if rpcError.Code() == codes.Unimplemented {
+			return nil, ErrNotYetImplemented
+		}
+		logging.V(7).Infof("%s failed: %v", label, rpcError)
+		return nil, rpcError
+	}
+
+	resources := make([]ListedResource, len(resp.GetResources()))
+	for i, res := range resp.GetResources() {
+		props, err := UnmarshalProperties(res.GetProperties(), MarshalOptions{
+			Label:          fmt.Sprintf("%s.properties", label),
+			RejectUnknowns: true,
+			KeepSecrets:    true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		resources[i] = ListedResource{
+			ID:         resource.ID(res.GetId()),
+			Name:       res.GetName(),
+			Properties: props,
+		}
+	}
+	logging.V(7).Infof("%s success: #resources=%d", label, len(resources))
+	return resources, nil
+}
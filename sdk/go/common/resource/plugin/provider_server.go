@@ -633,3 +633,44 @@ func (p *providerServer) GetMapping(ctx context.Context,
 	}
 	return &pulumirpc.GetMappingResponse{Data: data, Provider: provider}, nil
 }
+
+type resourceDiscoveryServer struct {
+	pulumirpc.UnsafeResourceDiscoveryServer // opt out of forward compat
+
+	provider ResourceLister
+}
+
+// NewResourceDiscoveryServer returns a server for the optional ResourceDiscovery service of the given provider, which
+// is served alongside the server returned by NewProviderServer.
+func NewResourceDiscoveryServer(provider ResourceLister) pulumirpc.ResourceDiscoveryServer {
+	return &resourceDiscoveryServer{provider: provider}
+}
+
+func (p *resourceDiscoveryServer) ListResources(ctx context.Context,
+	req *pulumirpc.ListResourcesRequest,
+) (*pulumirpc.ListResourcesResponse, error) {
+	resources, err := p.provider.ListResources(tokens.Type(req.GetType()), req.GetFilters())
+	if err != nil {
+		if err == ErrNotYetImplemented {
+			return nil, status.Error(codes.Unimplemented, "ListResources is not yet implemented")
+		}
+		return nil, err
+	}
+
+	rpcResources := make([]*pulumirpc.ListedResource, len(resources))
+	for i, res := range resources {
+		props, err := MarshalProperties(res.Properties, MarshalOptions{
+			Label:       "ListResources.properties",
+			KeepSecrets: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		rpcResources[i] = &pulumirpc.ListedResource{
+			Id:         string(res.ID),
+			Name:       res.Name,
+			Properties: props,
+		}
+	}
+	return &pulumirpc.ListResourcesResponse{Resources: rpcResources}, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"io"
+)
+
+type InitializeSecretsRequest struct {
+	Args  map[string]string
+	State string
+}
+
+type InitializeSecretsResponse struct {
+	State string
+}
+
+type EncryptSecretsRequest struct {
+	Args       map[string]string
+	State      string
+	Plaintexts []string
+}
+
+type EncryptSecretsResponse struct {
+	Ciphertexts []string
+}
+
+type DecryptSecretsRequest struct {
+	Args        map[string]string
+	State       string
+	Ciphertexts []string
+}
+
+type DecryptSecretsResponse struct {
+	Plaintexts []string
+}
+
+// SecretsManager is a plugin that encrypts and decrypts the secrets of a stack.
+type SecretsManager interface {
+	io.Closer
+
+	Initialize(ctx context.Context, req *InitializeSecretsRequest) (*InitializeSecretsResponse, error)
+
+	Encrypt(ctx context.Context, req *EncryptSecretsRequest) (*EncryptSecretsResponse, error)
+
+	Decrypt(ctx context.Context, req *DecryptSecretsRequest) (*DecryptSecretsResponse, error)
+}
@@ -0,0 +1,168 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/blang/semver"
+	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil/rpcerror"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+// secretsManager reflects a secrets plugin, loaded dynamically from another process over gRPC.
+type secretsManager struct {
+	name      string
+	plug      *plugin                        // the actual plugin process wrapper.
+	clientRaw pulumirpc.SecretsManagerClient // the raw secrets client; usually unsafe to use directly.
+}
+
+func NewSecretsManager(ctx *Context, name string, version *semver.Version) (SecretsManager, error) {
+	prefix := fmt.Sprintf("%v (secrets)", name)
+
+	// Load the plugin's path by using the standard workspace logic.
+	path, err := workspace.GetPluginPath(workspace.SecretsPlugin, name, version, ctx.Host.GetProjectPlugins())
+	if err != nil {
+		return nil, err
+	}
+
+	contract.Assertf(path != "", "unexpected empty path for plugin %s", name)
+
+	plug, err := newPlugin(ctx, ctx.Pwd, path, prefix,
+		workspace.SecretsPlugin, []string{}, os.Environ(), secretsPluginDialOptions(ctx, name, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	contract.Assertf(plug != nil, "unexpected nil secrets plugin for %s", name)
+
+	return &secretsManager{
+		name:      name,
+		plug:      plug,
+		clientRaw: pulumirpc.NewSecretsManagerClient(plug.Conn),
+	}, nil
+}
+
+func secretsPluginDialOptions(ctx *Context, name string, path string) []grpc.DialOption {
+	dialOpts := append(
+		rpcutil.OpenTracingInterceptorDialOptions(otgrpc.SpanDecorator(decorateProviderSpans)),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		rpcutil.GrpcChannelOptions(),
+	)
+
+	if ctx.DialOptions != nil {
+		metadata := map[string]interface{}{
+			"mode": "client",
+			"kind": "secrets",
+		}
+		if name != "" {
+			metadata["name"] = name
+		}
+		if path != "" {
+			metadata["path"] = path
+		}
+		dialOpts = append(dialOpts, ctx.DialOptions(metadata)...)
+	}
+
+	return dialOpts
+}
+
+// label returns a base label for tracing functions.
+func (s *secretsManager) label() string {
+	return fmt.Sprintf("SecretsManager[%s, %p]", s.name, s)
+}
+
+func (s *secretsManager) Close() error {
+	if s.plug == nil {
+		return nil
+	}
+	return s.plug.Close()
+}
+
+func (s *secretsManager) Initialize(ctx context.Context,
+	req *InitializeSecretsRequest,
+) (*InitializeSecretsResponse, error) {
+	label := fmt.Sprintf("%s.Initialize", s.label())
+	logging.V(7).Infof("%s executing", label)
+
+	resp, err := s.clientRaw.Initialize(ctx, &pulumirpc.InitializeSecretsRequest{
+		Args:  req.Args,
+		State: req.State,
+	})
+	if err != nil {
+		rpcError := rpcerror.Convert(err)
+		logging.V(8).Infof("%s secrets manager received rpc error `%s`: `%s`", label, rpcError.Code(), rpcError.Message())
+		return nil, err
+	}
+
+	logging.V(7).Infof("%s success", label)
+	return &InitializeSecretsResponse{State: resp.State}, nil
+}
+
+func (s *secretsManager) Encrypt(ctx context.Context, req *EncryptSecretsRequest) (*EncryptSecretsResponse, error) {
+	label := fmt.Sprintf("%s.Encrypt", s.label())
+	logging.V(7).Infof("%s executing (#plaintexts=%d)", label, len(req.Plaintexts))
+
+	resp, err := s.clientRaw.Encrypt(ctx, &pulumirpc.EncryptSecretsRequest{
+		Args:       req.Args,
+		State:      req.State,
+		Plaintexts: req.Plaintexts,
+	})
+	if err != nil {
+		rpcError := rpcerror.Convert(err)
+		logging.V(8).Infof("%s secrets manager received rpc error `%s`: `%s`", label, rpcError.Code(), rpcError.Message())
+		return nil, err
+	}
+	if len(resp.Ciphertexts) != len(req.Plaintexts) {
+		return nil, fmt.Errorf("secrets plugin %s returned %d ciphertexts for %d plaintexts",
+			s.name, len(resp.Ciphertexts), len(req.Plaintexts))
+	}
+
+	logging.V(7).Infof("%s success", label)
+	return &EncryptSecretsResponse{Ciphertexts: resp.Ciphertexts}, nil
+}
+
+func (s *secretsManager) Decrypt(ctx context.Context, req *DecryptSecretsRequest) (*DecryptSecretsResponse, error) {
+	label := fmt.Sprintf("%s.Decrypt", s.label())
+	logging.V(7).Infof("%s executing (#ciphertexts=%d)", label, len(req.Ciphertexts))
+
+	resp, err := s.clientRaw.Decrypt(ctx, &pulumirpc.DecryptSecretsRequest{
+		Args:        req.Args,
+		State:       req.State,
+		Ciphertexts: req.Ciphertexts,
+	})
+	if err != nil {
+		rpcError := rpcerror.Convert(err)
+		logging.V(8).Infof("%s secrets manager received rpc error `%s`: `%s`", label, rpcError.Code(), rpcError.Message())
+		return nil, err
+	}
+	if len(resp.Plaintexts) != len(req.Ciphertexts) {
+		return nil, fmt.Errorf("secrets plugin %s returned %d plaintexts for %d ciphertexts",
+			s.name, len(resp.Plaintexts), len(req.Ciphertexts))
+	}
+
+	logging.V(7).Infof("%s success", label)
+	return &DecryptSecretsResponse{Plaintexts: resp.Plaintexts}, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+type secretsManagerServer struct {
+	pulumirpc.UnsafeSecretsManagerServer // opt out of forward compat
+
+	manager SecretsManager
+}
+
+func NewSecretsManagerServer(manager SecretsManager) pulumirpc.SecretsManagerServer {
+	return &secretsManagerServer{manager: manager}
+}
+
+func (s *secretsManagerServer) Initialize(ctx context.Context,
+	req *pulumirpc.InitializeSecretsRequest,
+) (*pulumirpc.InitializeSecretsResponse, error) {
+	resp, err := s.manager.Initialize(ctx, &InitializeSecretsRequest{
+		Args:  req.Args,
+		State: req.State,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.InitializeSecretsResponse{State: resp.State}, nil
+}
+
+func (s *secretsManagerServer) Encrypt(ctx context.Context,
+	req *pulumirpc.EncryptSecretsRequest,
+) (*pulumirpc.EncryptSecretsResponse, error) {
+	resp, err := s.manager.Encrypt(ctx, &EncryptSecretsRequest{
+		Args:       req.Args,
+		State:      req.State,
+		Plaintexts: req.Plaintexts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.EncryptSecretsResponse{Ciphertexts: resp.Ciphertexts}, nil
+}
+
+func (s *secretsManagerServer) Decrypt(ctx context.Context,
+	req *pulumirpc.DecryptSecretsRequest,
+) (*pulumirpc.DecryptSecretsResponse, error) {
+	resp, err := s.manager.Decrypt(ctx, &DecryptSecretsRequest{
+		Args:        req.Args,
+		State:       req.State,
+		Ciphertexts: req.Ciphertexts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.DecryptSecretsResponse{Plaintexts: resp.Plaintexts}, nil
+}
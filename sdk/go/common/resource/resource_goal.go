@@ -43,6 +43,8 @@ type Goal struct {
 	// if set, the providers Delete method will not be called for this resource
 	// if specified resource is being deleted as well.
 	DeletedWith URN
+	// if set, the policy for retrying failed provider operations on this resource.
+	RetryPolicy *RetryPolicy
 }
 
 // NewGoal allocates a new resource goal state.
@@ -0,0 +1,97 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// RetryPolicy describes how the engine retries a provider operation on a resource that fails with a transient error.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int `json:"maxAttempts,omitempty" yaml:"maxAttempts,omitempty"`
+	// Backoff is the delay before the first retry, in seconds. The delay doubles after each retry.
+	Backoff float64 `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+	// MaxBackoff is the maximum delay between retries, in seconds. Zero means that the delay is unbounded.
+	MaxBackoff float64 `json:"maxBackoff,omitempty" yaml:"maxBackoff,omitempty"`
+	// RetryableErrors are regular expressions matching the errors that should be retried. All errors are retried if
+	// empty. Errors are never retried because of invalid patterns.
+	RetryableErrors []string `json:"retryableErrors,omitempty" yaml:"retryableErrors,omitempty"`
+}
+
+// compiledPattern is the result of compiling a RetryableErrors pattern.
+type compiledPattern struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// retryableErrorPatterns caches the compiled RetryableErrors patterns of all policies, so that each pattern is
+// compiled once rather than each time an error is checked.
+var retryableErrorPatterns sync.Map // map[string]compiledPattern
+
+// compileRetryableError returns the compiled form of the given pattern, or an error if the pattern is invalid.
+func compileRetryableError(pattern string) (*regexp.Regexp, error) {
+	compiled, ok := retryableErrorPatterns.Load(pattern)
+	if !ok {
+		re, err := regexp.Compile(pattern)
+		compiled, _ = retryableErrorPatterns.LoadOrStore(pattern, compiledPattern{re: re, err: err})
+	}
+	c := compiled.(compiledPattern)
+	return c.re, c.err
+}
+
+// Validate returns an error if any of the RetryableErrors patterns of the policy is invalid.
+func (p *RetryPolicy) Validate() error {
+	for _, pattern := range p.RetryableErrors {
+		if _, err := compileRetryableError(pattern); err != nil {
+			return fmt.Errorf("invalid retryable error pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Retryable returns true if an operation that failed with the given error after the given number of attempts should
+// be retried. Invalid RetryableErrors patterns don't match any errors.
+func (p *RetryPolicy) Retryable(err error, attempts int) bool {
+	if p == nil || err == nil || attempts >= p.MaxAttempts {
+		return false
+	}
+	if len(p.RetryableErrors) == 0 {
+		return true
+	}
+	msg := err.Error()
+	for _, pattern := range p.RetryableErrors {
+		if re, err := compileRetryableError(pattern); err == nil && re.MatchString(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// Delay returns how long to wait before retrying an operation that failed after the given number of attempts.
+func (p *RetryPolicy) Delay(attempts int) time.Duration {
+	if p == nil || attempts < 1 {
+		return 0
+	}
+	seconds := p.Backoff * math.Pow(2, float64(attempts-1))
+	if p.MaxBackoff > 0 && seconds > p.MaxBackoff {
+		seconds = p.MaxBackoff
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,73 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resource
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	t.Parallel()
+
+	var nilPolicy *RetryPolicy
+	assert.False(t, nilPolicy.Retryable(errors.New("boom"), 1))
+
+	// Without patterns, all errors are retried until the policy runs out of attempts.
+	policy := &RetryPolicy{MaxAttempts: 3}
+	require.NoError(t, policy.Validate())
+	assert.True(t, policy.Retryable(errors.New("boom"), 2))
+	assert.False(t, policy.Retryable(errors.New("boom"), 3))
+	assert.False(t, policy.Retryable(nil, 1))
+
+	// With patterns, only the errors that match one of them are retried.
+	policy = &RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{"^timeout", "rate exceeded"}}
+	require.NoError(t, policy.Validate())
+	assert.True(t, policy.Retryable(errors.New("timeout waiting for resource"), 1))
+	assert.True(t, policy.Retryable(errors.New("request rate exceeded"), 1))
+	assert.False(t, policy.Retryable(errors.New("resource timeout"), 1))
+
+	// The patterns are compiled once, when they are first used.
+	re, err := compileRetryableError("^timeout")
+	require.NoError(t, err)
+	cached, err := compileRetryableError("^timeout")
+	require.NoError(t, err)
+	assert.Same(t, re, cached)
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{MaxAttempts: 2, RetryableErrors: []string{"^timeout", "("}}
+	assert.ErrorContains(t, policy.Validate(), `invalid retryable error pattern "("`)
+
+	// Policies that weren't validated, e.g. from older checkpoints, don't retry errors because of invalid patterns.
+	assert.True(t, policy.Retryable(errors.New("timeout waiting for resource"), 1))
+	assert.False(t, policy.Retryable(errors.New("("), 1))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{Backoff: 1, MaxBackoff: 3}
+	assert.Equal(t, time.Duration(0), policy.Delay(0))
+	assert.Equal(t, time.Second, policy.Delay(1))
+	assert.Equal(t, 2*time.Second, policy.Delay(2))
+	assert.Equal(t, 3*time.Second, policy.Delay(3))
+}
@@ -51,6 +51,27 @@ type (
 	StackConfigValidator = func(StackName, ProjectConfigKey, ProjectConfigType, config.Value, config.Decrypter) error
 )
 
+// ConfigValidationError is returned when a stack's config value doesn't match the type or the constraints that the
+// project declares for its key.
+type ConfigValidationError struct {
+	// Stack is the name of the stack.
+	Stack StackName
+	// Key is the project config key of the value.
+	Key ProjectConfigKey
+	// Path is the path of the offending value within the config value, or empty if it is the whole value.
+	Path string
+	// Message describes the violated constraint, e.g. "must be of type 'integer'".
+	Message string
+}
+
+func (e *ConfigValidationError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("Stack '%v' with configuration key '%v' has an invalid value at '%v': it %v",
+			e.Stack, e.Key, e.Path, e.Message)
+	}
+	return fmt.Sprintf("Stack '%v' with configuration key '%v' %v", e.Stack, e.Key, e.Message)
+}
+
 func DefaultStackConfigValidator(
 	stackName string,
 	projectConfigKey string,
@@ -61,13 +82,25 @@ func DefaultStackConfigValidator(
 	// First check if the project says this should be secret, and if so that the stack value is
 	// secure.
 	if projectConfigType.Secret && !stackValue.Secure() {
-		validationError := fmt.Errorf(
-			"Stack '%v' with configuration key '%v' must be encrypted as it's secret",
-			stackName,
-			projectConfigKey)
-		return validationError
+		return &ConfigValidationError{
+			Stack:   stackName,
+			Key:     projectConfigKey,
+			Message: "must be encrypted as it's secret",
+		}
 	}
 
+	return validateStackConfigValue(stackName, projectConfigKey, projectConfigType, stackValue, dec)
+}
+
+// validateStackConfigValue checks a stack's config value against the type and the constraints that the project
+// declares for its key.
+func validateStackConfigValue(
+	stackName string,
+	projectConfigKey string,
+	projectConfigType ProjectConfigType,
+	stackValue config.Value,
+	dec config.Decrypter,
+) error {
 	value, err := stackValue.Value(dec)
 	if err != nil {
 		return err
@@ -83,18 +116,63 @@ func DefaultStackConfigValidator(
 
 	if !ValidateConfigValue(*projectConfigType.Type, projectConfigType.Items, content) {
 		typeName := InferFullTypeName(*projectConfigType.Type, projectConfigType.Items)
-		validationError := fmt.Errorf(
-			"Stack '%v' with configuration key '%v' must be of type '%v'",
-			stackName,
-			projectConfigKey,
-			typeName)
+		return &ConfigValidationError{
+			Stack:   stackName,
+			Key:     projectConfigKey,
+			Message: fmt.Sprintf("must be of type '%v'", typeName),
+		}
+	}
 
-		return validationError
+	constraints := projectConfigType.ConstraintsFor(stackName)
+	if path, message := validateConfigConstraints(
+		"", *projectConfigType.Type, projectConfigType.Items, constraints, content,
+	); message != "" {
+		return &ConfigValidationError{
+			Stack:   stackName,
+			Key:     projectConfigKey,
+			Path:    path,
+			Message: message,
+		}
 	}
 
 	return nil
 }
 
+// ValidateStackConfigValue checks a value of the given key of the stack's config against the type and the constraints
+// that the project declares for the key, if any. Unlike the validation of the whole stack config, it doesn't require
+// the value to be encrypted if the key is secret, so that values can be checked before they are encrypted.
+func ValidateStackConfigValue(
+	stackName string,
+	project *Project,
+	key config.Key,
+	stackValue config.Value,
+	dec config.Decrypter,
+) error {
+	for projectConfigKey, projectConfigType := range project.Config {
+		k, err := parseProjectConfigKey(project.Name.String(), projectConfigKey)
+		if err != nil {
+			return err
+		}
+		if k != key || !projectConfigType.IsExplicitlyTyped() {
+			continue
+		}
+		return validateStackConfigValue(stackName, projectConfigKey, projectConfigType, stackValue, dec)
+	}
+	return nil
+}
+
+// parseProjectConfigKey returns the stack config key of the given project config key, which is namespaced by the
+// project unless it specifies another namespace.
+func parseProjectConfigKey(projectName string, projectConfigKey string) (config.Key, error) {
+	if strings.Contains(projectConfigKey, ":") {
+		// key is already namespaced
+		return config.ParseKey(projectConfigKey)
+	}
+	// key is not namespaced
+	// use the project as default namespace
+	return config.MustMakeKey(projectName, projectConfigKey), nil
+}
+
 // The validator which does not validate anything
 // used when we only want to merge the project config onto the stack config
 func NoopStackConfigValidator(
@@ -134,19 +212,9 @@ func ValidateStackConfigAndMergeProjectConfig(
 	missingConfigurationKeys := make([]string, 0)
 	projectName := project.Name.String()
 	for projectConfigKey, projectConfigType := range project.Config {
-		var key config.Key
-		if strings.Contains(projectConfigKey, ":") {
-			// key is already namespaced
-			parsedKey, parseError := config.ParseKey(projectConfigKey)
-			if parseError != nil {
-				return parseError
-			}
-
-			key = parsedKey
-		} else {
-			// key is not namespaced
-			// use the project as default namespace
-			key = config.MustMakeKey(projectName, projectConfigKey)
+		key, parseError := parseProjectConfigKey(projectName, projectConfigKey)
+		if parseError != nil {
+			return parseError
 		}
 
 		stackValue, foundOnStack, err := stackConfig.Get(key, true)
@@ -240,3 +308,21 @@ func ApplyProjectConfig(stackName string, project *Project, stackConfig config.M
 	return ValidateStackConfigAndMergeProjectConfig(stackName, project, stackConfig,
 		emptyDecrypter, NoopStackConfigValidator)
 }
+
+// MergeConfigLayers merges the config of the given layers and the stack's own config in order of increasing
+// precedence: later values replace earlier values of the same keys. It also returns the paths of the layers that the
+// inherited keys come from.
+func MergeConfigLayers(layers []ConfigLayer, stackConfig config.Map) (config.Map, map[config.Key]string) {
+	merged, origins := make(config.Map), make(map[config.Key]string)
+	for _, layer := range layers {
+		for k, v := range layer.Stack.Config {
+			merged[k] = v
+			origins[k] = layer.Path
+		}
+	}
+	for k, v := range stackConfig {
+		merged[k] = v
+		delete(origins, k)
+	}
+	return merged, origins
+}
@@ -15,8 +15,10 @@
 package workspace
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
@@ -199,9 +201,66 @@ func LoadProjectStack(project *Project, path string) (*ProjectStack, error) {
 	}
 
 	projectStack.raw = b
+	projectStack.path = path
 	return &projectStack, nil
 }
 
+// ConfigLayer is a config file that a stack inherits config from.
+type ConfigLayer struct {
+	// Path is the path of the file.
+	Path string
+	// Stack is the contents of the file, including the settings of the secrets manager that encrypted its secrets.
+	Stack *ProjectStack
+}
+
+// LoadConfigLayers loads the files that the stack extends, and the files that those extend in turn, in order of
+// increasing precedence: each file comes after the files that it extends, and the files that a stack extends come in
+// the order in which they are listed. Files that are extended more than once are only loaded the first time.
+func (ps *ProjectStack) LoadConfigLayers(project *Project) ([]ConfigLayer, error) {
+	var layers []ConfigLayer
+	loaded, loading := map[string]bool{}, map[string]bool{}
+
+	var load func(stack *ProjectStack, path string) error
+	load = func(stack *ProjectStack, path string) error {
+		loading[path] = true
+		defer delete(loading, path)
+
+		for _, extended := range stack.Extends {
+			extendedPath := filepath.Clean(filepath.Join(filepath.Dir(path), extended))
+			if loading[extendedPath] {
+				return fmt.Errorf("config file '%s' extends itself through '%s'", extendedPath, path)
+			}
+			if loaded[extendedPath] {
+				continue
+			}
+
+			if _, err := os.Stat(extendedPath); err != nil {
+				return fmt.Errorf("could not load config file '%s' extended by '%s': %w", extended, path, err)
+			}
+			extendedStack, err := LoadProjectStack(project, extendedPath)
+			if err != nil {
+				return fmt.Errorf("could not load config file '%s' extended by '%s': %w", extended, path, err)
+			}
+			if err := load(extendedStack, extendedPath); err != nil {
+				return err
+			}
+
+			loaded[extendedPath] = true
+			layers = append(layers, ConfigLayer{Path: extendedPath, Stack: extendedStack})
+		}
+		return nil
+	}
+
+	if len(ps.Extends) > 0 && ps.path == "" {
+		return nil, errors.New("the config files that the stack extends can only be loaded if the stack was " +
+			"loaded from a file")
+	}
+	if err := load(ps, filepath.Clean(ps.path)); err != nil {
+		return nil, err
+	}
+	return layers, nil
+}
+
 // LoadPluginProject reads a plugin project definition from a file.
 func LoadPluginProject(path string) (*PluginProject, error) {
 	contract.Requiref(path != "", "path", "must not be empty")
@@ -0,0 +1,182 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// OutputValidationError is returned when a stack's output doesn't match the type that the project declares for it.
+type OutputValidationError struct {
+	// Name is the name of the output.
+	Name string
+	// Path is the path of the offending value within the output, or empty if it is the whole output.
+	Path string
+	// Message describes the violated constraint, e.g. "must be of type 'integer'".
+	Message string
+}
+
+func (e *OutputValidationError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("output '%v' has an invalid value at '%v': it %v", e.Name, e.Path, e.Message)
+	}
+	return fmt.Sprintf("output '%v' %v", e.Name, e.Message)
+}
+
+// MarshalOutputTypes returns the given output types as a property value, so that they can be published alongside the
+// outputs of a stack.
+func MarshalOutputTypes(outputTypes map[string]ProjectOutputType) (resource.PropertyValue, error) {
+	b, err := json.Marshal(outputTypes)
+	if err != nil {
+		return resource.PropertyValue{}, err
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return resource.PropertyValue{}, err
+	}
+	return resource.NewObjectProperty(resource.NewPropertyMapFromMap(obj)), nil
+}
+
+// UnmarshalOutputTypes returns the output types that were marshaled by MarshalOutputTypes.
+func UnmarshalOutputTypes(v resource.PropertyValue) (map[string]ProjectOutputType, error) {
+	if !v.IsObject() {
+		return nil, fmt.Errorf("expected output types to be an object, got %v", v.TypeString())
+	}
+	b, err := json.Marshal(v.Mappable())
+	if err != nil {
+		return nil, err
+	}
+	var outputTypes map[string]ProjectOutputType
+	if err := json.Unmarshal(b, &outputTypes); err != nil {
+		return nil, err
+	}
+	return outputTypes, nil
+}
+
+// ValidateStackOutputs checks the outputs of a stack against the types that its project declares for them. Outputs
+// that aren't declared are allowed, and outputs that aren't known yet, e.g. during previews, are not checked. All
+// mismatches are reported in the returned error.
+func ValidateStackOutputs(outputTypes map[string]ProjectOutputType, outputs resource.PropertyMap) error {
+	names := make([]string, 0, len(outputTypes))
+	for name := range outputTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result *multierror.Error
+	for _, name := range names {
+		if err := validateStackOutput(name, outputTypes[name], outputs); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+func validateStackOutput(name string, outputType ProjectOutputType, outputs resource.PropertyMap) error {
+	v, ok := outputs[resource.PropertyKey(name)]
+	if !ok || v.IsNull() {
+		if outputType.Optional {
+			return nil
+		}
+		return &OutputValidationError{Name: name, Message: "is missing"}
+	}
+	if v.ContainsUnknowns() {
+		return nil
+	}
+	if outputType.Secret && !v.ContainsSecrets() {
+		return &OutputValidationError{Name: name, Message: "must be a secret"}
+	}
+
+	value := outputValue(v)
+	if !validateOutputValue(outputType.Type, outputType.Items, value) {
+		return &OutputValidationError{
+			Name:    name,
+			Message: fmt.Sprintf("must be of type '%v'", InferFullTypeName(outputType.Type, outputType.Items)),
+		}
+	}
+	if path, message := validateConfigConstraints(
+		"", outputType.Type, outputType.Items, outputType.ProjectConfigConstraints, value,
+	); message != "" {
+		return &OutputValidationError{Name: name, Path: path, Message: message}
+	}
+	return nil
+}
+
+// outputValue returns the plain value of an output, with its secrets revealed, in the shape of a config value.
+func outputValue(v resource.PropertyValue) interface{} {
+	switch {
+	case v.IsSecret():
+		return outputValue(v.SecretValue().Element)
+	case v.IsOutput():
+		return outputValue(v.OutputValue().Element)
+	case v.IsArray():
+		items := make([]interface{}, len(v.ArrayValue()))
+		for i, item := range v.ArrayValue() {
+			items[i] = outputValue(item)
+		}
+		return items
+	case v.IsObject():
+		obj := make(map[string]interface{}, len(v.ObjectValue()))
+		for k, p := range v.ObjectValue() {
+			obj[string(k)] = outputValue(p)
+		}
+		return obj
+	default:
+		return v.Mappable()
+	}
+}
+
+// validateOutputValue checks the type of an output value. Unlike config values, which are often strings, outputs
+// keep their types, so it doesn't allow e.g. strings of integers for integers.
+func validateOutputValue(typeName string, itemsType *ProjectConfigItemsType, value interface{}) bool {
+	switch typeName {
+	case stringTypeName:
+		_, ok := value.(string)
+		return ok
+	case integerTypeName:
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case numberTypeName:
+		_, ok := value.(float64)
+		return ok
+	case booleanTypeName:
+		_, ok := value.(bool)
+		return ok
+	case objectTypeName:
+		// the types of the properties are checked alongside the other constraints of objects
+		_, ok := value.(map[string]interface{})
+		return ok
+	case arrayTypeName:
+		items, ok := value.([]interface{})
+		if !ok || itemsType == nil {
+			return false
+		}
+		for _, item := range items {
+			if !validateOutputValue(itemsType.Type, itemsType.Items, item) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
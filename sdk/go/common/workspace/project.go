@@ -23,8 +23,11 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
@@ -32,6 +35,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -39,6 +43,9 @@ const (
 	integerTypeName = "integer"
 	stringTypeName  = "string"
 	booleanTypeName = "boolean"
+	objectTypeName  = "object"
+	// numberTypeName is only used by outputs: config values that are numbers are declared as integers.
+	numberTypeName = "number"
 )
 
 //go:embed project.json
@@ -91,6 +98,9 @@ type ProjectBackend struct {
 type ProjectOptions struct {
 	// Refresh is the ability to always run a refresh as part of a pulumi update / preview / destroy
 	Refresh string `json:"refresh,omitempty" yaml:"refresh,omitempty"`
+	// ProviderParallelism limits the number of concurrent resource operations performed by the providers of each
+	// package, e.g. for providers backed by a rate-limited API.
+	ProviderParallelism map[string]int `json:"providerParallelism,omitempty" yaml:"providerParallelism,omitempty"`
 }
 
 type PluginOptions struct {
@@ -105,9 +115,73 @@ type Plugins struct {
 	Analyzers []PluginOptions `json:"analyzers,omitempty" yaml:"analyzers,omitempty"`
 }
 
+// ProjectConfigConstraints are the constraints on a config value beyond its type, which follow the keywords of JSON
+// schema of the same names.
+type ProjectConfigConstraints struct {
+	// Enum is the list of allowed values.
+	Enum []interface{} `json:"enum,omitempty" yaml:"enum,omitempty"`
+	// Minimum and Maximum are the inclusive bounds of integer values.
+	Minimum *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	// MinLength and MaxLength are the bounds of the lengths of string values.
+	MinLength *int `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength *int `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	// Pattern is a regular expression that string values must match.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// MinItems and MaxItems are the bounds of the lengths of array values.
+	MinItems *int `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems *int `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	// Properties are the types of the properties of object values.
+	Properties map[string]*ProjectConfigItemsType `json:"properties,omitempty" yaml:"properties,omitempty"`
+	// Required are the properties that object values must have.
+	Required []string `json:"required,omitempty" yaml:"required,omitempty"`
+	// AdditionalProperties is false if object values must not have properties other than Properties.
+	AdditionalProperties *bool `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+}
+
+// override returns the constraints with the fields that are set in the given constraints replaced.
+func (c ProjectConfigConstraints) override(o ProjectConfigConstraints) ProjectConfigConstraints {
+	if o.Enum != nil {
+		c.Enum = o.Enum
+	}
+	if o.Minimum != nil {
+		c.Minimum = o.Minimum
+	}
+	if o.Maximum != nil {
+		c.Maximum = o.Maximum
+	}
+	if o.MinLength != nil {
+		c.MinLength = o.MinLength
+	}
+	if o.MaxLength != nil {
+		c.MaxLength = o.MaxLength
+	}
+	if o.Pattern != "" {
+		c.Pattern = o.Pattern
+	}
+	if o.MinItems != nil {
+		c.MinItems = o.MinItems
+	}
+	if o.MaxItems != nil {
+		c.MaxItems = o.MaxItems
+	}
+	if o.Properties != nil {
+		c.Properties = o.Properties
+	}
+	if o.Required != nil {
+		c.Required = o.Required
+	}
+	if o.AdditionalProperties != nil {
+		c.AdditionalProperties = o.AdditionalProperties
+	}
+	return c
+}
+
 type ProjectConfigItemsType struct {
 	Type  string                  `json:"type,omitempty" yaml:"type,omitempty"`
 	Items *ProjectConfigItemsType `json:"items,omitempty" yaml:"items,omitempty"`
+
+	ProjectConfigConstraints `yaml:",inline"`
 }
 
 type ProjectConfigType struct {
@@ -117,6 +191,33 @@ type ProjectConfigType struct {
 	Default     interface{}             `json:"default,omitempty" yaml:"default,omitempty"`
 	Value       interface{}             `json:"value,omitempty" yaml:"value,omitempty"`
 	Secret      bool                    `json:"secret,omitempty" yaml:"secret,omitempty"`
+
+	ProjectConfigConstraints `yaml:",inline"`
+
+	// Stacks overrides the constraints of the value for individual stacks, keyed by stack name.
+	Stacks map[string]ProjectConfigConstraints `json:"stacks,omitempty" yaml:"stacks,omitempty"`
+}
+
+// ProjectOutputType declares the type of an output of the project's stacks, so that the consumers of the outputs can
+// rely on them.
+type ProjectOutputType struct {
+	Type        string                  `json:"type" yaml:"type"`
+	Description string                  `json:"description,omitempty" yaml:"description,omitempty"`
+	Items       *ProjectConfigItemsType `json:"items,omitempty" yaml:"items,omitempty"`
+	// Secret is true if the output must be a secret.
+	Secret bool `json:"secret,omitempty" yaml:"secret,omitempty"`
+	// Optional is true if stacks don't always have the output.
+	Optional bool `json:"optional,omitempty" yaml:"optional,omitempty"`
+
+	ProjectConfigConstraints `yaml:",inline"`
+}
+
+// ConstraintsFor returns the constraints of the value for the given stack.
+func (configType *ProjectConfigType) ConstraintsFor(stackName string) ProjectConfigConstraints {
+	if o, ok := configType.Stacks[stackName]; ok {
+		return configType.ProjectConfigConstraints.override(o)
+	}
+	return configType.ProjectConfigConstraints
 }
 
 // IsExplicitlyTyped returns whether the project config type is explicitly typed.
@@ -161,6 +262,9 @@ type Project struct {
 	// Config has been renamed to StackConfigDir.
 	Config map[string]ProjectConfigType `json:"config,omitempty" yaml:"config,omitempty"`
 
+	// Outputs declares the types of the outputs of the project's stacks, keyed by output name.
+	Outputs map[string]ProjectOutputType `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+
 	// StackConfigDir indicates where to store the Pulumi.<stack-name>.yaml files, combined with the folder
 	// Pulumi.yaml is in.
 	StackConfigDir string `json:"stackConfigDir,omitempty" yaml:"stackConfigDir,omitempty"`
@@ -422,6 +526,12 @@ func ValidateConfigValue(typeName string, itemsType *ProjectConfigItemsType, val
 		return ok
 	}
 
+	if typeName == objectTypeName {
+		// the types of the properties are checked alongside the other constraints of objects
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+
 	items, isArray := value.([]interface{})
 
 	if !isArray || itemsType == nil {
@@ -440,6 +550,154 @@ func ValidateConfigValue(typeName string, itemsType *ProjectConfigItemsType, val
 	return true
 }
 
+// validateConfigConstraints checks a config value, whose type has already been validated, against the given
+// constraints. It returns the path of the offending value within the config value and a description of the violated
+// constraint, or an empty description if the value satisfies the constraints.
+func validateConfigConstraints(
+	path string,
+	typeName string,
+	itemsType *ProjectConfigItemsType,
+	constraints ProjectConfigConstraints,
+	value interface{},
+) (string, string) {
+	if len(constraints.Enum) > 0 {
+		allowed := make([]string, len(constraints.Enum))
+		found := false
+		for i, e := range constraints.Enum {
+			// Config values are often strings even when they are declared as integers or booleans, so compare the
+			// formatted values.
+			found = found || fmt.Sprint(e) == fmt.Sprint(value)
+			allowed[i] = fmt.Sprintf("'%v'", e)
+		}
+		if !found {
+			return path, "must be one of " + strings.Join(allowed, ", ")
+		}
+	}
+
+	switch typeName {
+	case integerTypeName, numberTypeName:
+		n, ok := configNumber(value)
+		if !ok {
+			return "", ""
+		}
+		if constraints.Minimum != nil && n < *constraints.Minimum {
+			return path, fmt.Sprintf("must be at least %v", *constraints.Minimum)
+		}
+		if constraints.Maximum != nil && n > *constraints.Maximum {
+			return path, fmt.Sprintf("must be at most %v", *constraints.Maximum)
+		}
+	case stringTypeName:
+		str, _ := value.(string)
+		length := utf8.RuneCountInString(str)
+		if constraints.MinLength != nil && length < *constraints.MinLength {
+			return path, fmt.Sprintf("must be at least %d characters long", *constraints.MinLength)
+		}
+		if constraints.MaxLength != nil && length > *constraints.MaxLength {
+			return path, fmt.Sprintf("must be at most %d characters long", *constraints.MaxLength)
+		}
+		if constraints.Pattern != "" {
+			matched, err := regexp.MatchString(constraints.Pattern, str)
+			if err != nil {
+				return path, fmt.Sprintf("has an invalid pattern '%v': %v", constraints.Pattern, err)
+			}
+			if !matched {
+				return path, fmt.Sprintf("must match the pattern '%v'", constraints.Pattern)
+			}
+		}
+	case arrayTypeName:
+		items, _ := value.([]interface{})
+		if constraints.MinItems != nil && len(items) < *constraints.MinItems {
+			return path, fmt.Sprintf("must have at least %d items", *constraints.MinItems)
+		}
+		if constraints.MaxItems != nil && len(items) > *constraints.MaxItems {
+			return path, fmt.Sprintf("must have at most %d items", *constraints.MaxItems)
+		}
+		if itemsType != nil {
+			for i, item := range items {
+				itemPath := fmt.Sprintf("%s[%d]", path, i)
+				if p, message := validateConfigConstraints(
+					itemPath, itemsType.Type, itemsType.Items, itemsType.ProjectConfigConstraints, item,
+				); message != "" {
+					return p, message
+				}
+			}
+		}
+	case objectTypeName:
+		obj, _ := value.(map[string]interface{})
+		for _, name := range constraints.Required {
+			if _, ok := obj[name]; !ok {
+				return path, fmt.Sprintf("is missing the required property '%v'", name)
+			}
+		}
+
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propertyPath := name
+			if path != "" {
+				propertyPath = path + "." + name
+			}
+
+			propertyType, ok := constraints.Properties[name]
+			if !ok {
+				if constraints.AdditionalProperties != nil && !*constraints.AdditionalProperties {
+					return path, fmt.Sprintf("has the unknown property '%v'", name)
+				}
+				continue
+			}
+
+			if !ValidateConfigValue(propertyType.Type, propertyType.Items, obj[name]) {
+				return propertyPath, fmt.Sprintf("must be of type '%v'",
+					InferFullTypeName(propertyType.Type, propertyType.Items))
+			}
+			if p, message := validateConfigConstraints(
+				propertyPath, propertyType.Type, propertyType.Items, propertyType.ProjectConfigConstraints, obj[name],
+			); message != "" {
+				return p, message
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// configNumber returns the numeric value of an integer config value, which may be a string.
+func configNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.Atoi(v)
+		return float64(n), err == nil
+	default:
+		return 0, false
+	}
+}
+
+// validateConfigPatterns checks that the patterns of the given constraints and of the types that they contain are
+// valid regular expressions.
+func validateConfigPatterns(constraints ProjectConfigConstraints, itemsType *ProjectConfigItemsType) error {
+	if constraints.Pattern != "" {
+		if _, err := regexp.Compile(constraints.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern '%v': %w", constraints.Pattern, err)
+		}
+	}
+	for _, propertyType := range constraints.Properties {
+		if err := validateConfigPatterns(propertyType.ProjectConfigConstraints, propertyType.Items); err != nil {
+			return err
+		}
+	}
+	if itemsType != nil {
+		return validateConfigPatterns(itemsType.ProjectConfigConstraints, itemsType.Items)
+	}
+	return nil
+}
+
 func configKeyIsNamespacedByProject(projectName string, configKey string) bool {
 	return !strings.Contains(configKey, ":") || strings.HasPrefix(configKey, projectName+":")
 }
@@ -467,6 +725,17 @@ func (proj *Project) Validate() error {
 					"but does not specify the underlying type via the 'items' attribute", configKey)
 			}
 
+			if configType.IsExplicitlyTyped() {
+				if err := validateConfigPatterns(configType.ProjectConfigConstraints, configType.Items); err != nil {
+					return fmt.Errorf("The configuration key '%v' has an %w", configKey, err)
+				}
+				for stackName, constraints := range configType.Stacks {
+					if err := validateConfigPatterns(constraints, nil); err != nil {
+						return fmt.Errorf("The configuration key '%v' has an %w for stack '%v'", configKey, err, stackName)
+					}
+				}
+			}
+
 			// when we have a config _type_ with a schema
 			if configType.IsExplicitlyTyped() && configType.Default != nil {
 				if !ValidateConfigValue(configTypeName, configType.Items, configType.Default) {
@@ -475,6 +744,14 @@ func (proj *Project) Validate() error {
 						configKey,
 						inferredTypeName)
 				}
+				if path, message := validateConfigConstraints(
+					"", configTypeName, configType.Items, configType.ProjectConfigConstraints, configType.Default,
+				); message != "" {
+					if path != "" {
+						message = fmt.Sprintf("has an invalid value at '%v': it %v", path, message)
+					}
+					return fmt.Errorf("The default value specified for configuration key '%v' %v", configKey, message)
+				}
 			}
 
 		} else {
@@ -499,6 +776,22 @@ func (proj *Project) Validate() error {
 		}
 	}
 
+	for name, outputType := range proj.Outputs {
+		switch outputType.Type {
+		case stringTypeName, integerTypeName, numberTypeName, booleanTypeName, objectTypeName:
+		case arrayTypeName:
+			if outputType.Items == nil {
+				return fmt.Errorf("The output '%v' declares an array "+
+					"but does not specify the underlying type via the 'items' attribute", name)
+			}
+		default:
+			return fmt.Errorf("The output '%v' has an invalid type '%v'", name, outputType.Type)
+		}
+		if err := validateConfigPatterns(outputType.ProjectConfigConstraints, outputType.Items); err != nil {
+			return fmt.Errorf("The output '%v' has an %w", name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -583,17 +876,66 @@ type ProjectStack struct {
 	// EncryptionSalt is this stack's base64 encoded encryption salt.  Only used for
 	// passphrase-based secrets providers.
 	EncryptionSalt string `json:"encryptionsalt,omitempty" yaml:"encryptionsalt,omitempty"`
+	// Extends is an optional list of other stack config files or shared config fragments whose config this stack
+	// inherits. Paths are relative to the directory of this file. Later files take precedence over earlier ones, and
+	// the stack's own config takes precedence over all of them.
+	Extends []string `json:"extends,omitempty" yaml:"extends,omitempty"`
 	// Config is an optional config bag.
 	Config config.Map `json:"config,omitempty" yaml:"config,omitempty"`
 
 	// The original byte representation of the file, used to attempt trivia-preserving edits
 	raw []byte
+	// The path of the file that the stack was loaded from, if any.
+	path string
 }
 
 func (ps ProjectStack) RawValue() []byte {
 	return ps.raw
 }
 
+// ConfigLine returns the line of the given project config key in the file that the stack was loaded from, or 0 if the
+// key isn't found, e.g. because the stack wasn't loaded from a file. Keys may be written in the file with or without
+// the project's namespace.
+func (ps *ProjectStack) ConfigLine(projectName string, projectConfigKey ProjectConfigKey) int {
+	if len(ps.raw) == 0 {
+		return 0
+	}
+	key, err := parseProjectConfigKey(projectName, projectConfigKey)
+	if err != nil {
+		return 0
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(ps.raw, &doc); err != nil || len(doc.Content) == 0 {
+		return 0
+	}
+	configNode := yamlMappingValue(doc.Content[0], "config")
+	if configNode == nil || configNode.Kind != yaml.MappingNode {
+		return 0
+	}
+	for i := 0; i+1 < len(configNode.Content); i += 2 {
+		k := configNode.Content[i]
+		if k.Value == key.String() || (key.Namespace() == projectName && k.Value == key.Name()) {
+			return k.Line
+		}
+	}
+	return 0
+}
+
+// yamlMappingValue returns the value of the given key of a YAML mapping, or nil if the node isn't a mapping or doesn't
+// have the key.
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
 // Save writes a project definition to a file.
 func (ps *ProjectStack) Save(path string) error {
 	contract.Requiref(path != "", "path", "must not be empty")
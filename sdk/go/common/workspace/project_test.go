@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/stretchr/testify/assert"
@@ -871,6 +873,291 @@ config:
 		"Stack 'dev' with configuration key 'importantNumber' must be encrypted as it's secret")
 }
 
+func TestStackConfigConstraintsAreValidated(t *testing.T) {
+	t.Parallel()
+	projectYaml := `
+name: test
+runtime: dotnet
+config:
+  database:
+    type: object
+    required: [name]
+    additionalProperties: false
+    properties:
+      name:
+        type: string
+        pattern: ^[a-z]+$
+      replicas:
+        type: integer
+        minimum: 1
+        maximum: 3
+      tier:
+        type: string
+        enum: [small, large]
+    stacks:
+      prod:
+        required: [name, replicas]
+  zones:
+    type: array
+    items:
+      type: string
+      minLength: 2
+    minItems: 1
+    default: [us]
+`
+
+	cases := []struct {
+		stack    string
+		yaml     string
+		expected string
+	}{
+		{"dev", "config:\n  test:database:\n    name: db\n    tier: small\n", ""},
+		{
+			"dev", "config:\n  test:database:\n    replicas: 2\n",
+			"Stack 'dev' with configuration key 'database' is missing the required property 'name'",
+		},
+		{
+			"dev", "config:\n  test:database:\n    name: DB\n",
+			"Stack 'dev' with configuration key 'database' has an invalid value at 'name': " +
+				"it must match the pattern '^[a-z]+$'",
+		},
+		{
+			"dev", "config:\n  test:database:\n    name: db\n    replicas: 4\n",
+			"at 'replicas': it must be at most 3",
+		},
+		{
+			"dev", "config:\n  test:database:\n    name: db\n    replicas: two\n",
+			"at 'replicas': it must be of type 'integer'",
+		},
+		{
+			"dev", "config:\n  test:database:\n    name: db\n    tier: medium\n",
+			"at 'tier': it must be one of 'small', 'large'",
+		},
+		{
+			"dev", "config:\n  test:database:\n    name: db\n    size: 1\n",
+			"has the unknown property 'size'",
+		},
+		{
+			"prod", "config:\n  test:database:\n    name: db\n",
+			"Stack 'prod' with configuration key 'database' is missing the required property 'replicas'",
+		},
+		{
+			"dev", "config:\n  test:database:\n    name: db\n  test:zones: [us, e]\n",
+			"Stack 'dev' with configuration key 'zones' has an invalid value at '[1]': " +
+				"it must be at least 2 characters long",
+		},
+		{
+			"dev", "config:\n  test:database:\n    name: db\n  test:zones: []\n",
+			"Stack 'dev' with configuration key 'zones' must have at least 1 items",
+		},
+	}
+
+	project, projectError := loadProjectFromText(t, projectYaml)
+	require.NoError(t, projectError, "Should be able to load the project")
+	for _, c := range cases {
+		stack, stackError := loadProjectStackFromText(t, project, c.yaml)
+		require.NoError(t, stackError, "Should be able to read the stack")
+		configError := ValidateStackConfigAndApplyProjectConfig(c.stack, project, stack.Config, config.NewPanicCrypter())
+		if c.expected == "" {
+			assert.NoError(t, configError)
+			continue
+		}
+
+		var validationError *ConfigValidationError
+		if assert.ErrorAs(t, configError, &validationError, c.yaml) {
+			assert.Contains(t, validationError.Error(), c.expected)
+		}
+	}
+}
+
+func TestProjectValidationFailsForInvalidConstraints(t *testing.T) {
+	t.Parallel()
+
+	_, err := loadProjectFromText(t, `
+name: test
+runtime: dotnet
+config:
+  size:
+    type: integer
+    minimum: 1
+    default: 0
+`)
+	assert.ErrorContains(t, err, "The default value specified for configuration key 'size' must be at least 1")
+
+	_, err = loadProjectFromText(t, `
+name: test
+runtime: dotnet
+config:
+  name:
+    type: string
+    pattern: "[a-z"
+`)
+	assert.ErrorContains(t, err, "The configuration key 'name' has an invalid pattern '[a-z'")
+
+	_, err = loadProjectFromText(t, `
+name: test
+runtime: dotnet
+config:
+  name:
+    type: string
+    maxSize: 3
+`)
+	assert.ErrorContains(t, err, "maxSize")
+}
+
+func TestValidateStackConfigValue(t *testing.T) {
+	t.Parallel()
+
+	project, err := loadProjectFromText(t, `
+name: test
+runtime: dotnet
+config:
+  size:
+    type: integer
+    maximum: 10
+    secret: true
+`)
+	require.NoError(t, err)
+
+	key := config.MustMakeKey("test", "size")
+	dec := config.NewPanicCrypter()
+	assert.NoError(t, ValidateStackConfigValue("dev", project, key, config.NewValue("5"), dec))
+	assert.EqualError(t, ValidateStackConfigValue("dev", project, key, config.NewValue("50"), dec),
+		"Stack 'dev' with configuration key 'size' must be at most 10")
+	assert.NoError(t, ValidateStackConfigValue("dev", project, config.MustMakeKey("test", "other"),
+		config.NewValue("50"), dec))
+}
+
+func TestProjectLoadsOutputSchemas(t *testing.T) {
+	t.Parallel()
+
+	project, err := loadProjectFromText(t, `
+name: test
+runtime: dotnet
+outputs:
+  vpcId:
+    type: string
+    description: The ID of the VPC
+  subnetIds:
+    type: array
+    items:
+      type: string
+    minItems: 1
+  password:
+    type: string
+    secret: true
+    optional: true
+`)
+	require.NoError(t, err)
+	assert.Equal(t, ProjectOutputType{Type: "string", Description: "The ID of the VPC"}, project.Outputs["vpcId"])
+	assert.Equal(t, "string", project.Outputs["subnetIds"].Items.Type)
+	assert.Equal(t, 1, *project.Outputs["subnetIds"].MinItems)
+	assert.True(t, project.Outputs["password"].Secret)
+	assert.True(t, project.Outputs["password"].Optional)
+
+	_, err = loadProjectFromText(t, `
+name: test
+runtime: dotnet
+outputs:
+  subnetIds:
+    type: array
+`)
+	assert.ErrorContains(t, err, "items")
+
+	_, err = loadProjectFromText(t, `
+name: test
+runtime: dotnet
+outputs:
+  vpcId:
+    type: string
+    pattern: "[a-z"
+`)
+	assert.ErrorContains(t, err, "The output 'vpcId' has an invalid pattern '[a-z'")
+}
+
+func TestValidateStackOutputs(t *testing.T) {
+	t.Parallel()
+
+	project, err := loadProjectFromText(t, `
+name: test
+runtime: dotnet
+outputs:
+  vpcId:
+    type: string
+    pattern: "^vpc-"
+  port:
+    type: integer
+  ratio:
+    type: number
+    maximum: 1
+  subnetIds:
+    type: array
+    items:
+      type: string
+  password:
+    type: string
+    secret: true
+    optional: true
+`)
+	require.NoError(t, err)
+
+	valid := resource.PropertyMap{
+		"vpcId":     resource.NewStringProperty("vpc-1234"),
+		"port":      resource.NewNumberProperty(80),
+		"ratio":     resource.NewNumberProperty(0.5),
+		"subnetIds": resource.NewArrayProperty([]resource.PropertyValue{resource.NewStringProperty("subnet-1")}),
+		"password":  resource.MakeSecret(resource.NewStringProperty("hunter2")),
+		"other":     resource.NewBoolProperty(true),
+	}
+	assert.NoError(t, ValidateStackOutputs(project.Outputs, valid))
+
+	// Outputs that aren't known yet aren't checked.
+	unknown := valid.Copy()
+	unknown["vpcId"] = resource.MakeComputed(resource.NewStringProperty(""))
+	unknown["subnetIds"] = resource.NewArrayProperty([]resource.PropertyValue{
+		resource.MakeComputed(resource.NewStringProperty("")),
+	})
+	assert.NoError(t, ValidateStackOutputs(project.Outputs, unknown))
+
+	invalid := resource.PropertyMap{
+		"vpcId":     resource.NewStringProperty("subnet-1"),
+		"port":      resource.NewNumberProperty(80.5),
+		"ratio":     resource.NewNumberProperty(1.5),
+		"subnetIds": resource.NewArrayProperty([]resource.PropertyValue{resource.NewNumberProperty(1)}),
+		"password":  resource.NewStringProperty("hunter2"),
+	}
+	err = ValidateStackOutputs(project.Outputs, invalid)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "output 'password' must be a secret")
+	assert.ErrorContains(t, err, "output 'port' must be of type 'integer'")
+	assert.ErrorContains(t, err, "output 'ratio' must be at most 1")
+	assert.ErrorContains(t, err, "output 'subnetIds' must be of type 'array<string>'")
+	assert.ErrorContains(t, err, "output 'vpcId' must match the pattern '^vpc-'")
+
+	err = ValidateStackOutputs(project.Outputs, resource.PropertyMap{})
+	assert.ErrorContains(t, err, "output 'vpcId' is missing")
+	assert.NotContains(t, err.Error(), "password")
+}
+
+func TestProjectStackConfigLine(t *testing.T) {
+	t.Parallel()
+
+	project := &Project{Name: "test"}
+	stack, err := loadProjectStackFromText(t, project, `encryptionsalt: abc
+config:
+  size: 1
+  test:name: db
+  aws:region: us-west-2
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, stack.ConfigLine("test", "size"))
+	assert.Equal(t, 4, stack.ConfigLine("test", "name"))
+	assert.Equal(t, 5, stack.ConfigLine("test", "aws:region"))
+	assert.Equal(t, 0, stack.ConfigLine("test", "missing"))
+	assert.Equal(t, 0, (&ProjectStack{}).ConfigLine("test", "size"))
+}
+
 func TestProjectLoadYAML(t *testing.T) {
 	t.Parallel()
 
@@ -934,6 +1221,21 @@ func TestProjectLoadYAML(t *testing.T) {
 	assert.Equal(t, "", proj.Main)
 }
 
+func TestProjectLoadProviderParallelism(t *testing.T) {
+	t.Parallel()
+
+	proj, err := loadProjectFromText(t, "name: project\nruntime: test\noptions:\n  providerParallelism:\n    aws: 4\n")
+	require.NoError(t, err)
+	require.NotNil(t, proj.Options)
+	assert.Equal(t, map[string]int{"aws": 4}, proj.Options.ProviderParallelism)
+
+	// Limits must be positive integers.
+	_, err = loadProjectFromText(t, "name: project\nruntime: test\noptions:\n  providerParallelism:\n    aws: 0\n")
+	assert.ErrorContains(t, err, "#/options/providerParallelism/aws")
+	_, err = loadProjectFromText(t, "name: project\nruntime: test\noptions:\n  providerParallelism:\n    aws: two\n")
+	assert.ErrorContains(t, err, "#/options/providerParallelism/aws")
+}
+
 func TestProjectSaveLoadRoundtrip(t *testing.T) {
 	t.Parallel()
 
@@ -1032,3 +1334,59 @@ func TestProjectEditRoundtrip(t *testing.T) {
 		})
 	}
 }
+
+func TestProjectStackConfigLayers(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	common := write("shared/common.yaml", "config:\n  region: us-west-2\n  size: small\n  tier: free\n")
+	write("shared/network.yaml", "extends: [common.yaml]\nconfig:\n  cidr: 10.0.0.0/16\n")
+	base := write("Pulumi.base.yaml", "extends: [shared/common.yaml]\nconfig:\n  size: medium\n")
+	stackPath := write("Pulumi.dev.yaml",
+		"extends: [Pulumi.base.yaml, shared/network.yaml]\nconfig:\n  tier: paid\n")
+
+	project := &Project{Name: "test"}
+	stack, err := LoadProjectStack(project, stackPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Pulumi.base.yaml", "shared/network.yaml"}, stack.Extends)
+
+	layers, err := stack.LoadConfigLayers(project)
+	require.NoError(t, err)
+	paths := make([]string, len(layers))
+	for i, layer := range layers {
+		paths[i] = layer.Path
+	}
+	// common.yaml is extended twice but only loaded once, before the first file that extends it.
+	assert.Equal(t, []string{common, base, filepath.Join(dir, "shared/network.yaml")}, paths)
+
+	merged, origins := MergeConfigLayers(layers, stack.Config)
+	assert.Equal(t, "us-west-2", getConfigValue(t, merged, "test:region"))
+	assert.Equal(t, "medium", getConfigValue(t, merged, "test:size"))
+	assert.Equal(t, "paid", getConfigValue(t, merged, "test:tier"))
+	assert.Equal(t, "10.0.0.0/16", getConfigValue(t, merged, "test:cidr"))
+	assert.Equal(t, map[config.Key]string{
+		config.MustMakeKey("test", "region"): common,
+		config.MustMakeKey("test", "size"):   base,
+		config.MustMakeKey("test", "cidr"):   filepath.Join(dir, "shared/network.yaml"),
+	}, origins)
+
+	// The stack's own config doesn't include inherited values, so that they aren't saved to its file.
+	assert.Len(t, stack.Config, 1)
+
+	// Cycles and missing files are errors.
+	write("shared/common.yaml", "extends: [../Pulumi.base.yaml]\n")
+	_, err = stack.LoadConfigLayers(project)
+	assert.ErrorContains(t, err, "extends itself")
+
+	write("Pulumi.dev.yaml", "extends: [missing.yaml]\n")
+	stack, err = LoadProjectStack(project, stackPath)
+	require.NoError(t, err)
+	_, err = stack.LoadConfigLayers(project)
+	assert.ErrorContains(t, err, "could not load config file 'missing.yaml'")
+}
@@ -938,6 +938,7 @@ func (ctx *Context) registerResource(
 				ReplaceOnChanges:        inputs.replaceOnChanges,
 				RetainOnDelete:          inputs.retainOnDelete,
 				DeletedWith:             inputs.deletedWith,
+				Retry:                   inputs.retry,
 			})
 			if err != nil {
 				logging.V(9).Infof("RegisterResource(%s, %s): error: %v", t, name, err)
@@ -1336,6 +1337,7 @@ type resourceInputs struct {
 	replaceOnChanges        []string
 	retainOnDelete          bool
 	deletedWith             string
+	retry                   *pulumirpc.RegisterResourceRequest_RetryPolicy
 }
 
 func (ctx *Context) resolveAliasParent(alias Alias, spec *pulumirpc.Alias_Spec) error {
@@ -1528,6 +1530,12 @@ func (ctx *Context) prepareResourceInputs(res Resource, props Input, t string, o
 		return nil, fmt.Errorf("marshaling properties: %w", err)
 	}
 
+	// The engine reads the concurrency limit of a provider from its reserved pluginParallelism input, which it never
+	// sends to the provider itself.
+	if opts.PluginParallelism > 0 && strings.HasPrefix(t, "pulumi:providers:") {
+		resolvedProps["pluginParallelism"] = resource.NewNumberProperty(float64(opts.PluginParallelism))
+	}
+
 	// Marshal all properties for the RPC call.
 	rpcProps, err := plugin.MarshalProperties(
 		resolvedProps,
@@ -1601,6 +1609,7 @@ func (ctx *Context) prepareResourceInputs(res Resource, props Input, t string, o
 		replaceOnChanges:        resOpts.replaceOnChanges,
 		retainOnDelete:          opts.RetainOnDelete,
 		deletedWith:             string(deletedWithURN),
+		retry:                   getRetryPolicy(opts.Retry),
 	}, nil
 }
 
@@ -1614,6 +1623,18 @@ func getTimeouts(custom *CustomTimeouts) *pulumirpc.RegisterResourceRequest_Cust
 	return &timeouts
 }
 
+func getRetryPolicy(retry *RetryPolicy) *pulumirpc.RegisterResourceRequest_RetryPolicy {
+	if retry == nil {
+		return nil
+	}
+	return &pulumirpc.RegisterResourceRequest_RetryPolicy{
+		MaxAttempts:     int32(retry.MaxAttempts),
+		Backoff:         retry.Backoff,
+		MaxBackoff:      retry.MaxBackoff,
+		RetryableErrors: retry.RetryableErrors,
+	}
+}
+
 // Helper struct for the return type of `getOpts`.
 type resourceOpts struct {
 	parentURN               URN
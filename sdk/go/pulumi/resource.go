@@ -286,6 +286,25 @@ type CustomTimeouts struct {
 	Delete string
 }
 
+// RetryPolicy specifies how the engine retries provider operations on a resource
+// that fail with transient errors, such as throttling by a cloud API.
+// Use it with the [Retry] option when creating new resources.
+//
+// An operation is only retried if it failed without changing the resource.
+// The delay between attempts starts at Backoff and doubles after each retry,
+// up to MaxBackoff. Delays are specified as duration strings, as with [CustomTimeouts].
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, e.g. "5s".
+	Backoff string
+	// MaxBackoff is the maximum delay between retries, e.g. "1m".
+	MaxBackoff string
+	// RetryableErrors are regular expressions matching the error messages
+	// that should be retried. All errors are retried if empty.
+	RetryableErrors []string
+}
+
 // ResourceOptions is a snapshot of one or more [ResourceOption]s.
 //
 // You cannot pass a ResourceOptions struct to a resource constructor.
@@ -376,6 +395,15 @@ type ResourceOptions struct {
 	// DeletedWith holds a container resource that, if deleted,
 	// also deletes this resource.
 	DeletedWith Resource
+
+	// Retry, if set, specifies how failed provider operations
+	// on this resource are retried.
+	Retry *RetryPolicy
+
+	// PluginParallelism, if positive, limits the number of concurrent
+	// resource operations performed by this provider.
+	// This only applies to provider resources.
+	PluginParallelism int
 }
 
 // NewResourceOptions builds a preview of the effect of the provided options.
@@ -410,6 +438,8 @@ type resourceOptions struct {
 	PluginDownloadURL       string
 	RetainOnDelete          bool
 	DeletedWith             Resource
+	Retry                   *RetryPolicy
+	PluginParallelism       int
 }
 
 func resourceOptionsSnapshot(ro *resourceOptions) *ResourceOptions {
@@ -465,6 +495,8 @@ func resourceOptionsSnapshot(ro *resourceOptions) *ResourceOptions {
 		PluginDownloadURL:       ro.PluginDownloadURL,
 		RetainOnDelete:          ro.RetainOnDelete,
 		DeletedWith:             ro.DeletedWith,
+		Retry:                   ro.Retry,
+		PluginParallelism:       ro.PluginParallelism,
 	}
 }
 
@@ -817,6 +849,21 @@ func Timeouts(o *CustomTimeouts) ResourceOption {
 	})
 }
 
+// Retry specifies how provider operations on the resource that fail with transient errors are retried.
+func Retry(o *RetryPolicy) ResourceOption {
+	return resourceOption(func(ro *resourceOptions) {
+		ro.Retry = o
+	})
+}
+
+// PluginParallelism limits the number of concurrent resource operations performed by a provider,
+// e.g. for a provider backed by a rate-limited API. This only applies to provider resources.
+func PluginParallelism(o int) ResourceOption {
+	return resourceOption(func(ro *resourceOptions) {
+		ro.PluginParallelism = o
+	})
+}
+
 // Transformations is an optional list of transformations to be applied to the resource.
 func Transformations(o []ResourceTransformation) ResourceOption {
 	return resourceOption(func(ro *resourceOptions) {
@@ -1128,6 +1128,16 @@ func TestNewResourceOptions(t *testing.T) {
 			give: DeletedWith(&testRes{foo: "a"}),
 			want: ResourceOptions{DeletedWith: &testRes{foo: "a"}},
 		},
+		{
+			desc: "Retry",
+			give: Retry(&RetryPolicy{MaxAttempts: 3, Backoff: "5s"}),
+			want: ResourceOptions{Retry: &RetryPolicy{MaxAttempts: 3, Backoff: "5s"}},
+		},
+		{
+			desc: "PluginParallelism",
+			give: PluginParallelism(4),
+			want: ResourceOptions{PluginParallelism: 4},
+		},
 	}
 
 	for _, tt := range tests {
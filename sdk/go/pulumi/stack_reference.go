@@ -3,8 +3,10 @@ package pulumi
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
 // StackReference manages a reference to a Pulumi stack.
@@ -23,13 +25,27 @@ type StackReference struct {
 
 // GetOutput returns a stack output keyed by the given name as an AnyOutput
 // If the given name is not present in the StackReference, Output<nil> is returned.
+//
+// If the stack reference validates its outputs, GetOutput fails for names that the outputs schema of the referenced
+// stack doesn't declare.
 func (s *StackReference) GetOutput(name StringInput) AnyOutput {
+	return s.getOutput(name)
+}
+
+// getOutput returns the stack output keyed by the given name. If the stack reference validates its outputs and
+// typeNames is not empty, the output must be declared with one of these types.
+func (s *StackReference) getOutput(name StringInput, typeNames ...string) AnyOutput {
 	return All(name, s.rawOutputs).
 		ApplyT(func(args []interface{}) (interface{}, error) {
 			n, stack := args[0].(string), args[1].(resource.PropertyMap)
 			if !stack["outputs"].IsObject() {
 				return Any(nil), fmt.Errorf("failed to convert %T to object", stack)
 			}
+			if schema, ok := stack["outputsSchema"]; ok {
+				if err := s.checkOutputType(schema, n, typeNames); err != nil {
+					return nil, err
+				}
+			}
 			outs := stack["outputs"].ObjectValue()
 			v, ok := outs[resource.PropertyKey(n)]
 			if !ok {
@@ -51,6 +67,30 @@ func (s *StackReference) GetOutput(name StringInput) AnyOutput {
 		}).(AnyOutput)
 }
 
+// checkOutputType checks that the given outputs schema of the referenced stack declares the named output, with one of
+// the given types if there are any. This catches outputs that were renamed or changed by the referenced stack even
+// during previews, when outputs that don't exist are unknown.
+func (s *StackReference) checkOutputType(schema resource.PropertyValue, name string, typeNames []string) error {
+	outputTypes, err := workspace.UnmarshalOutputTypes(schema)
+	if err != nil {
+		return fmt.Errorf("reading the outputs schema of stack %q: %w", s.name, err)
+	}
+	outputType, ok := outputTypes[name]
+	if !ok {
+		return fmt.Errorf("stack reference output %q is not declared by the outputs schema of stack %q", name, s.name)
+	}
+	if len(typeNames) == 0 {
+		return nil
+	}
+	for _, typeName := range typeNames {
+		if outputType.Type == typeName {
+			return nil
+		}
+	}
+	return fmt.Errorf("stack reference output %q of stack %q is declared as %q, not %q",
+		name, s.name, outputType.Type, strings.Join(typeNames, `" or "`))
+}
+
 // StackReferenceOutputDetails holds a stack output value.
 // At most one of the Value and SecretValue fields will be set.
 //
@@ -92,7 +132,7 @@ func (s *StackReference) GetOutputDetails(name string) (*StackReferenceOutputDet
 
 // GetStringOutput returns a stack output keyed by the given name as an StringOutput
 func (s *StackReference) GetStringOutput(name StringInput) StringOutput {
-	return All(name, s.GetOutput(name)).ApplyT(func(args []interface{}) (string, error) {
+	return All(name, s.getOutput(name, "string")).ApplyT(func(args []interface{}) (string, error) {
 		name, out := args[0].(string), args[1]
 		if out == nil {
 			return "", fmt.Errorf(
@@ -121,7 +161,8 @@ func (s *StackReference) GetIDOutput(name StringInput) IDOutput {
 
 // GetFloat64Output returns a stack output keyed by the given name as an Float64Output
 func (s *StackReference) GetFloat64Output(name StringInput) Float64Output {
-	return All(name, s.GetOutput(name)).ApplyT(func(args []interface{}) (float64, error) {
+	// Integers are numbers too.
+	return All(name, s.getOutput(name, "number", "integer")).ApplyT(func(args []interface{}) (float64, error) {
 		name, out := args[0].(string), args[1]
 		if out == nil {
 			return 0.0, fmt.Errorf(
@@ -143,7 +184,7 @@ func (s *StackReference) GetFloat64Output(name StringInput) Float64Output {
 
 // GetIntOutput returns a stack output keyed by the given name as an IntOutput
 func (s *StackReference) GetIntOutput(name StringInput) IntOutput {
-	return All(name, s.GetOutput(name)).ApplyT(func(args []interface{}) (int, error) {
+	return All(name, s.getOutput(name, "integer")).ApplyT(func(args []interface{}) (int, error) {
 		name, out := args[0].(string), args[1]
 		if out == nil {
 			return 0, fmt.Errorf(
@@ -164,13 +205,18 @@ func (s *StackReference) GetIntOutput(name StringInput) IntOutput {
 }
 
 type stackReferenceArgs struct {
-	Name string `pulumi:"name"`
+	Name            string `pulumi:"name"`
+	ValidateOutputs *bool  `pulumi:"validateOutputs"`
 }
 
 // StackReferenceArgs is the input to NewStackReference that allows specifying a stack name
 type StackReferenceArgs struct {
 	// Name is in the form "Org/Program/Stack"
 	Name StringInput
+	// ValidateOutputs checks the outputs of the stack against the outputs schema that its project declares, and
+	// checks that the outputs that are read from the stack reference are declared by it. Mismatches fail previews
+	// and updates. The stack must publish an outputs schema.
+	ValidateOutputs BoolPtrInput
 }
 
 func (StackReferenceArgs) ElementType() reflect.Type {
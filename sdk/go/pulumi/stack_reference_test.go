@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -117,6 +118,64 @@ func TestStackReference(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestStackReferenceValidateOutputs(t *testing.T) {
+	t.Parallel()
+
+	schema, err := workspace.MarshalOutputTypes(map[string]workspace.ProjectOutputType{
+		"vpcId": {Type: "string"},
+		"port":  {Type: "integer"},
+		"ratio": {Type: "number"},
+	})
+	require.NoError(t, err)
+	mocks := &testMonitor{
+		NewResourceF: func(args MockResourceArgs) (string, resource.PropertyMap, error) {
+			assert.True(t, args.Inputs.DeepEquals(resource.NewPropertyMapFromMap(map[string]interface{}{
+				"name":            "stack",
+				"validateOutputs": true,
+			})))
+			return args.Inputs["name"].StringValue(), resource.PropertyMap{
+				"name": resource.NewStringProperty("stack"),
+				"outputs": resource.NewObjectProperty(resource.PropertyMap{
+					"vpcId": resource.NewStringProperty("vpc-1234"),
+					"ratio": resource.NewNumberProperty(0.5),
+				}),
+				"outputsSchema": schema,
+			}, nil
+		},
+	}
+
+	// Outputs that the schema doesn't declare fail even during previews, when they would otherwise be unknown.
+	err = RunErr(func(ctx *Context) error {
+		ref, err := NewStackReference(ctx, "stack", &StackReferenceArgs{ValidateOutputs: Bool(true)})
+		require.NoError(t, err)
+
+		vpcID, _, _, _, err := await(ref.GetStringOutput(String("vpcId")))
+		assert.NoError(t, err)
+		assert.Equal(t, "vpc-1234", vpcID)
+		_, known, _, _, err := await(ref.GetIntOutput(String("port")))
+		assert.NoError(t, err)
+		assert.False(t, known)
+		ratio, _, _, _, err := await(ref.GetFloat64Output(String("ratio")))
+		assert.NoError(t, err)
+		assert.Equal(t, 0.5, ratio)
+		_, known, _, _, err = await(ref.GetFloat64Output(String("port")))
+		assert.NoError(t, err)
+		assert.False(t, known)
+
+		_, _, _, _, err = await(ref.GetOutput(String("vpcID")))
+		assert.EqualError(t, err,
+			`stack reference output "vpcID" is not declared by the outputs schema of stack "stack"`)
+		_, _, _, _, err = await(ref.GetStringOutput(String("port")))
+		assert.EqualError(t, err,
+			`stack reference output "port" of stack "stack" is declared as "integer", not "string"`)
+		_, _, _, _, err = await(ref.GetIntOutput(String("ratio")))
+		assert.EqualError(t, err,
+			`stack reference output "ratio" of stack "stack" is declared as "number", not "integer"`)
+		return nil
+	}, WithDryRun(true), WithMocks("project", "stack", mocks))
+	assert.NoError(t, err)
+}
+
 func TestStackReferenceSecrets(t *testing.T) {
 	t.Parallel()
 	var resName string
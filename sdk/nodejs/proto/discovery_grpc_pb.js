@@ -0,0 +1,64 @@
+// GENERATED CODE -- DO NOT EDIT!
+
+// Original file comments:
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+'use strict';
+var grpc = require('@grpc/grpc-js');
+var pulumi_discovery_pb = require('./discovery_pb.js');
+var google_protobuf_struct_pb = require('google-protobuf/google/protobuf/struct_pb.js');
+
+function serialize_pulumirpc_ListResourcesRequest(arg) {
+  if (!(arg instanceof pulumi_discovery_pb.ListResourcesRequest)) {
+    throw new Error('Expected argument of type pulumirpc.ListResourcesRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_ListResourcesRequest(buffer_arg) {
+  return pulumi_discovery_pb.ListResourcesRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_ListResourcesResponse(arg) {
+  if (!(arg instanceof pulumi_discovery_pb.ListResourcesResponse)) {
+    throw new Error('Expected argument of type pulumirpc.ListResourcesResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_ListResourcesResponse(buffer_arg) {
+  return pulumi_discovery_pb.ListResourcesResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+
+// ResourceDiscovery is an optional service of resource providers, which lists the existing resources of a type
+// so that they can be discovered and imported. It is served alongside ResourceProvider by the providers that
+// support it.
+var ResourceDiscoveryService = exports.ResourceDiscoveryService = {
+  // ListResources lists the existing resources of a type that match the given filters.
+listResources: {
+    path: '/pulumirpc.ResourceDiscovery/ListResources',
+    requestStream: false,
+    responseStream: false,
+    requestType: pulumi_discovery_pb.ListResourcesRequest,
+    responseType: pulumi_discovery_pb.ListResourcesResponse,
+    requestSerialize: serialize_pulumirpc_ListResourcesRequest,
+    requestDeserialize: deserialize_pulumirpc_ListResourcesRequest,
+    responseSerialize: serialize_pulumirpc_ListResourcesResponse,
+    responseDeserialize: deserialize_pulumirpc_ListResourcesResponse,
+  },
+};
+
+exports.ResourceDiscoveryClient = grpc.makeGenericClientConstructor(ResourceDiscoveryService);
@@ -29,6 +29,7 @@ goog.exportSymbol('proto.pulumirpc.RegisterResourceOutputsRequest', null, global
 goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest.CustomTimeouts', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest.PropertyDependencies', null, global);
+goog.exportSymbol('proto.pulumirpc.RegisterResourceRequest.RetryPolicy', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceResponse', null, global);
 goog.exportSymbol('proto.pulumirpc.RegisterResourceResponse.PropertyDependencies', null, global);
 goog.exportSymbol('proto.pulumirpc.ResourceInvokeRequest', null, global);
@@ -181,6 +182,27 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.displayName = 'proto.pulumirpc.RegisterResourceRequest.CustomTimeouts';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.pulumirpc.RegisterResourceRequest.RetryPolicy.repeatedFields_, null);
+};
+goog.inherits(proto.pulumirpc.RegisterResourceRequest.RetryPolicy, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.pulumirpc.RegisterResourceRequest.RetryPolicy.displayName = 'proto.pulumirpc.RegisterResourceRequest.RetryPolicy';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -1298,7 +1320,8 @@ proto.pulumirpc.RegisterResourceRequest.toObject = function(includeInstance, msg
     retainondelete: jspb.Message.getBooleanFieldWithDefault(msg, 25, false),
     aliasesList: jspb.Message.toObjectList(msg.getAliasesList(),
     pulumi_alias_pb.Alias.toObject, includeInstance),
-    deletedwith: jspb.Message.getFieldWithDefault(msg, 27, "")
+    deletedwith: jspb.Message.getFieldWithDefault(msg, 27, ""),
+    retry: (f = msg.getRetry()) && proto.pulumirpc.RegisterResourceRequest.RetryPolicy.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -1450,6 +1473,11 @@ proto.pulumirpc.RegisterResourceRequest.deserializeBinaryFromReader = function(m
       var value = /** @type {string} */ (reader.readString());
       msg.setDeletedwith(value);
       break;
+    case 28:
+      var value = new proto.pulumirpc.RegisterResourceRequest.RetryPolicy;
+      reader.readMessage(value,proto.pulumirpc.RegisterResourceRequest.RetryPolicy.deserializeBinaryFromReader);
+      msg.setRetry(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1665,6 +1693,14 @@ proto.pulumirpc.RegisterResourceRequest.serializeBinaryToWriter = function(messa
       f
     );
   }
+  f = message.getRetry();
+  if (f != null) {
+    writer.writeMessage(
+      28,
+      f,
+      proto.pulumirpc.RegisterResourceRequest.RetryPolicy.serializeBinaryToWriter
+    );
+  }
 };
 
 
@@ -2014,6 +2050,252 @@ proto.pulumirpc.RegisterResourceRequest.CustomTimeouts.prototype.setDelete = fun
 };
 
 
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.repeatedFields_ = [4];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.toObject = function(opt_includeInstance) {
+  return proto.pulumirpc.RegisterResourceRequest.RetryPolicy.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    maxattempts: jspb.Message.getFieldWithDefault(msg, 1, 0),
+    backoff: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    maxbackoff: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    retryableerrorsList: (f = jspb.Message.getRepeatedField(msg, 4)) == null ? undefined : f
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy}
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.pulumirpc.RegisterResourceRequest.RetryPolicy;
+  return proto.pulumirpc.RegisterResourceRequest.RetryPolicy.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy}
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setMaxattempts(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setBackoff(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMaxbackoff(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addRetryableerrors(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.pulumirpc.RegisterResourceRequest.RetryPolicy.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getMaxattempts();
+  if (f !== 0) {
+    writer.writeInt32(
+      1,
+      f
+    );
+  }
+  f = message.getBackoff();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getMaxbackoff();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getRetryableerrorsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      4,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional int32 maxAttempts = 1;
+ * @return {number}
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.getMaxattempts = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.setMaxattempts = function(value) {
+  return jspb.Message.setProto3IntField(this, 1, value);
+};
+
+
+/**
+ * optional string backoff = 2;
+ * @return {string}
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.getBackoff = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.setBackoff = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string maxBackoff = 3;
+ * @return {string}
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.getMaxbackoff = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.setMaxbackoff = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * repeated string retryableErrors = 4;
+ * @return {!Array<string>}
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.getRetryableerrorsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 4));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.setRetryableerrorsList = function(value) {
+  return jspb.Message.setField(this, 4, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.addRetryableerrors = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 4, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.pulumirpc.RegisterResourceRequest.RetryPolicy} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.RetryPolicy.prototype.clearRetryableerrorsList = function() {
+  return this.setRetryableerrorsList([]);
+};
+
+
 /**
  * optional string type = 1;
  * @return {string}
@@ -2661,6 +2943,43 @@ proto.pulumirpc.RegisterResourceRequest.prototype.setDeletedwith = function(valu
 };
 
 
+/**
+ * optional RetryPolicy retry = 28;
+ * @return {?proto.pulumirpc.RegisterResourceRequest.RetryPolicy}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getRetry = function() {
+  return /** @type{?proto.pulumirpc.RegisterResourceRequest.RetryPolicy} */ (
+    jspb.Message.getWrapperField(this, proto.pulumirpc.RegisterResourceRequest.RetryPolicy, 28));
+};
+
+
+/**
+ * @param {?proto.pulumirpc.RegisterResourceRequest.RetryPolicy|undefined} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+*/
+proto.pulumirpc.RegisterResourceRequest.prototype.setRetry = function(value) {
+  return jspb.Message.setWrapperField(this, 28, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.clearRetry = function() {
+  return this.setRetry(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.hasRetry = function() {
+  return jspb.Message.getField(this, 28) != null;
+};
+
+
 
 /**
  * List of repeated fields within this message type.
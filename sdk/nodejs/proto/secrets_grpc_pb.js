@@ -0,0 +1,132 @@
+// GENERATED CODE -- DO NOT EDIT!
+
+// Original file comments:
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+'use strict';
+var grpc = require('@grpc/grpc-js');
+var pulumi_secrets_pb = require('./secrets_pb.js');
+
+function serialize_pulumirpc_DecryptSecretsRequest(arg) {
+  if (!(arg instanceof pulumi_secrets_pb.DecryptSecretsRequest)) {
+    throw new Error('Expected argument of type pulumirpc.DecryptSecretsRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_DecryptSecretsRequest(buffer_arg) {
+  return pulumi_secrets_pb.DecryptSecretsRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_DecryptSecretsResponse(arg) {
+  if (!(arg instanceof pulumi_secrets_pb.DecryptSecretsResponse)) {
+    throw new Error('Expected argument of type pulumirpc.DecryptSecretsResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_DecryptSecretsResponse(buffer_arg) {
+  return pulumi_secrets_pb.DecryptSecretsResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_EncryptSecretsRequest(arg) {
+  if (!(arg instanceof pulumi_secrets_pb.EncryptSecretsRequest)) {
+    throw new Error('Expected argument of type pulumirpc.EncryptSecretsRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_EncryptSecretsRequest(buffer_arg) {
+  return pulumi_secrets_pb.EncryptSecretsRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_EncryptSecretsResponse(arg) {
+  if (!(arg instanceof pulumi_secrets_pb.EncryptSecretsResponse)) {
+    throw new Error('Expected argument of type pulumirpc.EncryptSecretsResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_EncryptSecretsResponse(buffer_arg) {
+  return pulumi_secrets_pb.EncryptSecretsResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_InitializeSecretsRequest(arg) {
+  if (!(arg instanceof pulumi_secrets_pb.InitializeSecretsRequest)) {
+    throw new Error('Expected argument of type pulumirpc.InitializeSecretsRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_InitializeSecretsRequest(buffer_arg) {
+  return pulumi_secrets_pb.InitializeSecretsRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_InitializeSecretsResponse(arg) {
+  if (!(arg instanceof pulumi_secrets_pb.InitializeSecretsResponse)) {
+    throw new Error('Expected argument of type pulumirpc.InitializeSecretsResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_InitializeSecretsResponse(buffer_arg) {
+  return pulumi_secrets_pb.InitializeSecretsResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+
+// SecretsManager is a service for encrypting and decrypting the secrets of a stack, e.g. using an external key
+// management service. It is served by secrets plugins, which are selected by `plugin://<name>` secrets providers.
+var SecretsManagerService = exports.SecretsManagerService = {
+  // Initialize prepares the secrets manager of a stack from the arguments of its secrets provider URL and the state
+// it previously returned for the stack, if any. It returns the state to persist for the stack, which must not
+// contain any plaintext key material.
+initialize: {
+    path: '/pulumirpc.SecretsManager/Initialize',
+    requestStream: false,
+    responseStream: false,
+    requestType: pulumi_secrets_pb.InitializeSecretsRequest,
+    responseType: pulumi_secrets_pb.InitializeSecretsResponse,
+    requestSerialize: serialize_pulumirpc_InitializeSecretsRequest,
+    requestDeserialize: deserialize_pulumirpc_InitializeSecretsRequest,
+    responseSerialize: serialize_pulumirpc_InitializeSecretsResponse,
+    responseDeserialize: deserialize_pulumirpc_InitializeSecretsResponse,
+  },
+  // Encrypt encrypts a batch of plaintexts.
+encrypt: {
+    path: '/pulumirpc.SecretsManager/Encrypt',
+    requestStream: false,
+    responseStream: false,
+    requestType: pulumi_secrets_pb.EncryptSecretsRequest,
+    responseType: pulumi_secrets_pb.EncryptSecretsResponse,
+    requestSerialize: serialize_pulumirpc_EncryptSecretsRequest,
+    requestDeserialize: deserialize_pulumirpc_EncryptSecretsRequest,
+    responseSerialize: serialize_pulumirpc_EncryptSecretsResponse,
+    responseDeserialize: deserialize_pulumirpc_EncryptSecretsResponse,
+  },
+  // Decrypt decrypts a batch of ciphertexts.
+decrypt: {
+    path: '/pulumirpc.SecretsManager/Decrypt',
+    requestStream: false,
+    responseStream: false,
+    requestType: pulumi_secrets_pb.DecryptSecretsRequest,
+    responseType: pulumi_secrets_pb.DecryptSecretsResponse,
+    requestSerialize: serialize_pulumirpc_DecryptSecretsRequest,
+    requestDeserialize: deserialize_pulumirpc_DecryptSecretsRequest,
+    responseSerialize: serialize_pulumirpc_DecryptSecretsResponse,
+    responseDeserialize: deserialize_pulumirpc_DecryptSecretsResponse,
+  },
+};
+
+exports.SecretsManagerClient = grpc.makeGenericClientConstructor(SecretsManagerService);
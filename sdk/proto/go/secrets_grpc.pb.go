@@ -0,0 +1,187 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.20.1
+// source: pulumi/secrets.proto
+
+package pulumirpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// SecretsManagerClient is the client API for SecretsManager service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SecretsManagerClient interface {
+	// Initialize prepares the secrets manager of a stack from the arguments of its secrets provider URL and the state
+	// it previously returned for the stack, if any. It returns the state to persist for the stack, which must not
+	// contain any plaintext key material.
+	Initialize(ctx context.Context, in *InitializeSecretsRequest, opts ...grpc.CallOption) (*InitializeSecretsResponse, error)
+	// Encrypt encrypts a batch of plaintexts.
+	Encrypt(ctx context.Context, in *EncryptSecretsRequest, opts ...grpc.CallOption) (*EncryptSecretsResponse, error)
+	// Decrypt decrypts a batch of ciphertexts.
+	Decrypt(ctx context.Context, in *DecryptSecretsRequest, opts ...grpc.CallOption) (*DecryptSecretsResponse, error)
+}
+
+type secretsManagerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSecretsManagerClient(cc grpc.ClientConnInterface) SecretsManagerClient {
+	return &secretsManagerClient{cc}
+}
+
+func (c *secretsManagerClient) Initialize(ctx context.Context, in *InitializeSecretsRequest, opts ...grpc.CallOption) (*InitializeSecretsResponse, error) {
+	out := new(InitializeSecretsResponse)
+	err := c.cc.Invoke(ctx, "/pulumirpc.SecretsManager/Initialize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretsManagerClient) Encrypt(ctx context.Context, in *EncryptSecretsRequest, opts ...grpc.CallOption) (*EncryptSecretsResponse, error) {
+	out := new(EncryptSecretsResponse)
+	err := c.cc.Invoke(ctx, "/pulumirpc.SecretsManager/Encrypt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *secretsManagerClient) Decrypt(ctx context.Context, in *DecryptSecretsRequest, opts ...grpc.CallOption) (*DecryptSecretsResponse, error) {
+	out := new(DecryptSecretsResponse)
+	err := c.cc.Invoke(ctx, "/pulumirpc.SecretsManager/Decrypt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SecretsManagerServer is the server API for SecretsManager service.
+// All implementations must embed UnimplementedSecretsManagerServer
+// for forward compatibility
+type SecretsManagerServer interface {
+	// Initialize prepares the secrets manager of a stack from the arguments of its secrets provider URL and the state
+	// it previously returned for the stack, if any. It returns the state to persist for the stack, which must not
+	// contain any plaintext key material.
+	Initialize(context.Context, *InitializeSecretsRequest) (*InitializeSecretsResponse, error)
+	// Encrypt encrypts a batch of plaintexts.
+	Encrypt(context.Context, *EncryptSecretsRequest) (*EncryptSecretsResponse, error)
+	// Decrypt decrypts a batch of ciphertexts.
+	Decrypt(context.Context, *DecryptSecretsRequest) (*DecryptSecretsResponse, error)
+	mustEmbedUnimplementedSecretsManagerServer()
+}
+
+// UnimplementedSecretsManagerServer must be embedded to have forward compatible implementations.
+type UnimplementedSecretsManagerServer struct {
+}
+
+func (UnimplementedSecretsManagerServer) Initialize(context.Context, *InitializeSecretsRequest) (*InitializeSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Initialize not implemented")
+}
+func (UnimplementedSecretsManagerServer) Encrypt(context.Context, *EncryptSecretsRequest) (*EncryptSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Encrypt not implemented")
+}
+func (UnimplementedSecretsManagerServer) Decrypt(context.Context, *DecryptSecretsRequest) (*DecryptSecretsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decrypt not implemented")
+}
+func (UnimplementedSecretsManagerServer) mustEmbedUnimplementedSecretsManagerServer() {}
+
+// UnsafeSecretsManagerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SecretsManagerServer will
+// result in compilation errors.
+type UnsafeSecretsManagerServer interface {
+	mustEmbedUnimplementedSecretsManagerServer()
+}
+
+func RegisterSecretsManagerServer(s grpc.ServiceRegistrar, srv SecretsManagerServer) {
+	s.RegisterService(&SecretsManager_ServiceDesc, srv)
+}
+
+func _SecretsManager_Initialize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitializeSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsManagerServer).Initialize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pulumirpc.SecretsManager/Initialize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsManagerServer).Initialize(ctx, req.(*InitializeSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretsManager_Encrypt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EncryptSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsManagerServer).Encrypt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pulumirpc.SecretsManager/Encrypt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsManagerServer).Encrypt(ctx, req.(*EncryptSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SecretsManager_Decrypt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecryptSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SecretsManagerServer).Decrypt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pulumirpc.SecretsManager/Decrypt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SecretsManagerServer).Decrypt(ctx, req.(*DecryptSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SecretsManager_ServiceDesc is the grpc.ServiceDesc for SecretsManager service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SecretsManager_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pulumirpc.SecretsManager",
+	HandlerType: (*SecretsManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Initialize",
+			Handler:    _SecretsManager_Initialize_Handler,
+		},
+		{
+			MethodName: "Encrypt",
+			Handler:    _SecretsManager_Encrypt_Handler,
+		},
+		{
+			MethodName: "Decrypt",
+			Handler:    _SecretsManager_Decrypt_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pulumi/secrets.proto",
+}
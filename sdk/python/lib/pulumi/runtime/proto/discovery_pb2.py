@@ -0,0 +1,37 @@
+# -*- coding: utf-8 -*-
+# Generated by the protocol buffer compiler.  DO NOT EDIT!
+# source: pulumi/discovery.proto
+"""Generated protocol buffer code."""
+from google.protobuf.internal import builder as _builder
+from google.protobuf import descriptor as _descriptor
+from google.protobuf import descriptor_pool as _descriptor_pool
+from google.protobuf import symbol_database as _symbol_database
+# @@protoc_insertion_point(imports)
+
+_sym_db = _symbol_database.Default()
+
+
+from google.protobuf import struct_pb2 as google_dot_protobuf_dot_struct__pb2
+
+
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x16pulumi/discovery.proto\x12\tpulumirpc\x1a\x1cgoogle/protobuf/struct.proto\"\x93\x01\n\x14ListResourcesRequest\x12\x0c\n\x04type\x18\x01 \x01(\t\x12=\n\x07\x66ilters\x18\x02 \x03(\x0b\x32,.pulumirpc.ListResourcesRequest.FiltersEntry\x1a.\n\x0c\x46iltersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"E\n\x15ListResourcesResponse\x12,\n\tresources\x18\x01 \x03(\x0b\x32\x19.pulumirpc.ListedResource\"W\n\x0eListedResource\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12+\n\nproperties\x18\x03 \x01(\x0b\x32\x17.google.protobuf.Struct2i\n\x11ResourceDiscovery\x12T\n\rListResources\x12\x1f.pulumirpc.ListResourcesRequest\x1a .pulumirpc.ListResourcesResponse\"\x00\x42\x34Z2github.com/pulumi/pulumi/sdk/v3/proto/go;pulumirpcb\x06proto3')
+
+_builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, globals())
+_builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'pulumi.discovery_pb2', globals())
+if _descriptor._USE_C_DESCRIPTORS == False:
+
+  DESCRIPTOR._options = None
+  DESCRIPTOR._serialized_options = b'Z2github.com/pulumi/pulumi/sdk/v3/proto/go;pulumirpc'
+  _LISTRESOURCESREQUEST_FILTERSENTRY._options = None
+  _LISTRESOURCESREQUEST_FILTERSENTRY._serialized_options = b'8\001'
+  _LISTRESOURCESREQUEST._serialized_start=68
+  _LISTRESOURCESREQUEST._serialized_end=215
+  _LISTRESOURCESREQUEST_FILTERSENTRY._serialized_start=169
+  _LISTRESOURCESREQUEST_FILTERSENTRY._serialized_end=215
+  _LISTRESOURCESRESPONSE._serialized_start=217
+  _LISTRESOURCESRESPONSE._serialized_end=286
+  _LISTEDRESOURCE._serialized_start=288
+  _LISTEDRESOURCE._serialized_end=375
+  _RESOURCEDISCOVERY._serialized_start=377
+  _RESOURCEDISCOVERY._serialized_end=482
+# @@protoc_insertion_point(module_scope)
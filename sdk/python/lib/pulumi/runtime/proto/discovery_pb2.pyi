@@ -0,0 +1,113 @@
+"""
+@generated by mypy-protobuf.  Do not edit manually!
+isort:skip_file
+Copyright 2016-2023, Pulumi Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+"""
+import builtins
+import collections.abc
+import google.protobuf.descriptor
+import google.protobuf.internal.containers
+import google.protobuf.message
+import google.protobuf.struct_pb2
+import sys
+
+if sys.version_info >= (3, 8):
+    import typing as typing_extensions
+else:
+    import typing_extensions
+
+DESCRIPTOR: google.protobuf.descriptor.FileDescriptor
+
+@typing_extensions.final
+class ListResourcesRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    @typing_extensions.final
+    class FiltersEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        value: builtins.str
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: builtins.str = ...,
+        ) -> None: ...
+        def ClearField(self, field_name: typing_extensions.Literal["key", b"key", "value", b"value"]) -> None: ...
+
+    TYPE_FIELD_NUMBER: builtins.int
+    FILTERS_FIELD_NUMBER: builtins.int
+    type: builtins.str
+    """the type token of the resources to list."""
+    @property
+    def filters(self) -> google.protobuf.internal.containers.ScalarMap[builtins.str, builtins.str]:
+        """the values that the properties of the listed resources must have, keyed by property name."""
+    def __init__(
+        self,
+        *,
+        type: builtins.str = ...,
+        filters: collections.abc.Mapping[builtins.str, builtins.str] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing_extensions.Literal["filters", b"filters", "type", b"type"]) -> None: ...
+
+global___ListResourcesRequest = ListResourcesRequest
+
+@typing_extensions.final
+class ListResourcesResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RESOURCES_FIELD_NUMBER: builtins.int
+    @property
+    def resources(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___ListedResource]:
+        """the resources of the requested type."""
+    def __init__(
+        self,
+        *,
+        resources: collections.abc.Iterable[global___ListedResource] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing_extensions.Literal["resources", b"resources"]) -> None: ...
+
+global___ListResourcesResponse = ListResourcesResponse
+
+@typing_extensions.final
+class ListedResource(google.protobuf.message.Message):
+    """ListedResource is an existing resource that can be imported."""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ID_FIELD_NUMBER: builtins.int
+    NAME_FIELD_NUMBER: builtins.int
+    PROPERTIES_FIELD_NUMBER: builtins.int
+    id: builtins.str
+    """the ID of the resource, which is used to import it."""
+    name: builtins.str
+    """a suggested name for the resource, e.g. from its tags."""
+    @property
+    def properties(self) -> google.protobuf.struct_pb2.Struct:
+        """properties that describe the resource to users choosing the resources to import."""
+    def __init__(
+        self,
+        *,
+        id: builtins.str = ...,
+        name: builtins.str = ...,
+        properties: google.protobuf.struct_pb2.Struct | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing_extensions.Literal["properties", b"properties"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing_extensions.Literal["id", b"id", "name", b"name", "properties", b"properties"]) -> None: ...
+
+global___ListedResource = ListedResource
@@ -0,0 +1,76 @@
+# Generated by the gRPC Python protocol compiler plugin. DO NOT EDIT!
+"""Client and server classes corresponding to protobuf-defined services."""
+import grpc
+
+from . import discovery_pb2 as pulumi_dot_discovery__pb2
+
+
+class ResourceDiscoveryStub(object):
+    """ResourceDiscovery is an optional service of resource providers, which lists the existing resources of a type
+    so that they can be discovered and imported. It is served alongside ResourceProvider by the providers that
+    support it.
+    """
+
+    def __init__(self, channel):
+        """Constructor.
+
+        Args:
+            channel: A grpc.Channel.
+        """
+        self.ListResources = channel.unary_unary(
+                '/pulumirpc.ResourceDiscovery/ListResources',
+                request_serializer=pulumi_dot_discovery__pb2.ListResourcesRequest.SerializeToString,
+                response_deserializer=pulumi_dot_discovery__pb2.ListResourcesResponse.FromString,
+                )
+
+
+class ResourceDiscoveryServicer(object):
+    """ResourceDiscovery is an optional service of resource providers, which lists the existing resources of a type
+    so that they can be discovered and imported. It is served alongside ResourceProvider by the providers that
+    support it.
+    """
+
+    def ListResources(self, request, context):
+        """ListResources lists the existing resources of a type that match the given filters.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+
+def add_ResourceDiscoveryServicer_to_server(servicer, server):
+    rpc_method_handlers = {
+            'ListResources': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListResources,
+                    request_deserializer=pulumi_dot_discovery__pb2.ListResourcesRequest.FromString,
+                    response_serializer=pulumi_dot_discovery__pb2.ListResourcesResponse.SerializeToString,
+            ),
+    }
+    generic_handler = grpc.method_handlers_generic_handler(
+            'pulumirpc.ResourceDiscovery', rpc_method_handlers)
+    server.add_generic_rpc_handlers((generic_handler,))
+
+
+ # This class is part of an EXPERIMENTAL API.
+class ResourceDiscovery(object):
+    """ResourceDiscovery is an optional service of resource providers, which lists the existing resources of a type
+    so that they can be discovered and imported. It is served alongside ResourceProvider by the providers that
+    support it.
+    """
+
+    @staticmethod
+    def ListResources(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pulumirpc.ResourceDiscovery/ListResources',
+            pulumi_dot_discovery__pb2.ListResourcesRequest.SerializeToString,
+            pulumi_dot_discovery__pb2.ListResourcesResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
@@ -0,0 +1,51 @@
+"""
+@generated by mypy-protobuf.  Do not edit manually!
+isort:skip_file
+Copyright 2016-2023, Pulumi Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+"""
+import abc
+import grpc
+import grpc.aio
+import typing
+import pulumi.discovery_pb2
+
+class ResourceDiscoveryStub:
+    """ResourceDiscovery is an optional service of resource providers, which lists the existing resources of a type
+    so that they can be discovered and imported. It is served alongside ResourceProvider by the providers that
+    support it.
+    """
+
+    def __init__(self, channel: grpc.Channel) -> None: ...
+    ListResources: grpc.UnaryUnaryMultiCallable[
+        pulumi.discovery_pb2.ListResourcesRequest,
+        pulumi.discovery_pb2.ListResourcesResponse,
+    ]
+    """ListResources lists the existing resources of a type that match the given filters."""
+
+class ResourceDiscoveryServicer(metaclass=abc.ABCMeta):
+    """ResourceDiscovery is an optional service of resource providers, which lists the existing resources of a type
+    so that they can be discovered and imported. It is served alongside ResourceProvider by the providers that
+    support it.
+    """
+
+    
+    def ListResources(
+        self,
+        request: pulumi.discovery_pb2.ListResourcesRequest,
+        context: grpc.ServicerContext,
+    ) -> pulumi.discovery_pb2.ListResourcesResponse:
+        """ListResources lists the existing resources of a type that match the given filters."""
+
+def add_ResourceDiscoveryServicer_to_server(servicer: ResourceDiscoveryServicer, server: typing.Union[grpc.Server, grpc.aio.Server]) -> None: ...
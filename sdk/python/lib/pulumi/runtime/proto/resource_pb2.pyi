@@ -211,6 +211,35 @@ class RegisterResourceRequest(google.protobuf.message.Message):
         ) -> None: ...
         def ClearField(self, field_name: typing_extensions.Literal["create", b"create", "delete", b"delete", "update", b"update"]) -> None: ...
 
+    @typing_extensions.final
+    class RetryPolicy(google.protobuf.message.Message):
+        """RetryPolicy describes how the engine retries a provider operation on a resource that fails with a transient error."""
+
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        MAXATTEMPTS_FIELD_NUMBER: builtins.int
+        BACKOFF_FIELD_NUMBER: builtins.int
+        MAXBACKOFF_FIELD_NUMBER: builtins.int
+        RETRYABLEERRORS_FIELD_NUMBER: builtins.int
+        maxAttempts: builtins.int
+        """the maximum number of attempts, including the first one."""
+        backoff: builtins.str
+        """the delay before the first retry represented as a string e.g. 5s."""
+        maxBackoff: builtins.str
+        """the maximum delay between retries represented as a string e.g. 1m."""
+        @property
+        def retryableErrors(self) -> google.protobuf.internal.containers.RepeatedScalarFieldContainer[builtins.str]:
+            """regular expressions matching the errors to retry; all errors are retried if empty."""
+        def __init__(
+            self,
+            *,
+            maxAttempts: builtins.int = ...,
+            backoff: builtins.str = ...,
+            maxBackoff: builtins.str = ...,
+            retryableErrors: collections.abc.Iterable[builtins.str] | None = ...,
+        ) -> None: ...
+        def ClearField(self, field_name: typing_extensions.Literal["backoff", b"backoff", "maxAttempts", b"maxAttempts", "maxBackoff", b"maxBackoff", "retryableErrors", b"retryableErrors"]) -> None: ...
+
     @typing_extensions.final
     class PropertyDependenciesEntry(google.protobuf.message.Message):
         DESCRIPTOR: google.protobuf.descriptor.Descriptor
@@ -272,6 +301,7 @@ class RegisterResourceRequest(google.protobuf.message.Message):
     RETAINONDELETE_FIELD_NUMBER: builtins.int
     ALIASES_FIELD_NUMBER: builtins.int
     DELETEDWITH_FIELD_NUMBER: builtins.int
+    RETRY_FIELD_NUMBER: builtins.int
     type: builtins.str
     """the type of the object allocated."""
     name: builtins.str
@@ -336,6 +366,9 @@ class RegisterResourceRequest(google.protobuf.message.Message):
         """a list of additional aliases that should be considered the same."""
     deletedWith: builtins.str
     """if set the engine will not call the resource providers delete method for this resource when specified resource is deleted."""
+    @property
+    def retry(self) -> global___RegisterResourceRequest.RetryPolicy:
+        """the retry policy for transient failures of the resource's provider operations."""
     def __init__(
         self,
         *,
@@ -366,9 +399,10 @@ class RegisterResourceRequest(google.protobuf.message.Message):
         retainOnDelete: builtins.bool = ...,
         aliases: collections.abc.Iterable[pulumi.alias_pb2.Alias] | None = ...,
         deletedWith: builtins.str = ...,
+        retry: global___RegisterResourceRequest.RetryPolicy | None = ...,
     ) -> None: ...
-    def HasField(self, field_name: typing_extensions.Literal["customTimeouts", b"customTimeouts", "object", b"object"]) -> builtins.bool: ...
-    def ClearField(self, field_name: typing_extensions.Literal["acceptResources", b"acceptResources", "acceptSecrets", b"acceptSecrets", "additionalSecretOutputs", b"additionalSecretOutputs", "aliasURNs", b"aliasURNs", "aliases", b"aliases", "custom", b"custom", "customTimeouts", b"customTimeouts", "deleteBeforeReplace", b"deleteBeforeReplace", "deleteBeforeReplaceDefined", b"deleteBeforeReplaceDefined", "deletedWith", b"deletedWith", "dependencies", b"dependencies", "ignoreChanges", b"ignoreChanges", "importId", b"importId", "name", b"name", "object", b"object", "parent", b"parent", "pluginDownloadURL", b"pluginDownloadURL", "propertyDependencies", b"propertyDependencies", "protect", b"protect", "provider", b"provider", "providers", b"providers", "remote", b"remote", "replaceOnChanges", b"replaceOnChanges", "retainOnDelete", b"retainOnDelete", "supportsPartialValues", b"supportsPartialValues", "type", b"type", "version", b"version"]) -> None: ...
+    def HasField(self, field_name: typing_extensions.Literal["customTimeouts", b"customTimeouts", "object", b"object", "retry", b"retry"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing_extensions.Literal["acceptResources", b"acceptResources", "acceptSecrets", b"acceptSecrets", "additionalSecretOutputs", b"additionalSecretOutputs", "aliasURNs", b"aliasURNs", "aliases", b"aliases", "custom", b"custom", "customTimeouts", b"customTimeouts", "deleteBeforeReplace", b"deleteBeforeReplace", "deleteBeforeReplaceDefined", b"deleteBeforeReplaceDefined", "deletedWith", b"deletedWith", "dependencies", b"dependencies", "ignoreChanges", b"ignoreChanges", "importId", b"importId", "name", b"name", "object", b"object", "parent", b"parent", "pluginDownloadURL", b"pluginDownloadURL", "propertyDependencies", b"propertyDependencies", "protect", b"protect", "provider", b"provider", "providers", b"providers", "remote", b"remote", "replaceOnChanges", b"replaceOnChanges", "retainOnDelete", b"retainOnDelete", "retry", b"retry", "supportsPartialValues", b"supportsPartialValues", "type", b"type", "version", b"version"]) -> None: ...
 
 global___RegisterResourceRequest = RegisterResourceRequest
 
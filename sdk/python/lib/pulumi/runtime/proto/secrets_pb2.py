@@ -0,0 +1,50 @@
+# -*- coding: utf-8 -*-
+# Generated by the protocol buffer compiler.  DO NOT EDIT!
+# source: pulumi/secrets.proto
+"""Generated protocol buffer code."""
+from google.protobuf.internal import builder as _builder
+from google.protobuf import descriptor as _descriptor
+from google.protobuf import descriptor_pool as _descriptor_pool
+from google.protobuf import symbol_database as _symbol_database
+# @@protoc_insertion_point(imports)
+
+_sym_db = _symbol_database.Default()
+
+
+
+
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x14pulumi/secrets.proto\x12\tpulumirpc\"\x93\x01\n\x18InitializeSecretsRequest\x12;\n\x04\x61rgs\x18\x01 \x03(\x0b\x32-.pulumirpc.InitializeSecretsRequest.ArgsEntry\x12\r\n\x05state\x18\x02 \x01(\t\x1a+\n\tArgsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"*\n\x19InitializeSecretsResponse\x12\r\n\x05state\x18\x01 \x01(\t\"\xa1\x01\n\x15\x45ncryptSecretsRequest\x12\x38\n\x04\x61rgs\x18\x01 \x03(\x0b\x32*.pulumirpc.EncryptSecretsRequest.ArgsEntry\x12\r\n\x05state\x18\x02 \x01(\t\x12\x12\n\nplaintexts\x18\x03 \x03(\t\x1a+\n\tArgsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"-\n\x16\x45ncryptSecretsResponse\x12\x13\n\x0b\x63iphertexts\x18\x01 \x03(\t\"\xa2\x01\n\x15\x44\x65\x63ryptSecretsRequest\x12\x38\n\x04\x61rgs\x18\x01 \x03(\x0b\x32*.pulumirpc.DecryptSecretsRequest.ArgsEntry\x12\r\n\x05state\x18\x02 \x01(\t\x12\x13\n\x0b\x63iphertexts\x18\x03 \x03(\t\x1a+\n\tArgsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\",\n\x16\x44\x65\x63ryptSecretsResponse\x12\x12\n\nplaintexts\x18\x01 \x03(\t2\x8f\x02\n\x0eSecretsManager\x12Y\n\nInitialize\x12#.pulumirpc.InitializeSecretsRequest\x1a$.pulumirpc.InitializeSecretsResponse\"\x00\x12P\n\x07\x45ncrypt\x12 .pulumirpc.EncryptSecretsRequest\x1a!.pulumirpc.EncryptSecretsResponse\"\x00\x12P\n\x07\x44\x65\x63rypt\x12 .pulumirpc.DecryptSecretsRequest\x1a!.pulumirpc.DecryptSecretsResponse\"\x00\x42\x34Z2github.com/pulumi/pulumi/sdk/v3/proto/go;pulumirpcb\x06proto3')
+
+_builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, globals())
+_builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'pulumi.secrets_pb2', globals())
+if _descriptor._USE_C_DESCRIPTORS == False:
+
+  DESCRIPTOR._options = None
+  DESCRIPTOR._serialized_options = b'Z2github.com/pulumi/pulumi/sdk/v3/proto/go;pulumirpc'
+  _INITIALIZESECRETSREQUEST_ARGSENTRY._options = None
+  _INITIALIZESECRETSREQUEST_ARGSENTRY._serialized_options = b'8\001'
+  _ENCRYPTSECRETSREQUEST_ARGSENTRY._options = None
+  _ENCRYPTSECRETSREQUEST_ARGSENTRY._serialized_options = b'8\001'
+  _DECRYPTSECRETSREQUEST_ARGSENTRY._options = None
+  _DECRYPTSECRETSREQUEST_ARGSENTRY._serialized_options = b'8\001'
+  _INITIALIZESECRETSREQUEST._serialized_start=36
+  _INITIALIZESECRETSREQUEST._serialized_end=183
+  _INITIALIZESECRETSREQUEST_ARGSENTRY._serialized_start=140
+  _INITIALIZESECRETSREQUEST_ARGSENTRY._serialized_end=183
+  _INITIALIZESECRETSRESPONSE._serialized_start=185
+  _INITIALIZESECRETSRESPONSE._serialized_end=227
+  _ENCRYPTSECRETSREQUEST._serialized_start=230
+  _ENCRYPTSECRETSREQUEST._serialized_end=391
+  _ENCRYPTSECRETSREQUEST_ARGSENTRY._serialized_start=140
+  _ENCRYPTSECRETSREQUEST_ARGSENTRY._serialized_end=183
+  _ENCRYPTSECRETSRESPONSE._serialized_start=393
+  _ENCRYPTSECRETSRESPONSE._serialized_end=438
+  _DECRYPTSECRETSREQUEST._serialized_start=441
+  _DECRYPTSECRETSREQUEST._serialized_end=603
+  _DECRYPTSECRETSREQUEST_ARGSENTRY._serialized_start=140
+  _DECRYPTSECRETSREQUEST_ARGSENTRY._serialized_end=183
+  _DECRYPTSECRETSRESPONSE._serialized_start=605
+  _DECRYPTSECRETSRESPONSE._serialized_end=649
+  _SECRETSMANAGER._serialized_start=652
+  _SECRETSMANAGER._serialized_end=923
+# @@protoc_insertion_point(module_scope)
@@ -0,0 +1,143 @@
+# Generated by the gRPC Python protocol compiler plugin. DO NOT EDIT!
+"""Client and server classes corresponding to protobuf-defined services."""
+import grpc
+
+from . import secrets_pb2 as pulumi_dot_secrets__pb2
+
+
+class SecretsManagerStub(object):
+    """SecretsManager is a service for encrypting and decrypting the secrets of a stack, e.g. using an external key
+    management service. It is served by secrets plugins, which are selected by `plugin://<name>` secrets providers.
+    """
+
+    def __init__(self, channel):
+        """Constructor.
+
+        Args:
+            channel: A grpc.Channel.
+        """
+        self.Initialize = channel.unary_unary(
+                '/pulumirpc.SecretsManager/Initialize',
+                request_serializer=pulumi_dot_secrets__pb2.InitializeSecretsRequest.SerializeToString,
+                response_deserializer=pulumi_dot_secrets__pb2.InitializeSecretsResponse.FromString,
+                )
+        self.Encrypt = channel.unary_unary(
+                '/pulumirpc.SecretsManager/Encrypt',
+                request_serializer=pulumi_dot_secrets__pb2.EncryptSecretsRequest.SerializeToString,
+                response_deserializer=pulumi_dot_secrets__pb2.EncryptSecretsResponse.FromString,
+                )
+        self.Decrypt = channel.unary_unary(
+                '/pulumirpc.SecretsManager/Decrypt',
+                request_serializer=pulumi_dot_secrets__pb2.DecryptSecretsRequest.SerializeToString,
+                response_deserializer=pulumi_dot_secrets__pb2.DecryptSecretsResponse.FromString,
+                )
+
+
+class SecretsManagerServicer(object):
+    """SecretsManager is a service for encrypting and decrypting the secrets of a stack, e.g. using an external key
+    management service. It is served by secrets plugins, which are selected by `plugin://<name>` secrets providers.
+    """
+
+    def Initialize(self, request, context):
+        """Initialize prepares the secrets manager of a stack from the arguments of its secrets provider URL and the state
+        it previously returned for the stack, if any. It returns the state to persist for the stack, which must not
+        contain any plaintext key material.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Encrypt(self, request, context):
+        """Encrypt encrypts a batch of plaintexts.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Decrypt(self, request, context):
+        """Decrypt decrypts a batch of ciphertexts.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+
+def add_SecretsManagerServicer_to_server(servicer, server):
+    rpc_method_handlers = {
+            'Initialize': grpc.unary_unary_rpc_method_handler(
+                    servicer.Initialize,
+                    request_deserializer=pulumi_dot_secrets__pb2.InitializeSecretsRequest.FromString,
+                    response_serializer=pulumi_dot_secrets__pb2.InitializeSecretsResponse.SerializeToString,
+            ),
+            'Encrypt': grpc.unary_unary_rpc_method_handler(
+                    servicer.Encrypt,
+                    request_deserializer=pulumi_dot_secrets__pb2.EncryptSecretsRequest.FromString,
+                    response_serializer=pulumi_dot_secrets__pb2.EncryptSecretsResponse.SerializeToString,
+            ),
+            'Decrypt': grpc.unary_unary_rpc_method_handler(
+                    servicer.Decrypt,
+                    request_deserializer=pulumi_dot_secrets__pb2.DecryptSecretsRequest.FromString,
+                    response_serializer=pulumi_dot_secrets__pb2.DecryptSecretsResponse.SerializeToString,
+            ),
+    }
+    generic_handler = grpc.method_handlers_generic_handler(
+            'pulumirpc.SecretsManager', rpc_method_handlers)
+    server.add_generic_rpc_handlers((generic_handler,))
+
+
+ # This class is part of an EXPERIMENTAL API.
+class SecretsManager(object):
+    """SecretsManager is a service for encrypting and decrypting the secrets of a stack, e.g. using an external key
+    management service. It is served by secrets plugins, which are selected by `plugin://<name>` secrets providers.
+    """
+
+    @staticmethod
+    def Initialize(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pulumirpc.SecretsManager/Initialize',
+            pulumi_dot_secrets__pb2.InitializeSecretsRequest.SerializeToString,
+            pulumi_dot_secrets__pb2.InitializeSecretsResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def Encrypt(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pulumirpc.SecretsManager/Encrypt',
+            pulumi_dot_secrets__pb2.EncryptSecretsRequest.SerializeToString,
+            pulumi_dot_secrets__pb2.EncryptSecretsResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def Decrypt(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pulumirpc.SecretsManager/Decrypt',
+            pulumi_dot_secrets__pb2.DecryptSecretsRequest.SerializeToString,
+            pulumi_dot_secrets__pb2.DecryptSecretsResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
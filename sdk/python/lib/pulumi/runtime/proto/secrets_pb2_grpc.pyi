@@ -0,0 +1,79 @@
+"""
+@generated by mypy-protobuf.  Do not edit manually!
+isort:skip_file
+Copyright 2016-2023, Pulumi Corporation.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+"""
+import abc
+import grpc
+import grpc.aio
+import typing
+import pulumi.secrets_pb2
+
+class SecretsManagerStub:
+    """SecretsManager is a service for encrypting and decrypting the secrets of a stack, e.g. using an external key
+    management service. It is served by secrets plugins, which are selected by `plugin://<name>` secrets providers.
+    """
+
+    def __init__(self, channel: grpc.Channel) -> None: ...
+    Initialize: grpc.UnaryUnaryMultiCallable[
+        pulumi.secrets_pb2.InitializeSecretsRequest,
+        pulumi.secrets_pb2.InitializeSecretsResponse,
+    ]
+    """Initialize prepares the secrets manager of a stack from the arguments of its secrets provider URL and the state
+    it previously returned for the stack, if any. It returns the state to persist for the stack, which must not
+    contain any plaintext key material.
+    """
+    Encrypt: grpc.UnaryUnaryMultiCallable[
+        pulumi.secrets_pb2.EncryptSecretsRequest,
+        pulumi.secrets_pb2.EncryptSecretsResponse,
+    ]
+    """Encrypt encrypts a batch of plaintexts."""
+    Decrypt: grpc.UnaryUnaryMultiCallable[
+        pulumi.secrets_pb2.DecryptSecretsRequest,
+        pulumi.secrets_pb2.DecryptSecretsResponse,
+    ]
+    """Decrypt decrypts a batch of ciphertexts."""
+
+class SecretsManagerServicer(metaclass=abc.ABCMeta):
+    """SecretsManager is a service for encrypting and decrypting the secrets of a stack, e.g. using an external key
+    management service. It is served by secrets plugins, which are selected by `plugin://<name>` secrets providers.
+    """
+
+    
+    def Initialize(
+        self,
+        request: pulumi.secrets_pb2.InitializeSecretsRequest,
+        context: grpc.ServicerContext,
+    ) -> pulumi.secrets_pb2.InitializeSecretsResponse:
+        """Initialize prepares the secrets manager of a stack from the arguments of its secrets provider URL and the state
+        it previously returned for the stack, if any. It returns the state to persist for the stack, which must not
+        contain any plaintext key material.
+        """
+    
+    def Encrypt(
+        self,
+        request: pulumi.secrets_pb2.EncryptSecretsRequest,
+        context: grpc.ServicerContext,
+    ) -> pulumi.secrets_pb2.EncryptSecretsResponse:
+        """Encrypt encrypts a batch of plaintexts."""
+    
+    def Decrypt(
+        self,
+        request: pulumi.secrets_pb2.DecryptSecretsRequest,
+        context: grpc.ServicerContext,
+    ) -> pulumi.secrets_pb2.DecryptSecretsResponse:
+        """Decrypt decrypts a batch of ciphertexts."""
+
+def add_SecretsManagerServicer_to_server(servicer: SecretsManagerServicer, server: typing.Union[grpc.Server, grpc.aio.Server]) -> None: ...